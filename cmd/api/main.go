@@ -4,13 +4,18 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/murilo-bracero/sequence-technical-test/internal/db"
 	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+	"github.com/murilo-bracero/sequence-technical-test/internal/jobs"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/openapi"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/cache"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/ratelimit"
 	"github.com/murilo-bracero/sequence-technical-test/internal/services"
 )
 
@@ -25,9 +30,37 @@ func main() {
 
 	defer db.Close()
 
+	workspaceRepository := repository.NewWorkspaceRepository(db)
+
+	workspaceService := services.NewWorkspaceService(workspaceRepository)
+
+	workspaceHandler, err := handlers.NewWorkspaceHandler(cfg, workspaceService)
+	if err != nil {
+		slog.Error("failed to create the workspace handler", err.Error(), err)
+		os.Exit(1)
+	}
+
+	apiKeyRepository := repository.NewApiKeyRepository(db)
+
+	apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+	apiKeyHandler := handlers.NewApiKeyHandler(apiKeyService)
+
+	authHandler, err := handlers.NewAuthHandler(context.Background(), cfg, apiKeyService)
+	if err != nil {
+		slog.Error("failed to create the auth handler", err.Error(), err)
+		os.Exit(1)
+	}
+
+	roleBindingRepository := repository.NewRoleBindingRepository(db)
+
+	transactor := repository.NewTransactor(db)
+
+	auditRepository := repository.NewAuditRepository(db)
+
 	sequenceRepository := repository.NewSequenceRepository(db)
 
-	sequenceService := services.NewSequenceService(sequenceRepository)
+	sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, transactor, auditRepository)
 
 	cache, err := cache.New(context.Background(), cfg)
 	if err != nil {
@@ -37,13 +70,83 @@ func main() {
 
 	sequenceHandler := handlers.NewSequenceHandler(cfg, cache, sequenceService)
 
+	go jobs.StartTrashPurge(context.Background(), cfg, workspaceService, sequenceService)
+
 	stepRepository := repository.NewStepRepository(db)
 
-	stepService := services.NewStepService(sequenceRepository, stepRepository)
+	stepService := services.NewStepService(sequenceRepository, stepRepository, roleBindingRepository, transactor, auditRepository)
+
+	stepHandler := handlers.NewStepHandler(cfg, cache, stepService)
+
+	revisionRepository := repository.NewRevisionRepository(db)
+
+	revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, roleBindingRepository, transactor, auditRepository)
+
+	revisionHandler := handlers.NewRevisionHandler(cfg, cache, revisionService)
+
+	previewService := services.NewPreviewService(sequenceRepository, mail.NewTracker(cfg.TrackingBaseURL), roleBindingRepository)
+
+	previewHandler := handlers.NewPreviewHandler(previewService)
+
+	variantRepository := repository.NewVariantRepository(db)
+
+	variantService := services.NewVariantService(stepRepository, variantRepository, roleBindingRepository, transactor, auditRepository)
+
+	variantHandler := handlers.NewVariantHandler(variantService)
+
+	bundleService := services.NewBundleService(sequenceRepository, roleBindingRepository, transactor, auditRepository)
+
+	bundleHandler := handlers.NewBundleHandler(cfg, cache, bundleService)
+
+	roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+	roleBindingHandler := handlers.NewRoleBindingHandler(roleBindingService)
+
+	auditService := services.NewAuditService(auditRepository, roleBindingRepository)
+
+	auditHandler := handlers.NewAuditHandler(cfg, auditService)
+
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
+
+	idempotencyService := services.NewIdempotencyService(time.Duration(cfg.IdempotencyKeyTTL)*time.Hour, idempotencyRepository)
+
+	idempotencyHandler := handlers.NewIdempotencyHandler(idempotencyService)
+
+	go jobs.StartIdempotencyKeyPurge(context.Background(), workspaceService, idempotencyService)
+
+	rateLimitRepository := repository.NewRateLimitRepository(db)
+
+	limiter, err := ratelimit.New(cfg, rateLimitRepository)
+	if err != nil {
+		slog.Error("failed to create the rate limiter", err.Error(), err)
+		os.Exit(1)
+	}
+
+	rateLimitHandler, err := handlers.NewRateLimitHandler(cfg, limiter)
+	if err != nil {
+		slog.Error("failed to create the rate limit handler", err.Error(), err)
+		os.Exit(1)
+	}
+
+	go jobs.StartRateLimitPurge(context.Background(), cfg, limiter)
+
+	openAPIHandler := handlers.NewOpenAPIHandler()
+
+	doc, err := openapi.Load(context.Background())
+	if err != nil {
+		slog.Error("failed to load the OpenAPI document", err.Error(), err)
+		os.Exit(1)
+	}
+
+	validationHandler, err := handlers.NewValidationHandler(cfg, doc)
+	if err != nil {
+		slog.Error("failed to create the validation handler", err.Error(), err)
+		os.Exit(1)
+	}
 
-	stepHandler := handlers.NewStepHandler(cache, stepService)
+	requestIDHandler := handlers.NewRequestIDHandler()
 
-	if err := server.Start(cfg, db, sequenceHandler, stepHandler); err != nil {
+	if err := server.Start(cfg, db, sequenceHandler, stepHandler, revisionHandler, previewHandler, variantHandler, bundleHandler, idempotencyHandler, openAPIHandler, validationHandler, workspaceHandler, apiKeyHandler, roleBindingHandler, auditHandler, authHandler, rateLimitHandler, requestIDHandler); err != nil {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,71 @@
+// Command apikey creates an API key straight in the database, which is how the
+// first key of a workspace is made, as the API only creates keys for requests
+// already authenticated with one.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+func main() {
+	cfg := config.New()
+
+	name := flag.String("name", "admin", "name of the key")
+	scopes := flag.String("scopes", strings.Join(auth.Scopes, ","), "comma separated scopes of the key")
+	workspace := flag.String("workspace", cfg.DefaultWorkspaceID, "id of the workspace of the key")
+	flag.Parse()
+
+	req := dto.CreateApiKeyRequest{Name: *name, Scopes: strings.Split(*scopes, ",")}
+	if err := req.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	workspaceID, err := uuid.Parse(*workspace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "workspace must be a UUID")
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	db, err := db.New(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to connect to database", err.Error(), err)
+		os.Exit(1)
+	}
+
+	defer db.Close()
+
+	workspaceService := services.NewWorkspaceService(repository.NewWorkspaceRepository(db))
+
+	apiKeyService := services.NewApiKeyService(repository.NewApiKeyRepository(db))
+
+	ws, err := workspaceService.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		slog.Error("failed to get the workspace", err.Error(), err)
+		os.Exit(1)
+	}
+
+	key, err := apiKeyService.CreateApiKey(tenancy.WithWorkspace(ctx, ws.ID), req)
+	if err != nil {
+		slog.Error("failed to create the api key", err.Error(), err)
+		os.Exit(1)
+	}
+
+	fmt.Println(key.Key)
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS sequences_deleted_at_idx;
+
+ALTER TABLE steps DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE sequences DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
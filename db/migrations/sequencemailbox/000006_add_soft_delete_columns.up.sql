@@ -0,0 +1,5 @@
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS deleted_at timestamp;
+
+ALTER TABLE steps ADD COLUMN IF NOT EXISTS deleted_at timestamp;
+
+CREATE INDEX IF NOT EXISTS sequences_deleted_at_idx ON sequences(deleted_at);
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS steps_search_idx ON steps USING GIN (to_tsvector('simple', mail_subject || ' ' || coalesce(mail_content, '')));
\ No newline at end of file
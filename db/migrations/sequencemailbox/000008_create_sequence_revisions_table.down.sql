@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sequence_revisions;
\ No newline at end of file
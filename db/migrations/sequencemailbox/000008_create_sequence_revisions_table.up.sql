@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sequence_revisions(
+    id serial primary key,
+    sequence_id integer not null,
+    revision integer not null,
+    snapshot jsonb not null,
+    created timestamp not null default now(),
+    foreign key (sequence_id) references sequences(id) on delete cascade,
+    unique (sequence_id, revision)
+);
+
+GRANT SELECT, INSERT ON TABLE sequence_revisions TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE sequence_revisions_id_seq TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE steps DROP CONSTRAINT IF EXISTS steps_sequence_id_step_number_key;
\ No newline at end of file
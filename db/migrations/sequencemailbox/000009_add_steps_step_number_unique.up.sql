@@ -0,0 +1,10 @@
+-- renumbers the existing steps so every sequence has unique and gapless step numbers
+UPDATE steps 
+SET step_number = ordered.position 
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY sequence_id ORDER BY step_number, id) AS position 
+    FROM steps
+) ordered 
+WHERE steps.id = ordered.id;
+
+ALTER TABLE steps ADD CONSTRAINT steps_sequence_id_step_number_key UNIQUE (sequence_id, step_number) DEFERRABLE INITIALLY IMMEDIATE;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE steps 
+    DROP CONSTRAINT IF EXISTS steps_timing_check,
+    DROP COLUMN IF EXISTS delay_days,
+    DROP COLUMN IF EXISTS delay_hours,
+    DROP COLUMN IF EXISTS business_days_only,
+    DROP COLUMN IF EXISTS send_window_start,
+    DROP COLUMN IF EXISTS send_window_end;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE steps 
+    ADD COLUMN IF NOT EXISTS delay_days integer not null default 0,
+    ADD COLUMN IF NOT EXISTS delay_hours integer not null default 0,
+    ADD COLUMN IF NOT EXISTS business_days_only boolean not null default false,
+    ADD COLUMN IF NOT EXISTS send_window_start integer,
+    ADD COLUMN IF NOT EXISTS send_window_end integer;
+
+-- the send window is stored in minutes after midnight, in the timezone of the contact
+ALTER TABLE steps ADD CONSTRAINT steps_timing_check CHECK (
+    delay_days >= 0 
+    AND delay_hours BETWEEN 0 AND 23 
+    AND (send_window_start IS NULL) = (send_window_end IS NULL) 
+    AND send_window_start >= 0 
+    AND send_window_start < send_window_end 
+    AND send_window_end <= 1440
+);
\ No newline at end of file
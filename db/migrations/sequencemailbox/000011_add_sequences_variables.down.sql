@@ -0,0 +1 @@
+ALTER TABLE sequences DROP COLUMN IF EXISTS variables;
\ No newline at end of file
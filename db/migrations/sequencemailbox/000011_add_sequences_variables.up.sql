@@ -0,0 +1,2 @@
+-- values of the sequence namespace of the step templates, e.g. {{sequence.product}}
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS variables jsonb not null default '{}'::jsonb;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE steps DROP COLUMN IF EXISTS mail_text;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- an empty mail_text means the plain text body is derived from the html body (mail_content)
+ALTER TABLE steps ADD COLUMN IF NOT EXISTS mail_text text not null default '';
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS step_variant_assignments;
+
+DROP TABLE IF EXISTS step_variants;
\ No newline at end of file
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS step_variants(
+    id serial primary key,
+    external_id uuid not null default gen_random_uuid(),
+    step_id integer not null,
+    mail_subject varchar(255) not null,
+    mail_content text not null,
+    weight integer not null,
+    created timestamp not null default now(),
+    foreign key (step_id) references steps(id) on delete cascade,
+    constraint step_variants_weight_check check (weight > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS step_variants_external_id_idx ON step_variants(external_id);
+
+CREATE INDEX IF NOT EXISTS step_variants_step_id_idx ON step_variants(step_id);
+
+-- the variant each enrollment got for a step, kept so the variants can be compared later
+CREATE TABLE IF NOT EXISTS step_variant_assignments(
+    id serial primary key,
+    enrollment_id uuid not null,
+    step_id integer not null,
+    variant_id integer not null,
+    assigned timestamp not null default now(),
+    foreign key (step_id) references steps(id) on delete cascade,
+    foreign key (variant_id) references step_variants(id) on delete cascade,
+    unique (enrollment_id, step_id)
+);
+
+CREATE INDEX IF NOT EXISTS step_variant_assignments_variant_id_idx ON step_variant_assignments(variant_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON TABLE step_variants TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE step_variants_id_seq TO sequenceapi;
+
+GRANT SELECT, INSERT ON TABLE step_variant_assignments TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE step_variant_assignments_id_seq TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- draft -> active <-> paused, any of them -> archived, see sequenceService for the transitions
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS status varchar(16) not null default 'draft'
+    CONSTRAINT sequences_status_check CHECK (status IN ('draft', 'active', 'paused', 'archived'));
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE steps DROP COLUMN IF EXISTS version;
+ALTER TABLE sequences DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- versions back the ETag of the resources, the version of a sequence also changes with its steps
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS version integer not null default 1;
+ALTER TABLE steps ADD COLUMN IF NOT EXISTS version integer not null default 1;
\ No newline at end of file
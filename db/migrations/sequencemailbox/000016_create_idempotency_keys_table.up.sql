@@ -0,0 +1,15 @@
+-- responses of the requests sent with an Idempotency-Key header, replayed when
+-- the same request is sent again with the same key
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    idempotency_key varchar(255) primary key,
+    request_hash char(64) not null,
+    -- null while the first request with the key is still running
+    status_code integer,
+    response_headers jsonb,
+    response_body bytea,
+    created timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_idx ON idempotency_keys(created);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON TABLE idempotency_keys TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sequences DROP COLUMN IF EXISTS source_sequence_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- the sequence a sequence was cloned from, kept as its external id so the lineage
+-- is still known once the source is purged
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS source_sequence_id uuid;
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey, ADD PRIMARY KEY (idempotency_key);
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE step_variant_assignments DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE step_variants DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE sequence_revisions DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE steps DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE sequences DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspaces;
\ No newline at end of file
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS workspaces(
+    id serial primary key,
+    external_id uuid not null default gen_random_uuid(),
+    workspace_name varchar(255) not null,
+    created timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS workspaces_external_id_idx ON workspaces(external_id);
+
+-- the workspace of the rows stored before workspaces existed, also the one of
+-- the requests made without a principal, see DEFAULT_WORKSPACE_ID
+INSERT INTO workspaces (external_id, workspace_name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+ALTER TABLE sequences ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE sequences SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE sequences ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE steps ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE steps SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE steps ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE sequence_revisions ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE sequence_revisions SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE sequence_revisions ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE step_variants ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE step_variants SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE step_variants ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE step_variant_assignments ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE step_variant_assignments SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE step_variant_assignments ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE idempotency_keys SET workspace_id = (SELECT id FROM workspaces WHERE external_id = '00000000-0000-0000-0000-000000000001');
+
+ALTER TABLE idempotency_keys ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS sequences_workspace_id_idx ON sequences(workspace_id);
+
+-- keys are only unique within a workspace
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey, ADD PRIMARY KEY (workspace_id, idempotency_key);
+
+GRANT SELECT ON TABLE workspaces TO sequenceapi;
\ No newline at end of file
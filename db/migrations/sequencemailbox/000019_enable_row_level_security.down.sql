@@ -0,0 +1,23 @@
+DROP POLICY IF EXISTS idempotency_keys_workspace_isolation ON idempotency_keys;
+
+ALTER TABLE idempotency_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS step_variant_assignments_workspace_isolation ON step_variant_assignments;
+
+ALTER TABLE step_variant_assignments DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS step_variants_workspace_isolation ON step_variants;
+
+ALTER TABLE step_variants DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS sequence_revisions_workspace_isolation ON sequence_revisions;
+
+ALTER TABLE sequence_revisions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS steps_workspace_isolation ON steps;
+
+ALTER TABLE steps DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS sequences_workspace_isolation ON sequences;
+
+ALTER TABLE sequences DISABLE ROW LEVEL SECURITY;
\ No newline at end of file
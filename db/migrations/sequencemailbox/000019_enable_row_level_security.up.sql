@@ -0,0 +1,32 @@
+-- second line of defence behind the workspace filters of the queries, the api
+-- only sees the rows of the workspace set in app.workspace_id by db.New. Table
+-- owners, such as the user running the migrations, are not subject to it.
+ALTER TABLE sequences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY sequences_workspace_isolation ON sequences 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+ALTER TABLE steps ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY steps_workspace_isolation ON steps 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+ALTER TABLE sequence_revisions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY sequence_revisions_workspace_isolation ON sequence_revisions 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+ALTER TABLE step_variants ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY step_variants_workspace_isolation ON step_variants 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+ALTER TABLE step_variant_assignments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY step_variant_assignments_workspace_isolation ON step_variant_assignments 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY idempotency_keys_workspace_isolation ON idempotency_keys 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS api_keys(
+    id serial primary key,
+    external_id uuid not null default gen_random_uuid(),
+    workspace_id integer not null references workspaces(id) on delete cascade,
+    key_name varchar(255) not null,
+    key_prefix varchar(16) not null,
+    key_hash varchar(64) not null,
+    scopes text[] not null,
+    created timestamp not null default now(),
+    last_used_at timestamp,
+    revoked_at timestamp
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_external_id_idx ON api_keys(external_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_key_prefix_idx ON api_keys(key_prefix);
+
+CREATE INDEX IF NOT EXISTS api_keys_workspace_id_idx ON api_keys(workspace_id);
+
+-- no row level security here, keys are looked up by prefix before the
+-- workspace of the request is known
+GRANT SELECT, INSERT, UPDATE ON TABLE api_keys TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE api_keys_id_seq TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS role_bindings;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS role_bindings(
+    id serial primary key,
+    external_id uuid not null default gen_random_uuid(),
+    workspace_id integer not null references workspaces(id) on delete cascade,
+    user_id varchar(255) not null,
+    role varchar(16) not null check (role in ('viewer', 'editor', 'admin')),
+    sequence_id integer references sequences(id) on delete cascade,
+    created timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS role_bindings_external_id_idx ON role_bindings(external_id);
+
+-- a user has a single role in the workspace and in each sequence shared with
+-- them, the workspace binding being the one without sequence
+CREATE UNIQUE INDEX IF NOT EXISTS role_bindings_user_id_idx ON role_bindings(workspace_id, user_id, sequence_id) NULLS NOT DISTINCT;
+
+ALTER TABLE role_bindings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY role_bindings_workspace_isolation ON role_bindings 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON TABLE role_bindings TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE role_bindings_id_seq TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets(
+    bucket_key varchar(255) primary key,
+    tokens double precision not null,
+    allowed boolean not null,
+    updated timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_idx ON rate_limit_buckets(updated);
+
+-- no row level security here, the buckets belong to the clients of the API,
+-- which are counted before the workspace of the request is known
+GRANT SELECT, INSERT, UPDATE, DELETE ON TABLE rate_limit_buckets TO sequenceapi;
\ No newline at end of file
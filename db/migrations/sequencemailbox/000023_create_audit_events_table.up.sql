@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS audit_events(
+    id serial primary key,
+    external_id uuid not null default gen_random_uuid(),
+    workspace_id integer not null references workspaces(id) on delete cascade,
+    actor varchar(255) not null,
+    action varchar(32) not null,
+    target_type varchar(32) not null,
+    target_id uuid not null,
+    before jsonb,
+    after jsonb,
+    request_id varchar(128),
+    created timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS audit_events_external_id_idx ON audit_events(external_id);
+
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events(workspace_id, target_type, target_id);
+
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY audit_events_workspace_isolation ON audit_events 
+    USING (workspace_id = nullif(current_setting('app.workspace_id', true), '')::integer);
+
+-- the audit log is append only, the api may not rewrite the history
+GRANT SELECT, INSERT ON TABLE audit_events TO sequenceapi;
+
+GRANT USAGE ON SEQUENCE audit_events_id_seq TO sequenceapi;
\ No newline at end of file
@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS step_variant_assignments_enrollment_id_step_id_idx;
+
+DELETE FROM step_variant_assignments WHERE step_id IS NULL OR variant_id IS NULL;
+
+ALTER TABLE step_variant_assignments DROP CONSTRAINT IF EXISTS step_variant_assignments_variant_id_fkey;
+
+ALTER TABLE step_variant_assignments ADD CONSTRAINT step_variant_assignments_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES step_variants(id) ON DELETE CASCADE;
+
+ALTER TABLE step_variant_assignments DROP CONSTRAINT IF EXISTS step_variant_assignments_step_id_fkey;
+
+ALTER TABLE step_variant_assignments ADD CONSTRAINT step_variant_assignments_step_id_fkey FOREIGN KEY (step_id) REFERENCES steps(id) ON DELETE CASCADE;
+
+ALTER TABLE step_variant_assignments ALTER COLUMN variant_id SET NOT NULL;
+
+ALTER TABLE step_variant_assignments ADD CONSTRAINT step_variant_assignments_enrollment_id_step_id_key UNIQUE (enrollment_id, step_id);
+
+ALTER TABLE step_variant_assignments ALTER COLUMN step_id SET NOT NULL;
+
+ALTER TABLE step_variant_assignments DROP COLUMN IF EXISTS variant_external_id;
+
+ALTER TABLE step_variant_assignments DROP COLUMN IF EXISTS step_external_id;
\ No newline at end of file
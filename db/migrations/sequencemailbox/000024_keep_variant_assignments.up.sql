@@ -0,0 +1,29 @@
+-- the assignments outlive their variants and steps, so deleting them does not erase the history of the variants
+ALTER TABLE step_variant_assignments ADD COLUMN IF NOT EXISTS step_external_id uuid;
+
+UPDATE step_variant_assignments a SET step_external_id = s.external_id FROM steps s WHERE s.id = a.step_id;
+
+ALTER TABLE step_variant_assignments ALTER COLUMN step_external_id SET NOT NULL;
+
+ALTER TABLE step_variant_assignments ADD COLUMN IF NOT EXISTS variant_external_id uuid;
+
+UPDATE step_variant_assignments a SET variant_external_id = v.external_id FROM step_variants v WHERE v.id = a.variant_id;
+
+ALTER TABLE step_variant_assignments ALTER COLUMN variant_external_id SET NOT NULL;
+
+ALTER TABLE step_variant_assignments ALTER COLUMN step_id DROP NOT NULL;
+
+ALTER TABLE step_variant_assignments ALTER COLUMN variant_id DROP NOT NULL;
+
+ALTER TABLE step_variant_assignments DROP CONSTRAINT IF EXISTS step_variant_assignments_step_id_fkey;
+
+ALTER TABLE step_variant_assignments ADD CONSTRAINT step_variant_assignments_step_id_fkey FOREIGN KEY (step_id) REFERENCES steps(id) ON DELETE SET NULL;
+
+ALTER TABLE step_variant_assignments DROP CONSTRAINT IF EXISTS step_variant_assignments_variant_id_fkey;
+
+ALTER TABLE step_variant_assignments ADD CONSTRAINT step_variant_assignments_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES step_variants(id) ON DELETE SET NULL;
+
+-- an enrollment whose variant was deleted gets another one, the assignment of the deleted variant is kept
+ALTER TABLE step_variant_assignments DROP CONSTRAINT IF EXISTS step_variant_assignments_enrollment_id_step_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS step_variant_assignments_enrollment_id_step_id_idx ON step_variant_assignments(enrollment_id, step_id) WHERE variant_id IS NOT NULL;
\ No newline at end of file
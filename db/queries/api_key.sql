@@ -0,0 +1,31 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (workspace_id, key_name, key_prefix, key_hash, scopes) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING *;
+
+-- name: GetApiKeyByPrefix :one
+SELECT k.*, w.external_id workspace_external_id FROM api_keys k
+JOIN workspaces w ON w.id = k.workspace_id
+WHERE k.key_prefix = $1 AND k.revoked_at IS NULL;
+
+-- name: GetApiKeys :many
+SELECT * FROM api_keys 
+WHERE workspace_id = $1 
+ORDER BY id;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys 
+SET revoked_at = now() 
+WHERE external_id = $1 AND workspace_id = $2 AND revoked_at IS NULL 
+RETURNING *;
+
+-- name: RotateApiKey :one
+UPDATE api_keys 
+SET key_prefix = $3, key_hash = $4, last_used_at = NULL 
+WHERE external_id = $1 AND workspace_id = $2 AND revoked_at IS NULL 
+RETURNING *;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys 
+SET last_used_at = now() 
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2);
\ No newline at end of file
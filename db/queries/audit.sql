@@ -0,0 +1,18 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (workspace_id, actor, action, target_type, target_id, before, after, request_id) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+RETURNING *;
+
+-- name: GetAuditEventsPage :many
+SELECT * FROM audit_events 
+WHERE workspace_id = @workspace_id 
+    AND (sqlc.narg('actor')::varchar IS NULL OR actor = sqlc.narg('actor')::varchar) 
+    AND (sqlc.narg('action')::varchar IS NULL OR action = sqlc.narg('action')::varchar) 
+    AND (sqlc.narg('target_type')::varchar IS NULL OR target_type = sqlc.narg('target_type')::varchar) 
+    AND (sqlc.narg('target_id')::uuid IS NULL OR target_id = sqlc.narg('target_id')::uuid) 
+    AND (sqlc.narg('request_id')::varchar IS NULL OR request_id = sqlc.narg('request_id')::varchar) 
+    AND (sqlc.narg('created_after')::timestamp IS NULL OR created >= sqlc.narg('created_after')::timestamp) 
+    AND (sqlc.narg('created_before')::timestamp IS NULL OR created < sqlc.narg('created_before')::timestamp) 
+    AND (sqlc.narg('cursor_id')::integer IS NULL OR id < sqlc.narg('cursor_id')::integer) 
+ORDER BY id DESC 
+LIMIT @page_limit;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- name: ReserveIdempotencyKey :one
+INSERT INTO idempotency_keys (idempotency_key, request_hash, workspace_id) 
+VALUES (@idempotency_key, @request_hash, @workspace_id) 
+ON CONFLICT (workspace_id, idempotency_key) DO UPDATE 
+SET request_hash = excluded.request_hash, status_code = NULL, response_headers = NULL, response_body = NULL, created = now() 
+WHERE idempotency_keys.created < @expired_before 
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys 
+WHERE idempotency_key = $1 AND workspace_id = $2;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys 
+SET status_code = $2, response_headers = $3, response_body = $4 
+WHERE idempotency_key = $1 AND workspace_id = $5;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys 
+WHERE idempotency_key = $1 AND workspace_id = $2;
+
+-- name: PurgeIdempotencyKeys :execrows
+DELETE FROM idempotency_keys 
+WHERE workspace_id = $1 AND created < $2;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- name: TakeRateLimitTokens :one
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated) 
+VALUES (@bucket_key, @burst::float8 - @cost::float8, true, now()) 
+ON CONFLICT (bucket_key) DO UPDATE SET 
+    tokens = least(@burst::float8, b.tokens + extract(epoch FROM now() - b.updated)::float8 * @rate::float8) 
+        - CASE WHEN least(@burst::float8, b.tokens + extract(epoch FROM now() - b.updated)::float8 * @rate::float8) >= @cost::float8 THEN @cost::float8 ELSE 0 END, 
+    allowed = least(@burst::float8, b.tokens + extract(epoch FROM now() - b.updated)::float8 * @rate::float8) >= @cost::float8, 
+    updated = now() 
+RETURNING tokens, allowed;
+
+-- name: PurgeRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets 
+WHERE updated < now() - make_interval(secs => @fill_seconds::float8);
@@ -0,0 +1,17 @@
+-- name: CreateSequenceRevision :one
+INSERT INTO sequence_revisions (sequence_id, revision, snapshot, workspace_id) 
+VALUES ($1, (SELECT coalesce(max(revision), 0) + 1 FROM sequence_revisions WHERE sequence_id = $1 AND workspace_id = $3), $2, $3) 
+RETURNING *;
+
+-- name: GetSequenceRevisions :many
+SELECT r.* FROM sequence_revisions r
+JOIN sequences s ON s.id = r.sequence_id AND s.external_id = $1 AND s.deleted_at IS NULL
+WHERE r.workspace_id = $4
+ORDER BY r.revision DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetSequenceRevision :one
+SELECT r.* FROM sequence_revisions r
+JOIN sequences s ON s.id = r.sequence_id AND s.external_id = $1 AND s.deleted_at IS NULL
+WHERE r.revision = $2 AND r.workspace_id = $3;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- name: SaveRoleBinding :one
+INSERT INTO role_bindings (workspace_id, user_id, role, sequence_id) 
+VALUES ($1, $2, $3, $4) 
+ON CONFLICT (workspace_id, user_id, sequence_id) DO UPDATE SET role = EXCLUDED.role 
+RETURNING *;
+
+-- name: GetRoleBindings :many
+SELECT b.*, s.external_id sequence_external_id FROM role_bindings b
+LEFT JOIN sequences s ON s.id = b.sequence_id
+WHERE b.workspace_id = $1
+ORDER BY b.id;
+
+-- name: DeleteRoleBinding :execrows
+DELETE FROM role_bindings 
+WHERE external_id = $1 AND workspace_id = $2;
+
+-- name: GetUserRoles :many
+SELECT role FROM role_bindings 
+WHERE workspace_id = @workspace_id AND user_id = @user_id AND (
+    sequence_id IS NULL 
+    OR sequence_id = (SELECT id FROM sequences WHERE external_id = sqlc.narg('sequence_id')::uuid AND workspace_id = @workspace_id) 
+    OR sequence_id = (SELECT steps.sequence_id FROM steps WHERE steps.external_id = sqlc.narg('step_id')::uuid AND steps.workspace_id = @workspace_id)
+);
\ No newline at end of file
@@ -3,7 +3,8 @@ select
     s.*, 
     json_agg(row_to_json(t))::jsonb steps 
 from sequences s
-left join steps t on t.sequence_id = s.id
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.workspace_id = $1 and s.deleted_at is null
 group by
 	s.id,
 	s.external_id,
@@ -11,18 +12,226 @@ group by
 	s.open_tracking_enabled,
 	s.click_tracking_enabled,
 	s.created,
-	s.updated
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
 order by s.id
-limit $1
-offset $2;
+limit $2
+offset $3;
+
+-- name: GetSequencesPage :many
+with filtered as (
+	select 
+		s.*, 
+		count(t.id)::integer step_count,
+		coalesce(s.updated, s.created)::timestamp last_modified,
+		json_agg(row_to_json(t))::jsonb steps 
+	from sequences s
+	left join steps t on t.sequence_id = s.id and t.deleted_at is null
+	where s.workspace_id = @workspace_id and s.deleted_at is null
+		and (sqlc.narg('name')::varchar is null or s.sequence_name ilike '%' || sqlc.narg('name')::varchar || '%')
+		and (sqlc.narg('open_tracking_enabled')::boolean is null or s.open_tracking_enabled = sqlc.narg('open_tracking_enabled')::boolean)
+		and (sqlc.narg('click_tracking_enabled')::boolean is null or s.click_tracking_enabled = sqlc.narg('click_tracking_enabled')::boolean)
+		and (sqlc.narg('created_after')::timestamp is null or s.created >= sqlc.narg('created_after')::timestamp)
+		and (sqlc.narg('created_before')::timestamp is null or s.created < sqlc.narg('created_before')::timestamp)
+		and (sqlc.narg('updated_after')::timestamp is null or s.updated >= sqlc.narg('updated_after')::timestamp)
+		and (sqlc.narg('updated_before')::timestamp is null or s.updated < sqlc.narg('updated_before')::timestamp)
+		and (
+			sqlc.narg('search')::varchar is null
+			or exists (
+				select 1 from steps fs
+				where fs.sequence_id = s.id
+					and fs.deleted_at is null
+					and to_tsvector('simple', fs.mail_subject || ' ' || coalesce(fs.mail_content, '')) @@ websearch_to_tsquery('simple', sqlc.narg('search')::varchar)
+			)
+		)
+	group by
+		s.id,
+		s.external_id,
+		s.sequence_name,
+		s.open_tracking_enabled,
+		s.click_tracking_enabled,
+		s.created,
+		s.updated,
+		s.deleted_at,
+		s.variables,
+		s.status,
+		s.version,
+		s.source_sequence_id,
+		s.workspace_id
+)
+select 
+	id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id, steps
+from filtered
+where
+	sqlc.narg('cursor_id')::integer is null
+	or (@sort_by::text = 'name' and @after::boolean and (sequence_name, id) > (sqlc.narg('cursor_name')::varchar, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'name' and not @after::boolean and (sequence_name, id) < (sqlc.narg('cursor_name')::varchar, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'created' and @after::boolean and (created, id) > (sqlc.narg('cursor_time')::timestamp, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'created' and not @after::boolean and (created, id) < (sqlc.narg('cursor_time')::timestamp, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'updated' and @after::boolean and (last_modified, id) > (sqlc.narg('cursor_time')::timestamp, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'updated' and not @after::boolean and (last_modified, id) < (sqlc.narg('cursor_time')::timestamp, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'stepCount' and @after::boolean and (step_count, id) > (sqlc.narg('cursor_count')::integer, sqlc.narg('cursor_id')::integer))
+	or (@sort_by::text = 'stepCount' and not @after::boolean and (step_count, id) < (sqlc.narg('cursor_count')::integer, sqlc.narg('cursor_id')::integer))
+order by
+	case when @sort_by::text = 'name' and @after::boolean then sequence_name end,
+	case when @sort_by::text = 'name' and not @after::boolean then sequence_name end desc,
+	case when @sort_by::text = 'created' and @after::boolean then created end,
+	case when @sort_by::text = 'created' and not @after::boolean then created end desc,
+	case when @sort_by::text = 'updated' and @after::boolean then last_modified end,
+	case when @sort_by::text = 'updated' and not @after::boolean then last_modified end desc,
+	case when @sort_by::text = 'stepCount' and @after::boolean then step_count end,
+	case when @sort_by::text = 'stepCount' and not @after::boolean then step_count end desc,
+	case when @after::boolean then id end,
+	case when not @after::boolean then id end desc
+limit @page_limit;
+
+-- name: CountSequences :one
+select count(*) from sequences s
+where s.workspace_id = @workspace_id and s.deleted_at is null
+		and (sqlc.narg('name')::varchar is null or s.sequence_name ilike '%' || sqlc.narg('name')::varchar || '%')
+		and (sqlc.narg('open_tracking_enabled')::boolean is null or s.open_tracking_enabled = sqlc.narg('open_tracking_enabled')::boolean)
+		and (sqlc.narg('click_tracking_enabled')::boolean is null or s.click_tracking_enabled = sqlc.narg('click_tracking_enabled')::boolean)
+		and (sqlc.narg('created_after')::timestamp is null or s.created >= sqlc.narg('created_after')::timestamp)
+		and (sqlc.narg('created_before')::timestamp is null or s.created < sqlc.narg('created_before')::timestamp)
+		and (sqlc.narg('updated_after')::timestamp is null or s.updated >= sqlc.narg('updated_after')::timestamp)
+		and (sqlc.narg('updated_before')::timestamp is null or s.updated < sqlc.narg('updated_before')::timestamp)
+		and (
+			sqlc.narg('search')::varchar is null
+			or exists (
+				select 1 from steps fs
+				where fs.sequence_id = s.id
+					and fs.deleted_at is null
+					and to_tsvector('simple', fs.mail_subject || ' ' || coalesce(fs.mail_content, '')) @@ websearch_to_tsquery('simple', sqlc.narg('search')::varchar)
+			)
+		);
 
 -- name: GetSequenceById :one
 select 
     s.*, 
 	json_agg(row_to_json(t))::jsonb steps 
 from sequences s
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.external_id = $1 and s.workspace_id = $2 and s.deleted_at is null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id;
+
+-- name: GetSequencesByExternalIds :many
+select 
+    s.*, 
+	json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.external_id = any(@external_ids::uuid[]) and s.workspace_id = @workspace_id and s.deleted_at is null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.id;
+
+-- name: GetSequencesByNames :many
+select 
+    s.*, 
+	json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.sequence_name = any(@names::text[]) and s.workspace_id = @workspace_id and s.deleted_at is null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.id;
+
+-- name: GetDeletedSequenceById :one
+select 
+    s.*, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id
+where s.external_id = $1 and s.workspace_id = $2 and s.deleted_at is not null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id;
+
+-- name: GetDeletedSequences :many
+select 
+    s.*, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id
+where s.workspace_id = $1 and s.deleted_at is not null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.deleted_at desc, s.id
+limit $2
+offset $3;
+
+-- name: GetExpiredSequences :many
+select 
+    s.*, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
 left join steps t on t.sequence_id = s.id
-where s.external_id = $1
+where s.workspace_id = @workspace_id and s.deleted_at is not null and s.deleted_at < now() - make_interval(secs => @retention_seconds::float8)
 group by
 	s.id,
 	s.external_id,
@@ -30,19 +239,77 @@ group by
 	s.open_tracking_enabled,
 	s.click_tracking_enabled,
 	s.created,
-	s.updated;
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.deleted_at, s.id;
 
 -- name: CreateSequence :one
-INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled) 
-VALUES ($1, $2, $3) 
+INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled, variables, workspace_id) 
+VALUES ($1, $2, $3, $4, $5) 
 RETURNING *;
 
+-- name: CloneSequence :one
+INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled, variables, source_sequence_id, workspace_id) 
+SELECT @sequence_name::varchar, @open_tracking_enabled::boolean, @click_tracking_enabled::boolean, variables, external_id, workspace_id FROM sequences 
+WHERE id = @source_id AND workspace_id = @workspace_id 
+RETURNING *;
+
+-- name: GetSequenceForUpdate :one
+SELECT * FROM sequences 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+FOR UPDATE;
+
+-- name: LockSequence :one
+SELECT * FROM sequences 
+WHERE id = $1 AND workspace_id = $2 
+FOR UPDATE;
+
 -- name: UpdateSequence :one
 UPDATE sequences 
-SET open_tracking_enabled = $2, click_tracking_enabled = $3 
-WHERE id = $1 
+SET sequence_name = $2, open_tracking_enabled = $3, click_tracking_enabled = $4, variables = $5, version = version + 1 
+WHERE id = $1 AND version = $6 AND workspace_id = $7 
 RETURNING *;
 
--- name: DeleteSequence :exec
+-- name: IncrementSequenceVersion :one
+UPDATE sequences 
+SET version = version + 1 
+WHERE id = $1 AND workspace_id = $2 
+RETURNING *;
+
+-- name: UpdateSequenceStatus :one
+UPDATE sequences 
+SET status = @to_status, version = version + 1 
+WHERE external_id = @external_id AND status = @from_status AND workspace_id = @workspace_id AND deleted_at IS NULL 
+RETURNING *;
+
+-- name: DeleteSequence :one
+UPDATE sequences 
+SET deleted_at = now() 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+RETURNING *;
+
+-- name: DeleteSequenceSteps :exec
+UPDATE steps 
+SET deleted_at = $2 
+WHERE sequence_id = $1 AND workspace_id = $3 AND deleted_at IS NULL;
+
+-- name: RestoreSequence :one
+UPDATE sequences 
+SET deleted_at = NULL 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL 
+RETURNING *;
+
+-- name: RestoreSequenceSteps :exec
+UPDATE steps 
+SET deleted_at = NULL 
+WHERE sequence_id = $1 AND workspace_id = $2;
+
+-- name: PurgeDeletedSequences :many
 DELETE FROM sequences 
-WHERE external_id = $1;
\ No newline at end of file
+WHERE workspace_id = @workspace_id AND deleted_at IS NOT NULL AND external_id = ANY(@external_ids::uuid[]) 
+RETURNING external_id;
\ No newline at end of file
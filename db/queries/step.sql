@@ -1,23 +1,66 @@
 -- name: CreateSteps :copyfrom
-INSERT INTO steps (external_id, step_number, mail_subject, mail_content, sequence_id) 
-VALUES ($1, $2, $3, $4, $5);
+INSERT INTO steps (external_id, step_number, mail_subject, mail_content, sequence_id, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 
 -- name: CreateStep :one
-INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id) 
-VALUES ($1, $2, $3, $4)
+INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
+-- name: CloneSteps :execrows
+INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id) 
+SELECT step_number, mail_subject, mail_content, @target_id::integer, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id FROM steps 
+WHERE sequence_id = @source_id AND workspace_id = @workspace_id AND deleted_at IS NULL;
+
 -- name: GetStepById :one
 SELECT steps.* FROM steps
-JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $2
-WHERE steps.external_id = $1;
+JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $2 AND sequences.deleted_at IS NULL
+WHERE steps.external_id = $1 AND steps.workspace_id = $3 AND steps.deleted_at IS NULL;
+
+-- name: GetSequenceSteps :many
+SELECT * FROM steps 
+WHERE sequence_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+ORDER BY step_number;
+
+-- name: GetSequenceStepsPage :many
+SELECT steps.* FROM steps
+JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $1 AND sequences.deleted_at IS NULL
+WHERE steps.workspace_id = $4 AND steps.deleted_at IS NULL AND steps.step_number > $2
+ORDER BY steps.step_number
+LIMIT $3;
+
+-- name: LockStepSequence :one
+SELECT sequences.id FROM sequences
+JOIN steps ON steps.sequence_id = sequences.id
+WHERE steps.external_id = $1 AND steps.workspace_id = $2 AND steps.deleted_at IS NULL
+FOR UPDATE OF sequences;
 
 -- name: UpdateStep :one
 UPDATE steps 
-SET mail_subject = $2, mail_content = $3 , step_number = $4
-WHERE external_id = $1 
+SET mail_subject = $2, mail_content = $3 , step_number = $4, delay_days = $5, delay_hours = $6, business_days_only = $7, send_window_start = $8, send_window_end = $9, mail_text = $10, version = version + 1
+WHERE external_id = $1 AND version = $11 AND workspace_id = $12 
 RETURNING *;
 
--- name: DeleteStep :exec
+-- name: DeleteStep :one
 DELETE FROM steps 
-WHERE external_id = $1;
+WHERE external_id = $1 AND workspace_id = $2 
+RETURNING *;
+
+-- name: ShiftSteps :exec
+UPDATE steps 
+SET step_number = step_number + 1, version = version + 1 
+WHERE sequence_id = $1 AND step_number >= $2 AND workspace_id = $3 AND deleted_at IS NULL;
+
+-- name: CloseStepGap :exec
+UPDATE steps 
+SET step_number = step_number - 1, version = version + 1 
+WHERE sequence_id = $1 AND step_number > $2 AND workspace_id = $3 AND deleted_at IS NULL;
+
+-- name: ReorderSteps :execrows
+UPDATE steps 
+SET step_number = ordered.position, version = version + 1 
+FROM unnest(@step_ids::uuid[]) WITH ORDINALITY AS ordered(external_id, position) 
+WHERE steps.external_id = ordered.external_id AND steps.sequence_id = @sequence_id AND steps.workspace_id = @workspace_id AND steps.deleted_at IS NULL;
+
+-- name: DeferStepNumbers :exec
+SET CONSTRAINTS steps_sequence_id_step_number_key DEFERRED;
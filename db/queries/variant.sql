@@ -0,0 +1,58 @@
+-- name: CreateStepVariant :one
+INSERT INTO step_variants (step_id, mail_subject, mail_content, weight, workspace_id) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING *;
+
+-- name: RestoreStepVariant :one
+INSERT INTO step_variants (external_id, step_id, mail_subject, mail_content, weight, workspace_id) 
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING *;
+
+-- name: GetStepVariants :many
+SELECT v.*, count(a.id) AS assignments FROM step_variants v
+LEFT JOIN step_variant_assignments a ON a.variant_id = v.id
+WHERE v.step_id = $1 AND v.workspace_id = $2
+GROUP BY v.id
+ORDER BY v.id;
+
+-- name: GetStepVariant :one
+SELECT v.*, count(a.id) AS assignments FROM step_variants v
+LEFT JOIN step_variant_assignments a ON a.variant_id = v.id
+WHERE v.step_id = $1 AND v.external_id = $2 AND v.workspace_id = $3
+GROUP BY v.id;
+
+-- name: GetSequenceVariants :many
+SELECT v.* FROM step_variants v
+JOIN steps s ON s.id = v.step_id AND s.sequence_id = $1 AND s.deleted_at IS NULL
+WHERE v.workspace_id = $2
+ORDER BY v.id;
+
+-- name: UpdateStepVariant :one
+UPDATE step_variants 
+SET mail_subject = $3, mail_content = $4, weight = $5 
+WHERE step_id = $1 AND external_id = $2 AND workspace_id = $6 
+RETURNING *;
+
+-- name: DeleteStepVariant :execrows
+DELETE FROM step_variants 
+WHERE step_id = $1 AND external_id = $2 AND workspace_id = $3;
+
+-- name: GetVariantAssignment :one
+SELECT v.* FROM step_variant_assignments a
+JOIN step_variants v ON v.id = a.variant_id
+WHERE a.step_id = $1 AND a.enrollment_id = $2 AND a.workspace_id = $3;
+
+-- name: CreateVariantAssignment :exec
+INSERT INTO step_variant_assignments (enrollment_id, step_id, variant_id, step_external_id, variant_external_id, workspace_id) 
+SELECT @enrollment_id::uuid, s.id, v.id, s.external_id, v.external_id, v.workspace_id FROM step_variants v
+JOIN steps s ON s.id = v.step_id
+WHERE v.id = @variant_id AND v.step_id = @step_id AND v.workspace_id = @workspace_id
+ON CONFLICT (enrollment_id, step_id) WHERE variant_id IS NOT NULL DO NOTHING;
+
+-- name: CloneStepVariants :execrows
+INSERT INTO step_variants (step_id, mail_subject, mail_content, weight, workspace_id) 
+SELECT t.id, v.mail_subject, v.mail_content, v.weight, v.workspace_id FROM step_variants v
+JOIN steps s ON s.id = v.step_id AND s.sequence_id = @source_id AND s.deleted_at IS NULL
+JOIN steps t ON t.sequence_id = @target_id AND t.step_number = s.step_number AND t.deleted_at IS NULL
+WHERE v.workspace_id = @workspace_id
+ORDER BY v.id;
\ No newline at end of file
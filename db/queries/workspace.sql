@@ -0,0 +1,7 @@
+-- name: GetWorkspaces :many
+SELECT * FROM workspaces 
+ORDER BY id;
+
+-- name: GetWorkspaceById :one
+SELECT * FROM workspaces 
+WHERE external_id = $1;
\ No newline at end of file
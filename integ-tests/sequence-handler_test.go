@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	integtests "github.com/murilo-bracero/sequence-technical-test/integ-tests"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/stretchr/testify/assert"
@@ -107,19 +112,102 @@ func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequences() {
 	assert.NoError(t, err)
 	assert.Equal(t, 200, res.StatusCode)
 
+	var body dto.SequencePageResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, body.Items, 1)
+
+	assert.Equal(t, "My Sequence 982", body.Items[0].Name)
+	assert.Equal(t, false, body.Items[0].OpenTrackingEnabled)
+	assert.Equal(t, true, body.Items[0].ClickTrackingEnabled)
+	assert.Len(t, body.Items[0].Steps, 1)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequences_Cursor() {
+	t := s.T()
+
+	req, err := http.NewRequest("GET", "http://localhost:8000/sequences?limit=1&includeTotalCount=true", nil)
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Contains(t, res.Header.Get("Link"), `rel="first"`)
+
+	var body dto.SequencePageResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, body.Items, 1)
+	assert.Nil(t, body.NextCursor)
+	assert.Nil(t, body.PrevCursor)
+	assert.Equal(t, int64(1), *body.TotalCount)
+
+	req, err = http.NewRequest("GET", "http://localhost:8000/sequences?cursor=invalid!", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequences_Deprecated() {
+	t := s.T()
+
+	req, err := http.NewRequest("GET", "http://localhost:8000/sequences?page=0&size=10", nil)
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "true", res.Header.Get("Deprecation"))
+
 	var body []dto.SequenceResponse
 	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
 		t.Fatal(err)
 	}
 
 	assert.Len(t, body, 1)
-
-	assert.Equal(t, "My Sequence 982", body[0].Name)
-	assert.Equal(t, false, body[0].OpenTrackingEnabled)
-	assert.Equal(t, true, body[0].ClickTrackingEnabled)
-	assert.Len(t, body[0].Steps, 1)
 }
 
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequences_Filter() {
+	t := s.T()
+
+	req, err := http.NewRequest("GET", "http://localhost:8000/sequences?name=does-not-exist&sort=name:desc&includeTotalCount=true", nil)
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	var body dto.SequencePageResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, body.Items)
+	assert.Equal(t, int64(0), *body.TotalCount)
+
+	req, err = http.NewRequest("GET", "http://localhost:8000/sequences?sort=mailSubject", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+}
 func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequence() {
 	t := s.T()
 
@@ -134,14 +222,14 @@ func (s *SequenceHandlerTestSuite) TestSequenceHandler_GetSequence() {
 	assert.NoError(t, err)
 	assert.Equal(t, 200, res.StatusCode)
 
-	var body []dto.SequenceResponse
+	var body dto.SequencePageResponse
 	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
 		t.Fatal(err)
 	}
 
-	assert.Len(t, body, 1)
+	assert.Len(t, body.Items, 1)
 
-	id := body[0].ExternalID
+	id := body.Items[0].ExternalID
 
 	url = "http://localhost:8000/sequences/" + id
 
@@ -179,14 +267,14 @@ func (s *SequenceHandlerTestSuite) TestSequenceHandler_UpdateSequence() {
 	assert.NoError(t, err)
 	assert.Equal(t, 200, res.StatusCode)
 
-	var listResponse []dto.SequenceResponse
+	var listResponse dto.SequencePageResponse
 	if err := json.NewDecoder(res.Body).Decode(&listResponse); err != nil {
 		t.Fatal(err)
 	}
 
-	assert.Len(t, listResponse, 1)
+	assert.Len(t, listResponse.Items, 1)
 
-	id := listResponse[0].ExternalID
+	id := listResponse.Items[0].ExternalID
 
 	url = "http://localhost:8000/sequences/" + id
 
@@ -215,6 +303,1322 @@ func (s *SequenceHandlerTestSuite) TestSequenceHandler_UpdateSequence() {
 	assert.Len(t, patchResponse.Steps, 1)
 }
 
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_DeleteAndRestoreSequence() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence to trash",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	req, err := http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	req, err = http.NewRequest("GET", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	req, err = http.NewRequest("GET", "http://localhost:8000/sequences/trash", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var trash []dto.SequenceResponse
+	if err := json.NewDecoder(res.Body).Decode(&trash); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, trash, 1)
+	assert.Equal(t, sequence.ExternalID, trash[0].ExternalID)
+	assert.NotNil(t, trash[0].DeletedAt)
+	assert.Len(t, trash[0].Steps, 1)
+
+	req, err = http.NewRequest("POST", url+"/restore", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var restored dto.SequenceResponse
+	if err := json.NewDecoder(res.Body).Decode(&restored); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, sequence.ExternalID, restored.ExternalID)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Len(t, restored.Steps, 1)
+
+	req, err = http.NewRequest("GET", url+"/revisions", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var revisions []dto.RevisionResponse
+	if err := json.NewDecoder(res.Body).Decode(&revisions); err != nil {
+		t.Fatal(err)
+	}
+
+	// create, delete and restore, newest first
+	assert.Len(t, revisions, 3)
+	assert.False(t, revisions[0].Deleted)
+	assert.Len(t, revisions[0].Steps, 1)
+	assert.True(t, revisions[1].Deleted)
+	assert.Len(t, revisions[1].Steps, 1)
+	assert.False(t, revisions[2].Deleted)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_ReplaceSequence() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence to replace",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps: []*dto.CreateStepRequest{
+			{MailSubject: "kept subject", MailContent: "kept mailbody", StepNumber: 1},
+			{MailSubject: "removed subject", MailContent: "removed mailbody", StepNumber: 2},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	var keptID string
+	for _, step := range sequence.Steps {
+		if step.MailSubject == "kept subject" {
+			keptID = step.ExternalID
+		}
+	}
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	payload := strings.NewReader(`{
+		"name": "My Replaced Sequence",
+		"openTrackingEnabled": true,
+		"clickTrackingEnabled": false,
+		"steps": [
+			{"id": "` + keptID + `", "stepNumber": 2, "mailSubject": "updated subject", "mailContent": "kept mailbody"},
+			{"stepNumber": 1, "mailSubject": "new subject", "mailContent": "new mailbody"}
+		]
+	}`)
+
+	req, err := http.NewRequest("PUT", url, payload)
+
+	assert.NoError(t, err)
+
+	req.Header.Add("content-type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var body dto.SequenceResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	replaced, err := s.ev.GetSequenceById(context.Background(), sequence.ExternalID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "My Replaced Sequence", replaced.Name)
+	assert.Equal(t, true, replaced.OpenTrackingEnabled)
+	assert.Equal(t, false, replaced.ClickTrackingEnabled)
+	assert.Len(t, replaced.Steps, 2)
+
+	for _, step := range replaced.Steps {
+		assert.NotEqual(t, "removed subject", step.MailSubject)
+		if step.ExternalID == keptID {
+			assert.Equal(t, "updated subject", step.MailSubject)
+			assert.Equal(t, 2, step.StepNumber)
+		}
+	}
+
+	// the response has the steps as stored, with the versions GET returns
+	assert.Len(t, body.Steps, 2)
+	for _, step := range body.Steps {
+		assert.NotZero(t, step.Version)
+	}
+
+	payload = strings.NewReader(`{
+		"name": "My Replaced Sequence",
+		"steps": [{"id": "` + sequence.ExternalID + `", "stepNumber": 1, "mailSubject": "subject", "mailContent": "mailbody"}]
+	}`)
+
+	req, err = http.NewRequest("PUT", url, payload)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_Revisions() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence with history",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	req, err := http.NewRequest("PATCH", url, strings.NewReader(`{"name": "My Renamed Sequence"}`))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	req, err = http.NewRequest("GET", url+"/revisions", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var revisions []dto.RevisionResponse
+	if err := json.NewDecoder(res.Body).Decode(&revisions); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "My Renamed Sequence", revisions[0].Name)
+
+	req, err = http.NewRequest("GET", url+"/revisions/diff?from=1&to=2", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var diff dto.RevisionDiffResponse
+	if err := json.NewDecoder(res.Body).Decode(&diff); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, diff.Changes, 1)
+	assert.Equal(t, "name", diff.Changes[0].Field)
+	assert.Empty(t, diff.ChangedSteps)
+
+	req, err = http.NewRequest("POST", url+"/revisions/1/rollback", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	rolledBack, err := s.ev.GetSequenceById(context.Background(), sequence.ExternalID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "My Sequence with history", rolledBack.Name)
+	assert.Len(t, rolledBack.Steps, 1)
+
+	req, err = http.NewRequest("GET", url+"/revisions/3", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	req, err = http.NewRequest("GET", url+"/revisions/4", nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	variantsURL := url + "/steps/" + rolledBack.Steps[0].ExternalID + "/variants"
+
+	res, err = http.Post(variantsURL, "application/json", strings.NewReader(`{"mailSubject": "subject A", "mailContent": "content", "weight": 1}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var variant dto.VariantResponse
+	if err := json.NewDecoder(res.Body).Decode(&variant); err != nil {
+		t.Fatal(err)
+	}
+
+	listVariants := func() []*dto.VariantResponse {
+		res, err := http.Get(variantsURL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var variants []*dto.VariantResponse
+		if err := json.NewDecoder(res.Body).Decode(&variants); err != nil {
+			t.Fatal(err)
+		}
+		return variants
+	}
+
+	// revision 3 has no variants and revision 4 has the one created
+	res, err = http.Post(url+"/revisions/3/rollback", "application/json", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, listVariants())
+
+	res, err = http.Post(url+"/revisions/4/rollback", "application/json", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	variants := listVariants()
+
+	assert.Len(t, variants, 1)
+	assert.Equal(t, variant.ExternalID, variants[0].ExternalID)
+	assert.Equal(t, "subject A", variants[0].MailSubject)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_Lifecycle() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence to send",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+	assert.Equal(t, "draft", sequence.Status)
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	transition := func(action string, status int) *dto.SequenceResponse {
+		res, err := http.Post(url+":"+action, "application/json", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, status, res.StatusCode)
+
+		if status != http.StatusOK {
+			return nil
+		}
+
+		var body dto.SequenceResponse
+		if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return &body
+	}
+
+	active := transition("activate", http.StatusOK)
+	assert.Equal(t, "active", active.Status)
+
+	transition("activate", http.StatusConflict)
+	transition("publish", http.StatusNotFound)
+
+	req, err := http.NewRequest("PATCH", url, strings.NewReader(`{"name": "My Renamed Sequence"}`))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	paused := transition("pause", http.StatusOK)
+	assert.Equal(t, "paused", paused.Status)
+
+	req, err = http.NewRequest("PATCH", url, strings.NewReader(`{"name": "My Renamed Sequence"}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	resumed := transition("resume", http.StatusOK)
+	assert.Equal(t, "active", resumed.Status)
+	assert.Equal(t, "My Renamed Sequence", resumed.Name)
+
+	archived := transition("archive", http.StatusOK)
+	assert.Equal(t, "archived", archived.Status)
+
+	transition("resume", http.StatusConflict)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_Versions() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Versioned Sequence",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+	assert.Equal(t, int32(1), sequence.Version)
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	res, err := http.Get(url)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `"1"`, res.Header.Get("ETag"))
+
+	// the second read is served from the cache
+	for range 2 {
+		req, err := http.NewRequest("GET", url, nil)
+
+		assert.NoError(t, err)
+
+		req.Header.Set("If-None-Match", `"1"`)
+
+		res, err = http.DefaultClient.Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Equal(t, `"1"`, res.Header.Get("ETag"))
+	}
+
+	req, err := http.NewRequest("PATCH", url, strings.NewReader(`{"name": "My Renamed Sequence"}`))
+
+	assert.NoError(t, err)
+
+	req.Header.Set("If-Match", `"1"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `"2"`, res.Header.Get("ETag"))
+
+	req, err = http.NewRequest("PATCH", url, strings.NewReader(`{"name": "My Stale Sequence"}`))
+
+	assert.NoError(t, err)
+
+	req.Header.Set("If-Match", `"1"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+
+	// changing a step changes the version of its sequence as well
+	req, err = http.NewRequest("PATCH", url+"/steps/"+sequence.Steps[0].ExternalID, strings.NewReader(`{"mailSubject": "new subject"}`))
+
+	assert.NoError(t, err)
+
+	req.Header.Set("If-Match", `"1"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `"2"`, res.Header.Get("ETag"))
+
+	req, err = http.NewRequest("GET", url, nil)
+
+	assert.NoError(t, err)
+
+	req.Header.Set("If-None-Match", `"2"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `"3"`, res.Header.Get("ETag"))
+
+	req, err = http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	req.Header.Set("If-Match", `"2"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+
+	req.Header.Set("If-Match", `"3"`)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_IdempotencyKey() {
+	t := s.T()
+
+	url := "http://localhost:8000/sequences"
+
+	post := func(payload string) *http.Response {
+		req, err := http.NewRequest("POST", url, strings.NewReader(payload))
+
+		assert.NoError(t, err)
+
+		req.Header.Add("content-type", "application/json")
+		req.Header.Set("Idempotency-Key", "create-my-idempotent-sequence")
+
+		res, err := http.DefaultClient.Do(req)
+
+		assert.NoError(t, err)
+		return res
+	}
+
+	payload := `{"name": "My Idempotent Sequence", "steps": [{"mailSubject": "test subject", "mailContent": "test mailbody", "stepNumber": 1}]}`
+
+	res := post(payload)
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Idempotent-Replayed"))
+
+	var created dto.SequenceResponse
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	res = post(payload)
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "true", res.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, `"1"`, res.Header.Get("ETag"))
+
+	var replayed dto.SequenceResponse
+	if err := json.NewDecoder(res.Body).Decode(&replayed); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, created.ExternalID, replayed.ExternalID)
+
+	res = post(`{"name": "My Other Sequence"}`)
+
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err := http.NewRequest("DELETE", url+"/"+created.ExternalID, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_Problems() {
+	t := s.T()
+
+	url := "http://localhost:8000/sequences"
+
+	post := func(payload string) *dto.Problem {
+		res, err := http.Post(url, "application/json", strings.NewReader(payload))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+
+		var problem dto.Problem
+		if err := json.NewDecoder(res.Body).Decode(&problem); err != nil {
+			t.Fatal(err)
+		}
+
+		return &problem
+	}
+
+	problem := post(`{"steps": [{"mailSubject": "test subject", "mailContent": "test mailbody", "stepNumber": 1}, {"mailContent": "test mailbody", "stepNumber": 2}]}`)
+
+	assert.Equal(t, "/problems/validation-error", problem.Type)
+	assert.Equal(t, "/sequences", problem.Instance)
+	assert.Equal(t, []*dto.FieldError{
+		{Pointer: "/name", Detail: `property "name" is missing`},
+		{Pointer: "/steps/1/mailSubject", Detail: `property "mailSubject" is missing`},
+	}, problem.Errors)
+
+	problem = post(`{"name": "My Sequence",}`)
+
+	assert.Equal(t, "/problems/malformed-json", problem.Type)
+	assert.Equal(t, "malformed JSON at offset 24: invalid character '}' looking for beginning of object key string", problem.Detail)
+
+	problem = post(`{"name": "My Sequence", "steps": [{"stepNumber": "1", "mailSubject": "test subject"}]}`)
+
+	assert.Equal(t, "/problems/validation-error", problem.Type)
+	assert.Equal(t, []*dto.FieldError{
+		{Pointer: "/steps/0/stepNumber", Detail: "value must be an integer"},
+	}, problem.Errors)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_OpenAPI() {
+	t := s.T()
+
+	res, err := http.Get("http://localhost:8000/openapi.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+	assert.Contains(t, spec.Paths, "/sequences/{sequence_id}/steps/{step_id}")
+
+	res, err = http.Get("http://localhost:8000/docs")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_ExportImport() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:      "My Exported Sequence",
+		Variables: map[string]string{"product": "Sequences"},
+		Steps:     []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+
+	url := "http://localhost:8000/sequences"
+
+	req, err := http.NewRequest("GET", url+"/"+sequence.ExternalID+"/export", nil)
+
+	assert.NoError(t, err)
+
+	req.Header.Set("Accept", "application/yaml")
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/yaml", res.Header.Get("Content-Type"))
+
+	exported, err := io.ReadAll(res.Body)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(exported), "name: My Exported Sequence")
+	assert.NotContains(t, string(exported), sequence.ExternalID)
+
+	importBundle := func(query string, contentType string, body []byte) *dto.ImportResponse {
+		res, err := http.Post(url+"/import?"+query, contentType, bytes.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var imported dto.ImportResponse
+		if err := json.NewDecoder(res.Body).Decode(&imported); err != nil {
+			t.Fatal(err)
+		}
+		return &imported
+	}
+
+	imported := importBundle("onConflict=rename&dryRun=true", "application/yaml", exported)
+
+	assert.True(t, imported.DryRun)
+	assert.Equal(t, dto.ImportRenamed, imported.Sequences[0].Action)
+	assert.Equal(t, "My Exported Sequence (2)", imported.Sequences[0].ImportedAs)
+	assert.Nil(t, imported.Sequences[0].ID)
+
+	imported = importBundle("onConflict=skip", "application/yaml", exported)
+
+	assert.Equal(t, dto.ImportSkipped, imported.Sequences[0].Action)
+	assert.Equal(t, sequence.ExternalID, *imported.Sequences[0].ID)
+
+	res, err = http.Get(url + "/export?ids=" + sequence.ExternalID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var bundle dto.SequenceBundle
+	if err := json.NewDecoder(res.Body).Decode(&bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle.Sequences[0].Steps[0].MailSubject = "imported subject"
+	bundle.Sequences[0].Steps = append(bundle.Sequences[0].Steps, &dto.BundleStep{StepNumber: 2, MailSubject: "follow up", MailHTML: "<p>follow up</p>"})
+
+	raw, err := json.Marshal(bundle)
+
+	assert.NoError(t, err)
+
+	imported = importBundle("onConflict=overwrite", "application/json", raw)
+
+	assert.Equal(t, dto.ImportOverwritten, imported.Sequences[0].Action)
+	assert.Equal(t, sequence.ExternalID, *imported.Sequences[0].ID)
+	assert.Len(t, imported.Sequences[0].Changes.AddedSteps, 1)
+	assert.Len(t, imported.Sequences[0].Changes.ChangedSteps, 1)
+
+	overwritten, err := s.ev.GetSequenceById(context.Background(), sequence.ExternalID)
+
+	assert.NoError(t, err)
+	assert.Len(t, overwritten.Steps, 2)
+
+	for _, step := range overwritten.Steps {
+		if step.StepNumber == 1 {
+			assert.Equal(t, sequence.Steps[0].ExternalID, step.ExternalID)
+			assert.Equal(t, "imported subject", step.MailSubject)
+		}
+	}
+
+	imported = importBundle("onConflict=rename", "application/json", raw)
+
+	assert.Equal(t, dto.ImportRenamed, imported.Sequences[0].Action)
+	assert.NotNil(t, imported.Sequences[0].ID)
+
+	// leaves the sequences in the trash so the listing tests only see their own sequence
+	for _, id := range []string{sequence.ExternalID, *imported.Sequences[0].ID} {
+		req, err := http.NewRequest("DELETE", url+"/"+id, nil)
+
+		assert.NoError(t, err)
+
+		res, err := http.DefaultClient.Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_CloneSequence() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence to clone",
+		OpenTrackingEnabled:  true,
+		ClickTrackingEnabled: false,
+		Variables:            map[string]string{"product": "Sequences"},
+		Steps: []*dto.CreateStepRequest{
+			{MailSubject: "first subject", MailContent: "first mailbody", StepNumber: 1},
+			{MailSubject: "second subject", MailContent: "second mailbody", StepNumber: 2, DelayDays: 2},
+		},
+	})
+
+	assert.NoError(t, err)
+
+	url := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	variants := fmt.Sprintf("%s/steps/%s/variants", url, sequence.Steps[0].ExternalID)
+
+	res, err := http.Post(variants, "application/json", strings.NewReader(`{"mailSubject": "subject A", "mailContent": "content", "weight": 1}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	clone := func(body string) *dto.SequenceResponse {
+		res, err := http.Post(url+"/clone", "application/json", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, `"1"`, res.Header.Get("ETag"))
+
+		var cloned dto.SequenceResponse
+		if err := json.NewDecoder(res.Body).Decode(&cloned); err != nil {
+			t.Fatal(err)
+		}
+		return &cloned
+	}
+
+	cloned := clone("")
+
+	assert.NotEqual(t, sequence.ExternalID, cloned.ExternalID)
+	assert.Equal(t, "My Sequence to clone (copy)", cloned.Name)
+	assert.True(t, cloned.OpenTrackingEnabled)
+	assert.False(t, cloned.ClickTrackingEnabled)
+	assert.Equal(t, map[string]string{"product": "Sequences"}, cloned.Variables)
+	assert.Equal(t, "draft", cloned.Status)
+	assert.Equal(t, sequence.ExternalID, *cloned.SourceSequenceID)
+	assert.Len(t, cloned.Steps, 2)
+
+	for i, step := range cloned.Steps {
+		assert.NotEqual(t, sequence.Steps[i].ExternalID, step.ExternalID)
+		assert.Equal(t, sequence.Steps[i].StepNumber, step.StepNumber)
+		assert.Equal(t, sequence.Steps[i].MailSubject, step.MailSubject)
+		assert.Equal(t, sequence.Steps[i].DelayDays, step.DelayDays)
+	}
+
+	res, err = http.Get(fmt.Sprintf("http://localhost:8000/sequences/%s/steps/%s/variants", cloned.ExternalID, cloned.Steps[0].ExternalID))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var clonedVariants []*dto.VariantResponse
+	if err := json.NewDecoder(res.Body).Decode(&clonedVariants); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, clonedVariants, 1)
+	assert.Equal(t, "subject A", clonedVariants[0].MailSubject)
+
+	renamed := clone(`{"name": "My Cloned Sequence", "openTrackingEnabled": false, "clickTrackingEnabled": true}`)
+
+	assert.Equal(t, "My Cloned Sequence", renamed.Name)
+	assert.False(t, renamed.OpenTrackingEnabled)
+	assert.True(t, renamed.ClickTrackingEnabled)
+
+	res, err = http.Post("http://localhost:8000/sequences/"+uuid.NewString()+"/clone", "application/json", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	// leaves the sequences in the trash so the listing tests only see their own sequence
+	for _, id := range []string{sequence.ExternalID, cloned.ExternalID, renamed.ExternalID} {
+		req, err := http.NewRequest("DELETE", "http://localhost:8000/sequences/"+id, nil)
+
+		assert.NoError(t, err)
+
+		res, err := http.DefaultClient.Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_Workspaces() {
+	t := s.T()
+
+	id, err := s.ev.CreateWorkspaceSequence(context.Background(), "Other Workspace Sequence")
+
+	assert.NoError(t, err)
+
+	res, err := http.Get("http://localhost:8000/sequences/" + id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get("http://localhost:8000/sequences?name=Other%20Workspace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var page dto.SequencePageResponse
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, page.Items)
+
+	res, err = http.Post("http://localhost:8000/sequences/"+id+"/clone", "application/json", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	req, err := http.NewRequest("DELETE", "http://localhost:8000/sequences/"+id, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_ApiKeys() {
+	t := s.T()
+
+	// a client of its own, so requests do not get the key of the suite
+	withKey := func(method string, url string, key string, body string) *http.Response {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		assert.NoError(t, err)
+
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+
+		res, err := (&http.Client{}).Do(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	res := withKey("GET", "http://localhost:8000/sequences", "", "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, "Bearer", res.Header.Get("WWW-Authenticate"))
+
+	res = withKey("GET", "http://localhost:8000/sequences", "sk_unknown_secret", "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res, err := http.Post("http://localhost:8000/api-keys", "application/json", strings.NewReader(`{"name": "read only", "scopes": ["sequences:read"]}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var created dto.ApiKeyResponse
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, strings.HasPrefix(created.Key, "sk_"+created.Prefix+"_"))
+
+	res = withKey("GET", "http://localhost:8000/sequences", created.Key, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = withKey("POST", "http://localhost:8000/sequences", created.Key, `{"name": "Forbidden", "openTrackingEnabled": false, "clickTrackingEnabled": false, "steps": [{"mailSubject": "Subject", "mailContent": "content", "stepNumber": 1}]}`)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res, err = http.Get("http://localhost:8000/api-keys")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var keys []*dto.ApiKeyResponse
+	if err := json.NewDecoder(res.Body).Decode(&keys); err != nil {
+		t.Fatal(err)
+	}
+
+	var listed *dto.ApiKeyResponse
+	for _, key := range keys {
+		assert.Empty(t, key.Key)
+		if key.ExternalID == created.ExternalID {
+			listed = key
+		}
+	}
+
+	if assert.NotNil(t, listed) {
+		assert.NotNil(t, listed.LastUsedAt)
+	}
+
+	res, err = http.Post("http://localhost:8000/api-keys/"+created.ExternalID+"/rotate", "application/json", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var rotated dto.ApiKeyResponse
+	if err := json.NewDecoder(res.Body).Decode(&rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, created.ExternalID, rotated.ExternalID)
+	assert.NotEqual(t, created.Key, rotated.Key)
+
+	res = withKey("GET", "http://localhost:8000/sequences", created.Key, "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res = withKey("GET", "http://localhost:8000/sequences", rotated.Key, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	req, err := http.NewRequest("DELETE", "http://localhost:8000/api-keys/"+created.ExternalID, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	res = withKey("GET", "http://localhost:8000/sequences", rotated.Key, "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_BearerTokens() {
+	t := s.T()
+
+	withToken := func(claims jwt.MapClaims) *http.Response {
+		token, err := s.ev.SignToken(claims)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest("GET", "http://localhost:8000/sequences", nil)
+		assert.NoError(t, err)
+
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		res, err := (&http.Client{}).Do(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":          integtests.TokenIssuer,
+			"aud":          integtests.TokenAudience,
+			"sub":          "user-1",
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"workspace_id": "00000000-0000-0000-0000-000000000001",
+			"scope":        "openid sequences:read",
+			"roles":        []string{"viewer"},
+		}
+	}
+
+	res := withToken(claims())
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	res = withToken(expired)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, `Bearer error="invalid_token"`, res.Header.Get("WWW-Authenticate"))
+
+	otherAudience := claims()
+	otherAudience["aud"] = "other-api"
+
+	res = withToken(otherAudience)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	otherWorkspace := claims()
+	otherWorkspace["workspace_id"] = uuid.NewString()
+
+	res = withToken(otherWorkspace)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	withoutScope := claims()
+	withoutScope["scope"] = "openid"
+
+	res = withToken(withoutScope)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_RoleBindings() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:  "My shared sequence",
+		Steps: []*dto.CreateStepRequest{{MailSubject: "subject", MailContent: "content", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+
+	sequenceID := sequence.ExternalID
+
+	withToken := func(method string, url string, user string, roles []string, body string) *http.Response {
+		token, err := s.ev.SignToken(jwt.MapClaims{
+			"iss":          integtests.TokenIssuer,
+			"aud":          integtests.TokenAudience,
+			"sub":          user,
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"workspace_id": "00000000-0000-0000-0000-000000000001",
+			"scope":        "sequences:read sequences:write roles:manage",
+			"roles":        roles,
+		})
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		assert.NoError(t, err)
+
+		req.Header.Set("Authorization", "Bearer "+token)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		res, err := (&http.Client{}).Do(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	sequenceURL := "http://localhost:8000/sequences/" + sequenceID
+
+	res := withToken("GET", sequenceURL, "user-2", nil, "")
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	var problem dto.Problem
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+	assert.Equal(t, "/problems/role-not-granted", problem.Type)
+
+	// the reads of an admin and of an anonymous caller must not be served to a user without a role
+	stepURL := sequenceURL + "/steps/" + sequence.Steps[0].ExternalID
+
+	for _, url := range []string{sequenceURL, stepURL, sequenceURL + "/steps", "http://localhost:8000/sequences"} {
+		res = withToken("GET", url, "user-1", []string{"admin"}, "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		res, err = http.Get(url)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		res = withToken("GET", url, "user-2", nil, "")
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	}
+
+	res = withToken("POST", "http://localhost:8000/role-bindings", "user-2", nil, `{"userId":"user-2","role":"admin"}`)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res = withToken("POST", "http://localhost:8000/role-bindings", "user-1", []string{"admin"}, `{"userId":"user-2","role":"viewer","sequenceId":"`+sequenceID+`"}`)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var binding dto.RoleBindingResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&binding))
+	assert.Equal(t, "user-2", binding.UserID)
+	assert.Equal(t, "viewer", binding.Role)
+	assert.Equal(t, sequenceID, *binding.SequenceID)
+
+	res = withToken("GET", sequenceURL, "user-2", nil, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = withToken("PATCH", sequenceURL, "user-2", nil, `{"name":"Renamed"}`)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res = withToken("DELETE", stepURL, "user-2", nil, "")
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res = withToken("POST", stepURL+"/variants/assignments", "user-2", nil, `{"enrollmentId":"`+uuid.NewString()+`"}`)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res = withToken("GET", "http://localhost:8000/sequences", "user-2", nil, "")
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	res = withToken("POST", "http://localhost:8000/role-bindings", "user-1", []string{"admin"}, `{"userId":"user-2","role":"editor","sequenceId":"`+sequenceID+`"}`)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	res = withToken("PATCH", sequenceURL, "user-2", nil, `{"name":"Renamed"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = withToken("GET", "http://localhost:8000/role-bindings", "user-1", []string{"admin"}, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var bindings []dto.RoleBindingResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&bindings))
+	assert.Len(t, bindings, 1)
+	assert.Equal(t, "editor", bindings[0].Role)
+
+	res = withToken("DELETE", "http://localhost:8000/role-bindings/"+bindings[0].ExternalID, "user-1", []string{"admin"}, "")
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	res = withToken("GET", sequenceURL, "user-2", nil, "")
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err := http.NewRequest("DELETE", sequenceURL, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_RateLimit() {
+	t := s.T()
+
+	// a client of its own behind a proxy, so the requests get a bucket of their own
+	health := func() *http.Response {
+		req, err := http.NewRequest("GET", "http://localhost:8000/health", nil)
+		assert.NoError(t, err)
+
+		req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+
+		res, err := (&http.Client{}).Do(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	res := health()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "10000", res.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "0", res.Header.Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, res.Header.Get("RateLimit-Reset"))
+
+	res = health()
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.NotEmpty(t, res.Header.Get("Retry-After"))
+
+	var problem dto.Problem
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+	assert.Equal(t, "/problems/rate-limited", problem.Type)
+
+	res, err := http.Get("http://localhost:8000/sequences")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "10000", res.Header.Get("RateLimit-Limit"))
+}
+
+func (s *SequenceHandlerTestSuite) TestSequenceHandler_AuditEvents() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:  "My audited sequence",
+		Steps: []*dto.CreateStepRequest{{MailSubject: "subject", MailContent: "content", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+
+	sequenceURL := "http://localhost:8000/sequences/" + sequence.ExternalID
+
+	req, err := http.NewRequest("PATCH", sequenceURL, strings.NewReader(`{"name":"Renamed"}`))
+	assert.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "audit-test-"+sequence.ExternalID)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "audit-test-"+sequence.ExternalID, res.Header.Get("X-Request-Id"))
+
+	res, err = http.DefaultClient.Get("http://localhost:8000/audit-events?limit=1&targetType=sequence&targetId=" + sequence.ExternalID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var page dto.AuditEventPageResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&page))
+	assert.Len(t, page.Items, 1)
+	assert.NotNil(t, page.NextCursor)
+
+	update := page.Items[0]
+	assert.Equal(t, "update", update.Action)
+	assert.True(t, strings.HasPrefix(update.Actor, "api-key:"))
+	assert.Equal(t, "audit-test-"+sequence.ExternalID, *update.RequestID)
+
+	var before, after dto.SequenceResponse
+	assert.NoError(t, json.Unmarshal(update.Before, &before))
+	assert.NoError(t, json.Unmarshal(update.After, &after))
+	assert.Equal(t, "My audited sequence", before.Name)
+	assert.Equal(t, "Renamed", after.Name)
+
+	res, err = http.DefaultClient.Get("http://localhost:8000/audit-events?limit=1&targetType=sequence&targetId=" + sequence.ExternalID + "&cursor=" + *page.NextCursor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	page = dto.AuditEventPageResponse{}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&page))
+	assert.Len(t, page.Items, 1)
+	assert.Nil(t, page.NextCursor)
+	assert.Equal(t, "create", page.Items[0].Action)
+	assert.Equal(t, "null", string(page.Items[0].Before))
+
+	// only the admins of the workspace read the audit log
+	token, err := s.ev.SignToken(jwt.MapClaims{
+		"iss":          integtests.TokenIssuer,
+		"aud":          integtests.TokenAudience,
+		"sub":          "user-1",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"workspace_id": "00000000-0000-0000-0000-000000000001",
+		"scope":        "audit:read",
+		"roles":        []string{"editor"},
+	})
+	assert.NoError(t, err)
+
+	req, err = http.NewRequest("GET", "http://localhost:8000/audit-events", nil)
+	assert.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err = (&http.Client{}).Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	// leaves the sequence in the trash so the listing tests only see their own sequence
+	req, err = http.NewRequest("DELETE", sequenceURL, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
 func (s *SequenceHandlerTestSuite) TearDownSuite() {
 	err := s.ev.ClearDatabase(context.Background())
 	s.Require().NoError(err)
@@ -3,30 +3,50 @@ package integtests
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
 	"github.com/murilo-bracero/sequence-technical-test/internal/db"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/openapi"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/cache"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/ratelimit"
 	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// the issuer and audience of the bearer tokens accepted by the app
+const (
+	TokenIssuer   = "https://issuer.integ-tests.local"
+	TokenAudience = "sequence-api"
+)
+
 type EnvironmentCommands struct {
 	pgContainer *postgres.PostgresContainer
 	db          db.DB
+	tokenKey    *rsa.PrivateKey
 }
 
 func New() *EnvironmentCommands {
@@ -115,14 +135,96 @@ func (e *EnvironmentCommands) ClearDatabase(ctx context.Context) error {
 		return err
 	}
 
+	_, err = tx.Exec(ctx, "DELETE FROM role_bindings")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM audit_events")
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(ctx, "DELETE FROM sequences")
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.Exec(ctx, "DELETE FROM workspaces WHERE external_id <> '00000000-0000-0000-0000-000000000001'")
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
+// CreateWorkspaceSequence stores a sequence in a new workspace straight in the
+// database, as requests only reach the default workspace, and returns its id.
+func (e *EnvironmentCommands) CreateWorkspaceSequence(ctx context.Context, name string) (string, error) {
+	if e.db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+
+	tx, err := e.db.Tx(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var id string
+	err = tx.QueryRow(ctx, `
+		WITH workspace AS (INSERT INTO workspaces (workspace_name) VALUES ('Other') RETURNING id)
+		INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled, workspace_id)
+		SELECT $1, false, false, id FROM workspace
+		RETURNING external_id::text`, name).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return id, tx.Commit(ctx)
+}
+
+// StepExists tells whether the step is still stored, looking it up straight in
+// the database, whatever its workspace.
+func (e *EnvironmentCommands) StepExists(ctx context.Context, id string) (bool, error) {
+	if e.db == nil {
+		return false, fmt.Errorf("database not initialized")
+	}
+
+	tx, err := e.db.Tx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM steps WHERE external_id = $1)", id).Scan(&exists)
+
+	return exists, err
+}
+
+// CountVariantAssignments counts the assignments recorded for the variant,
+// looking them up straight in the database, even when the variant is deleted.
+func (e *EnvironmentCommands) CountVariantAssignments(ctx context.Context, variantID string) (int, error) {
+	if e.db == nil {
+		return 0, fmt.Errorf("database not initialized")
+	}
+
+	tx, err := e.db.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var count int
+	err = tx.QueryRow(ctx, "SELECT count(*) FROM step_variant_assignments WHERE variant_external_id = $1", variantID).Scan(&count)
+
+	return count, err
+}
+
 func (e *EnvironmentCommands) Destroy(ctx context.Context) error {
 	if e.pgContainer != nil {
 		return e.pgContainer.Terminate(ctx)
@@ -189,6 +291,35 @@ func (e *EnvironmentCommands) startApp(ctx context.Context) error {
 		MaxDbConnections: 10,
 		MinDbConnections: 1,
 		MaxConnIdleTime:  30,
+
+		MaxSequencePagination: 50,
+
+		IdempotencyKeyTTL: 24,
+
+		ValidateRequests:  true,
+		ValidateResponses: true,
+
+		DefaultWorkspaceID: "00000000-0000-0000-0000-000000000001",
+
+		JWKSRefreshInterval: 60,
+		JWTIssuer:           TokenIssuer,
+		JWTAudience:         TokenAudience,
+		JWTWorkspaceClaim:   "workspace_id",
+		JWTRolesClaim:       "roles",
+
+		// generous enough for the suites, only the health checks of the rate
+		// limit test empty their bucket
+		RateLimitEnabled:    true,
+		RateLimitPerMinute:  60000,
+		RateLimitBurst:      10000,
+		RateLimitCosts:      "GET /health=10000",
+		RateLimitStore:      "postgres",
+		RateLimitTrustProxy: true,
+	}
+
+	cfg.JWKSFile, err = e.writeKeySet()
+	if err != nil {
+		return err
 	}
 
 	db, err := db.New(context.Background(), cfg)
@@ -203,23 +334,184 @@ func (e *EnvironmentCommands) startApp(ctx context.Context) error {
 
 	e.db = db
 
+	workspaceRepository := repository.NewWorkspaceRepository(db)
+
+	workspaceService := services.NewWorkspaceService(workspaceRepository)
+
+	workspaceHandler, err := handlers.NewWorkspaceHandler(cfg, workspaceService)
+	if err != nil {
+		return err
+	}
+
+	apiKeyRepository := repository.NewApiKeyRepository(db)
+
+	apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+	apiKeyHandler := handlers.NewApiKeyHandler(apiKeyService)
+
+	authHandler, err := handlers.NewAuthHandler(ctx, cfg, apiKeyService)
+	if err != nil {
+		return err
+	}
+
+	if err := e.authenticateClient(ctx, cfg, workspaceService, apiKeyService); err != nil {
+		return err
+	}
+
+	roleBindingRepository := repository.NewRoleBindingRepository(db)
+
+	transactor := repository.NewTransactor(db)
+
+	auditRepository := repository.NewAuditRepository(db)
+
 	sequenceRepository := repository.NewSequenceRepository(db)
 
-	sequenceService := services.NewSequenceService(sequenceRepository)
+	sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, transactor, auditRepository)
 
 	sequenceHandler := handlers.NewSequenceHandler(cfg, cache, sequenceService)
 
 	stepRepository := repository.NewStepRepository(db)
 
-	stepService := services.NewStepService(sequenceRepository, stepRepository)
+	stepService := services.NewStepService(sequenceRepository, stepRepository, roleBindingRepository, transactor, auditRepository)
+
+	stepHandler := handlers.NewStepHandler(cfg, cache, stepService)
+
+	revisionRepository := repository.NewRevisionRepository(db)
+
+	revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, roleBindingRepository, transactor, auditRepository)
+
+	revisionHandler := handlers.NewRevisionHandler(cfg, cache, revisionService)
+
+	previewService := services.NewPreviewService(sequenceRepository, mail.NewTracker(cfg.TrackingBaseURL), roleBindingRepository)
+
+	previewHandler := handlers.NewPreviewHandler(previewService)
+
+	variantRepository := repository.NewVariantRepository(db)
+
+	variantService := services.NewVariantService(stepRepository, variantRepository, roleBindingRepository, transactor, auditRepository)
+
+	variantHandler := handlers.NewVariantHandler(variantService)
+
+	bundleService := services.NewBundleService(sequenceRepository, roleBindingRepository, transactor, auditRepository)
+
+	bundleHandler := handlers.NewBundleHandler(cfg, cache, bundleService)
+
+	roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+	roleBindingHandler := handlers.NewRoleBindingHandler(roleBindingService)
+
+	auditService := services.NewAuditService(auditRepository, roleBindingRepository)
+
+	auditHandler := handlers.NewAuditHandler(cfg, auditService)
+
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
+
+	idempotencyService := services.NewIdempotencyService(time.Duration(cfg.IdempotencyKeyTTL)*time.Hour, idempotencyRepository)
+
+	idempotencyHandler := handlers.NewIdempotencyHandler(idempotencyService)
+
+	rateLimitRepository := repository.NewRateLimitRepository(db)
+
+	limiter, err := ratelimit.New(cfg, rateLimitRepository)
+	if err != nil {
+		return err
+	}
+
+	rateLimitHandler, err := handlers.NewRateLimitHandler(cfg, limiter)
+	if err != nil {
+		return err
+	}
+
+	openAPIHandler := handlers.NewOpenAPIHandler()
+
+	doc, err := openapi.Load(context.Background())
+	if err != nil {
+		return err
+	}
+
+	validationHandler, err := handlers.NewValidationHandler(cfg, doc)
+	if err != nil {
+		return err
+	}
+
+	requestIDHandler := handlers.NewRequestIDHandler()
+
+	go server.Start(cfg, db, sequenceHandler, stepHandler, revisionHandler, previewHandler, variantHandler, bundleHandler, idempotencyHandler, openAPIHandler, validationHandler, workspaceHandler, apiKeyHandler, roleBindingHandler, auditHandler, authHandler, rateLimitHandler, requestIDHandler)
+
+	return nil
+}
+
+// writeKeySet generates the key signing the bearer tokens of the tests and
+// writes the key set with its public key to a temporary file.
+func (e *EnvironmentCommands) writeKeySet() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+
+	e.tokenKey = key
+
+	set, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "integ-tests",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "integ-tests")
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, "jwks.json")
+	return file, os.WriteFile(file, set, 0o600)
+}
 
-	stepHandler := handlers.NewStepHandler(cache, stepService)
+// SignToken signs a bearer token with claims, which the app accepts when they
+// hold the issuer, audience and expiration it expects.
+func (e *EnvironmentCommands) SignToken(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "integ-tests"
+
+	return token.SignedString(e.tokenKey)
+}
+
+// authenticateClient creates a key of the default workspace with every scope
+// and makes http.DefaultClient send it, unless a request sets its own.
+func (e *EnvironmentCommands) authenticateClient(ctx context.Context, cfg *config.Config, workspaceService services.WorkspaceService, apiKeyService services.ApiKeyService) error {
+	workspace, err := workspaceService.GetWorkspace(ctx, uuid.MustParse(cfg.DefaultWorkspaceID))
+	if err != nil {
+		return err
+	}
 
-	go server.Start(cfg, db, sequenceHandler, stepHandler)
+	key, err := apiKeyService.CreateApiKey(tenancy.WithWorkspace(ctx, workspace.ID), dto.CreateApiKeyRequest{Name: "integ-tests", Scopes: auth.Scopes})
+	if err != nil {
+		return err
+	}
 
+	http.DefaultClient.Transport = &apiKeyTransport{key: key.Key}
 	return nil
 }
 
+type apiKeyTransport struct {
+	key string
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.key)
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
 func (e *EnvironmentCommands) migrate(dbName string, conn string) error {
 	m, err := migrate.New("file://../db/migrations/"+dbName, conn)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	integtests "github.com/murilo-bracero/sequence-technical-test/integ-tests"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/stretchr/testify/assert"
@@ -175,6 +176,535 @@ func (s *StepHandlerTestSuite) TestStepHandler_DeleteStep() {
 	assert.Len(t, sequence.Steps, 0)
 }
 
+func (s *StepHandlerTestSuite) TestStepHandler_DeleteStep_RemovesRow() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	stepID := sequence.Steps[0].ExternalID
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps/%s", sequence.ExternalID, stepID)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	exists, err := s.ev.StepExists(context.Background(), stepID)
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_StepNumbers() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps: []*dto.CreateStepRequest{
+			{MailSubject: "first subject", MailContent: "test mailbody", StepNumber: 1},
+			{MailSubject: "second subject", MailContent: "test mailbody", StepNumber: 2},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	stepNumbers := func() map[string]int {
+		found, err := s.ev.GetSequenceById(context.Background(), sequence.ExternalID)
+		assert.NoError(t, err)
+
+		numbers := make(map[string]int)
+		for _, step := range found.Steps {
+			numbers[step.MailSubject] = step.StepNumber
+		}
+		return numbers
+	}
+
+	// the numbers sent on create are the ones stored, CreateSteps used to drop them
+	assert.Equal(t, map[string]int{"first subject": 1, "second subject": 2}, stepNumbers())
+
+	// inserts a step before the others
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps", sequence.ExternalID)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(`{"stepNumber": 1, "mailSubject": "inserted subject", "mailContent": "test mailbody"}`))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var inserted dto.StepResponse
+	if err := json.NewDecoder(res.Body).Decode(&inserted); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]int{"inserted subject": 1, "first subject": 2, "second subject": 3}, stepNumbers())
+
+	// a step number taken by another step is a conflict
+	req, err = http.NewRequest("PATCH", url+"/"+inserted.ExternalID, strings.NewReader(`{"stepNumber": 2}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	// moves the inserted step to the end
+	var first, second string
+	for _, step := range sequence.Steps {
+		if step.MailSubject == "first subject" {
+			first = step.ExternalID
+		} else {
+			second = step.ExternalID
+		}
+	}
+
+	payload := fmt.Sprintf(`{"stepIds": ["%s", "%s", "%s"]}`, first, second, inserted.ExternalID)
+
+	req, err = http.NewRequest("PUT", url+"/order", strings.NewReader(payload))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.Equal(t, map[string]int{"first subject": 1, "second subject": 2, "inserted subject": 3}, stepNumbers())
+
+	// the order must list every step
+	req, err = http.NewRequest("PUT", url+"/order", strings.NewReader(fmt.Sprintf(`{"stepIds": ["%s"]}`, first)))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// deleting a step closes the gap it leaves
+	req, err = http.NewRequest("DELETE", url+"/"+first, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, map[string]int{"second subject": 1, "inserted subject": 2}, stepNumbers())
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_Timing() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps", sequence.ExternalID)
+
+	payload := `{"stepNumber": 2, "mailSubject": "test subject", "mailContent": "test mailbody", "delayDays": 2, "delayHours": 3, "businessDaysOnly": true, "sendWindow": {"start": "09:00", "end": "17:00"}}`
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(payload))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var body dto.StepResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, body.DelayDays)
+	assert.Equal(t, 3, body.DelayHours)
+	assert.True(t, body.BusinessDaysOnly)
+	assert.Equal(t, &dto.SendWindow{Start: "09:00", End: "17:00"}, body.SendWindow)
+
+	// an inverted window is rejected
+	req, err = http.NewRequest("PATCH", url+"/"+body.ExternalID, strings.NewReader(`{"sendWindow": {"start": "17:00", "end": "09:00"}}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// an empty window removes it
+	req, err = http.NewRequest("PATCH", url+"/"+body.ExternalID, strings.NewReader(`{"delayDays": 0, "sendWindow": {}}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	found, err := s.ev.GetSequenceById(context.Background(), sequence.ExternalID)
+	assert.NoError(t, err)
+
+	for _, step := range found.Steps {
+		if step.ExternalID == body.ExternalID {
+			assert.Equal(t, 0, step.DelayDays)
+			assert.Equal(t, 3, step.DelayHours)
+			assert.Nil(t, step.SendWindow)
+		}
+	}
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_GetSteps() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Steps: []*dto.CreateStepRequest{
+			{MailSubject: "first subject", MailContent: "test mailbody", StepNumber: 1},
+			{MailSubject: "second subject", MailContent: "test mailbody", StepNumber: 2},
+			{MailSubject: "third subject", MailContent: "test mailbody", StepNumber: 3},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, sequence)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps", sequence.ExternalID)
+
+	getPage := func(query string) dto.StepPageResponse {
+		res, err := http.Get(url + query)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var page dto.StepPageResponse
+		if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+
+	page := getPage("?limit=2")
+
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "first subject", page.Items[0].MailSubject)
+	assert.Equal(t, "second subject", page.Items[1].MailSubject)
+	assert.NotNil(t, page.NextCursor)
+
+	page = getPage("?limit=2&cursor=" + *page.NextCursor)
+
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "third subject", page.Items[0].MailSubject)
+	assert.Nil(t, page.NextCursor)
+
+	// fetches a single step
+	res, err := http.Get(url + "/" + page.Items[0].ExternalID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var step dto.StepResponse
+	if err := json.NewDecoder(res.Body).Decode(&step); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 3, step.StepNumber)
+
+	// a step of another sequence is not found
+	other, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:  "My Sequence 2",
+		Steps: []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+
+	res, err = http.Get(fmt.Sprintf("http://localhost:8000/sequences/%s/steps/%s", other.ExternalID, step.ExternalID))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get(fmt.Sprintf("http://localhost:8000/sequences/%s/steps", uuid.New()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_Templates() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: true,
+		Variables:            map[string]string{"product": "Mailbox"},
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"product": "Mailbox"}, sequence.Variables)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps", sequence.ExternalID)
+
+	payload := `{"stepNumber": 2, "mailSubject": "Hi {{contact.firstName | default \"there\"}}", "mailContent": "Meet {{sequence.product}}"}`
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(payload))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var body dto.StepResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"contact.firstName", "sequence.product"}, body.Variables)
+
+	// templates are validated when the step is updated
+	req, err = http.NewRequest("PATCH", url+"/"+body.ExternalID, strings.NewReader(`{"mailSubject": "Hi {{contact.firstName"}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_Bodies() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: false,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test mailbody", sequence.Steps[0].MailHTML)
+	assert.Equal(t, "test mailbody", sequence.Steps[0].MailText)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps", sequence.ExternalID)
+
+	payload := `{"stepNumber": 2, "mailSubject": "Hi", "mailHtml": "<p onclick=\"x()\">Hi <a href=\"https://example.com\">there</a></p><script>alert(1)</script>"}`
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(payload))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var body dto.StepResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `<p>Hi <a href="https://example.com">there</a></p>`, body.MailHTML)
+	assert.Equal(t, body.MailHTML, body.MailContent)
+	assert.Equal(t, "Hi there (https://example.com)", body.MailText)
+
+	req, err = http.NewRequest("PATCH", url+"/"+body.ExternalID, strings.NewReader(`{"mailText": "Hello there"}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Hello there", body.MailText)
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_Preview() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  true,
+		ClickTrackingEnabled: true,
+		Variables:            map[string]string{"product": "Mailbox"},
+		Steps: []*dto.CreateStepRequest{{
+			MailSubject: "Hi {{contact.firstName | capitalize}}",
+			MailContent: `<p>{{contact.company}} meets <a href="https://example.com">{{sequence.product}}</a></p>`,
+			StepNumber:  1,
+		}},
+	})
+
+	assert.NoError(t, err)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps/%s/preview", sequence.ExternalID, sequence.Steps[0].ExternalID)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(`{"contact": {"firstName": "ana"}}`))
+
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var body dto.StepPreviewResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Hi Ana", body.Subject)
+	assert.Equal(t, []string{"variable contact.company is not resolved"}, body.Warnings)
+	assert.Len(t, body.TrackedLinks, 1)
+	assert.Equal(t, "https://example.com", body.TrackedLinks[0].URL)
+	assert.NotNil(t, body.OpenTrackingURL)
+	assert.Contains(t, body.HTML, "/track/open?step=")
+	assert.Contains(t, body.Text, "Mailbox ("+body.TrackedLinks[0].TrackedURL+")")
+
+	req, err = http.NewRequest("POST", url, strings.NewReader(`{"contact": {"firstName": "ana"}, "strict": true}`))
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+}
+
+func (s *StepHandlerTestSuite) TestStepHandler_Variants() {
+	t := s.T()
+
+	sequence, err := s.ev.CreateSequence(context.Background(), dto.CreateSequenceRequest{
+		Name:                 "My Sequence 1",
+		OpenTrackingEnabled:  false,
+		ClickTrackingEnabled: false,
+		Steps:                []*dto.CreateStepRequest{{MailSubject: "test subject", MailContent: "test mailbody", StepNumber: 1}},
+	})
+
+	assert.NoError(t, err)
+
+	url := fmt.Sprintf("http://localhost:8000/sequences/%s/steps/%s/variants", sequence.ExternalID, sequence.Steps[0].ExternalID)
+
+	enrollmentID := uuid.New()
+
+	// steps without variants cannot be assigned
+	res, err := http.Post(url+"/assignments", "application/json", strings.NewReader(fmt.Sprintf(`{"enrollmentId": "%s"}`, enrollmentID)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+	for _, subject := range []string{"subject A", "subject B"} {
+		res, err := http.Post(url, "application/json", strings.NewReader(fmt.Sprintf(`{"mailSubject": "%s", "mailContent": "content", "weight": 1}`, subject)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+	}
+
+	var assignments [2]dto.VariantAssignmentResponse
+	for i := range assignments {
+		res, err := http.Post(url+"/assignments", "application/json", strings.NewReader(fmt.Sprintf(`{"enrollmentId": "%s"}`, enrollmentID)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		if err := json.NewDecoder(res.Body).Decode(&assignments[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Equal(t, assignments[0], assignments[1])
+
+	res, err = http.Get(url + "/" + assignments[0].VariantID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var variant dto.VariantResponse
+	if err := json.NewDecoder(res.Body).Decode(&variant); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(1), variant.Assignments)
+
+	req, err := http.NewRequest("DELETE", url+"/"+assignments[0].VariantID, nil)
+
+	assert.NoError(t, err)
+
+	res, err = http.DefaultClient.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	res, err = http.Get(url)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var variants []*dto.VariantResponse
+	if err := json.NewDecoder(res.Body).Decode(&variants); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, variants, 1)
+
+	// the assignment of the deleted variant is kept, and the enrollment gets the variant left
+	count, err := s.ev.CountVariantAssignments(context.Background(), assignments[0].VariantID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	res, err = http.Post(url+"/assignments", "application/json", strings.NewReader(fmt.Sprintf(`{"enrollmentId": "%s"}`, enrollmentID)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var reassigned dto.VariantAssignmentResponse
+	if err := json.NewDecoder(res.Body).Decode(&reassigned); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, variants[0].ExternalID, reassigned.VariantID)
+
+	count, err = s.ev.CountVariantAssignments(context.Background(), reassigned.VariantID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
 func (s *StepHandlerTestSuite) TearDownSuite() {
 	err := s.ev.ClearDatabase(context.Background())
 	s.Require().NoError(err)
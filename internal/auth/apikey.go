@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// apiKeyPrefix tells the API keys apart from other bearer tokens.
+const apiKeyPrefix = "sk_"
+
+// ApiKey is a newly generated API key. Only Prefix and Hash are stored, Key is
+// shown to its owner once and cannot be recovered afterwards.
+type ApiKey struct {
+	Key    string
+	Prefix string
+	Hash   string
+}
+
+// NewApiKey generates an API key in the sk_<prefix>_<secret> format, the
+// prefix identifies the key and the secret proves its possession.
+func NewApiKey() (*ApiKey, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApiKey{
+		Key:    apiKeyPrefix + prefix + "_" + secret,
+		Prefix: prefix,
+		Hash:   HashSecret(secret),
+	}, nil
+}
+
+// ParseApiKey splits key into its prefix and secret, ok is false when key is
+// not an API key.
+func ParseApiKey(key string) (prefix string, secret string, ok bool) {
+	rest, found := strings.CutPrefix(key, apiKeyPrefix)
+	if !found {
+		return "", "", false
+	}
+
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || prefix == "" || secret == "" {
+		return "", "", false
+	}
+
+	return prefix, secret, true
+}
+
+// HashSecret returns the hex encoded SHA-256 of the secret. The secrets are
+// random enough for an unsalted hash to be safe.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySecret tells whether secret matches the stored hash, in constant time.
+func VerifySecret(hash string, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashSecret(secret))) == 1
+}
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,48 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApiKey(t *testing.T) {
+	t.Run("should generate a key that parses back to its prefix", func(t *testing.T) {
+		key, err := auth.NewApiKey()
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key.Key, "sk_"+key.Prefix+"_"))
+
+		prefix, secret, ok := auth.ParseApiKey(key.Key)
+		assert.True(t, ok)
+		assert.Equal(t, key.Prefix, prefix)
+		assert.True(t, auth.VerifySecret(key.Hash, secret))
+	})
+
+	t.Run("should generate a different key every time", func(t *testing.T) {
+		first, err := auth.NewApiKey()
+		assert.NoError(t, err)
+
+		second, err := auth.NewApiKey()
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first.Key, second.Key)
+		assert.NotEqual(t, first.Prefix, second.Prefix)
+	})
+}
+
+func TestParseApiKey(t *testing.T) {
+	for _, key := range []string{"", "sk_", "sk_abc", "sk_abc_", "sk__secret", "pk_abc_secret"} {
+		t.Run("should reject "+key, func(t *testing.T) {
+			_, _, ok := auth.ParseApiKey(key)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestVerifySecret(t *testing.T) {
+	t.Run("should reject a secret that does not match the hash", func(t *testing.T) {
+		assert.False(t, auth.VerifySecret(auth.HashSecret("secret"), "other"))
+	})
+}
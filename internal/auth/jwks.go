@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minKeySetRefresh limits how often an unknown key id refreshes the key set,
+// so tokens signed by unknown keys cannot hammer the source.
+const minKeySetRefresh = time.Minute
+
+var ErrUnknownKey = errors.New("token is signed by an unknown key")
+
+// KeySet is a JSON Web Key Set (RFC 7517), loaded from a file or a URL. The
+// keys are cached and loaded again once they get older than the refresh
+// interval, or when a token is signed by a key they do not have, which is how
+// the key rotations of the issuer are picked up. Stale keys are still served
+// while they are loaded again in the background, and concurrent requests
+// share a single load.
+type KeySet struct {
+	load            func(ctx context.Context) ([]byte, error)
+	refreshInterval time.Duration
+
+	mu         sync.RWMutex
+	keys       map[string]crypto.PublicKey
+	fetched    time.Time
+	refreshing *keySetRefresh
+}
+
+// keySetRefresh is a load of the key set in flight, done is closed once it is
+// over.
+type keySetRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewFileKeySet returns the key set of the file at path.
+func NewFileKeySet(path string, refreshInterval time.Duration) *KeySet {
+	return &KeySet{
+		load: func(_ context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+		refreshInterval: refreshInterval,
+	}
+}
+
+// NewURLKeySet returns the key set served at url, e.g. the jwks_uri of an
+// OpenID provider.
+func NewURLKeySet(url string, client *http.Client, refreshInterval time.Duration) *KeySet {
+	return &KeySet{
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("key set request returned %d", res.StatusCode)
+			}
+
+			return io.ReadAll(io.LimitReader(res.Body, 1<<20))
+		},
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Key returns the public key with the key id kid.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	key, found := s.keys[kid]
+	fetched := s.fetched
+	s.mu.RUnlock()
+
+	age := time.Since(fetched)
+	stale := age > s.refreshInterval
+
+	if found {
+		// a stale key is refreshed without holding up the request, a failed
+		// refresh keeps the keys loaded before, so the tokens signed by them
+		// are still accepted while the source is down
+		if stale {
+			s.refresh(ctx, fetched)
+		}
+		return key, nil
+	}
+
+	if !stale && age < minKeySetRefresh {
+		return nil, ErrUnknownKey
+	}
+
+	select {
+	case <-s.refresh(ctx, fetched).done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, found = s.keys[kid]
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// Refresh loads the key set from its source. Keys that are not for signatures
+// or of unsupported types are skipped.
+func (s *KeySet) Refresh(ctx context.Context) error {
+	s.mu.RLock()
+	fetched := s.fetched
+	s.mu.RUnlock()
+
+	refresh := s.refresh(ctx, fetched)
+
+	select {
+	case <-refresh.done:
+		return refresh.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refresh starts loading the key set, unless a load is already in flight, which
+// is returned instead. A key set loaded again since fetched is not loaded.
+// The load runs in the background, the keys are only locked to be replaced, so
+// the requests are served the cached keys meanwhile.
+func (s *KeySet) refresh(ctx context.Context, fetched time.Time) *keySetRefresh {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refreshing != nil {
+		return s.refreshing
+	}
+
+	refresh := &keySetRefresh{done: make(chan struct{})}
+	if !s.fetched.Equal(fetched) {
+		close(refresh.done)
+		return refresh
+	}
+
+	s.refreshing = refresh
+
+	// the load is shared, so it is not canceled along with the request that
+	// started it
+	go s.reload(context.WithoutCancel(ctx), refresh)
+
+	return refresh
+}
+
+func (s *KeySet) reload(ctx context.Context, refresh *keySetRefresh) {
+	defer close(refresh.done)
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		slog.Error("failed to refresh the key set", err.Error(), err)
+		refresh.err = err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetched = time.Now()
+	s.refreshing = nil
+	if err == nil {
+		s.keys = keys
+	}
+}
+
+func (s *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	body, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the key set: %w", err)
+	}
+
+	return parseKeySet(body)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func parseKeySet(body []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("malformed key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			slog.Warn("skipping key of the key set", "kid", jwk.Kid, "error", err.Error())
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("malformed Ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("malformed key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
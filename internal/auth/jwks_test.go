@@ -0,0 +1,167 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySet_Key(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var (
+		served   atomic.Value
+		requests atomic.Int32
+		failing  atomic.Bool
+	)
+
+	served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(served.Load().([]byte))
+	}))
+	defer server.Close()
+
+	t.Run("should cache the keys", func(t *testing.T) {
+		requests.Store(0)
+		keys := auth.NewURLKeySet(server.URL, server.Client(), time.Hour)
+
+		for range 3 {
+			key, err := keys.Key(context.Background(), "first")
+			assert.NoError(t, err)
+			assert.True(t, first.PublicKey.Equal(key))
+		}
+
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("should pick up rotated keys once the keys are stale", func(t *testing.T) {
+		served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+		keys := auth.NewURLKeySet(server.URL, server.Client(), 0)
+
+		_, err := keys.Key(context.Background(), "first")
+		assert.NoError(t, err)
+
+		served.Store(keySet(t, map[string]crypto.PublicKey{"second": &second.PublicKey}))
+
+		key, err := keys.Key(context.Background(), "second")
+		assert.NoError(t, err)
+		assert.True(t, second.PublicKey.Equal(key))
+
+		_, err = keys.Key(context.Background(), "first")
+		assert.ErrorIs(t, err, auth.ErrUnknownKey)
+	})
+
+	t.Run("should keep the keys when the refresh fails", func(t *testing.T) {
+		served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+		keys := auth.NewURLKeySet(server.URL, server.Client(), 0)
+
+		_, err := keys.Key(context.Background(), "first")
+		assert.NoError(t, err)
+
+		failing.Store(true)
+		defer failing.Store(false)
+
+		key, err := keys.Key(context.Background(), "first")
+		assert.NoError(t, err)
+		assert.True(t, first.PublicKey.Equal(key))
+	})
+
+	t.Run("should not refresh for every unknown key", func(t *testing.T) {
+		served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+		keys := auth.NewURLKeySet(server.URL, server.Client(), time.Hour)
+
+		assert.NoError(t, keys.Refresh(context.Background()))
+		requests.Store(0)
+
+		for range 3 {
+			_, err := keys.Key(context.Background(), "unknown")
+			assert.ErrorIs(t, err, auth.ErrUnknownKey)
+		}
+
+		assert.Equal(t, int32(0), requests.Load())
+	})
+
+	t.Run("should serve the stale keys while refreshing them", func(t *testing.T) {
+		served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+
+		var loads atomic.Int32
+		refreshing := make(chan struct{}, 1)
+		release := make(chan struct{})
+
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if loads.Add(1) > 1 {
+				refreshing <- struct{}{}
+				<-release
+			}
+			w.Write(served.Load().([]byte))
+		}))
+		defer slow.Close()
+		defer close(release)
+
+		keys := auth.NewURLKeySet(slow.URL, slow.Client(), 0)
+
+		assert.NoError(t, keys.Refresh(context.Background()))
+
+		_, err := keys.Key(context.Background(), "first")
+		assert.NoError(t, err)
+
+		select {
+		case <-refreshing:
+		case <-time.After(time.Second):
+			t.Fatal("the stale keys were not refreshed")
+		}
+
+		for range 3 {
+			key, err := keys.Key(context.Background(), "first")
+			assert.NoError(t, err)
+			assert.True(t, first.PublicKey.Equal(key))
+		}
+
+		assert.Equal(t, int32(2), loads.Load())
+	})
+
+	t.Run("should share the refresh between concurrent requests", func(t *testing.T) {
+		served.Store(keySet(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+		keys := auth.NewURLKeySet(server.URL, server.Client(), time.Hour)
+		requests.Store(0)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				key, err := keys.Key(context.Background(), "first")
+				assert.NoError(t, err)
+				assert.True(t, first.PublicKey.Equal(key))
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("return error when the key set is malformed", func(t *testing.T) {
+		served.Store([]byte("not json"))
+		keys := auth.NewURLKeySet(server.URL, server.Client(), time.Hour)
+
+		assert.Error(t, keys.Refresh(context.Background()))
+	})
+}
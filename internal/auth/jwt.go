@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// tokenLeeway tolerates the clock skew between the issuer and the API.
+const tokenLeeway = 30 * time.Second
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenOptions tells which tokens are accepted and where their claims are.
+// The claims are paths of the payload, e.g. realm_access.roles.
+type TokenOptions struct {
+	Issuer         string
+	Audience       string
+	WorkspaceClaim string
+	RolesClaim     string
+}
+
+// TokenVerifier verifies the JWTs issued to the users against a key set and
+// maps their claims to a principal.
+type TokenVerifier struct {
+	keys    *KeySet
+	parser  *jwt.Parser
+	options TokenOptions
+}
+
+func NewTokenVerifier(keys *KeySet, options TokenOptions) *TokenVerifier {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(options.Issuer),
+		jwt.WithAudience(options.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tokenLeeway),
+	)
+
+	return &TokenVerifier{keys: keys, parser: parser, options: options}
+}
+
+// Verify checks the signature, expiration, issuer and audience of token and
+// returns its principal. The user id is the sub claim and the scopes are the
+// space separated scope claim, the way OAuth 2.0 grants them. Every failure is
+// an ErrInvalidToken.
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no sub claim", ErrInvalidToken)
+	}
+
+	workspace, _ := claimValue(claims, v.options.WorkspaceClaim).(string)
+	workspaceID, err := uuid.Parse(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token has no valid %s claim", ErrInvalidToken, v.options.WorkspaceClaim)
+	}
+
+	return &Principal{
+		Subject:     "user:" + subject,
+		UserID:      subject,
+		WorkspaceID: workspaceID,
+		Scopes:      claimStrings(claimValue(claims, "scope")),
+		Roles:       claimStrings(claimValue(claims, v.options.RolesClaim)),
+	}, nil
+}
+
+// claimValue returns the claim at the dotted path, or nil if there is none.
+func claimValue(claims jwt.MapClaims, path string) any {
+	var value any = map[string]any(claims)
+
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	return value
+}
+
+// claimStrings reads a claim that is either an array of strings or a space
+// separated string.
+func claimStrings(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
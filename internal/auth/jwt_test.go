@@ -0,0 +1,191 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+var tokenOptions = auth.TokenOptions{
+	Issuer:         "https://issuer.example.com",
+	Audience:       "sequence-api",
+	WorkspaceClaim: "workspace_id",
+	RolesClaim:     "realm_access.roles",
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	path := writeKeySet(t, map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey, "ed": edPublic})
+	verifier := auth.NewTokenVerifier(auth.NewFileKeySet(path, time.Hour), tokenOptions)
+
+	workspaceID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		claims := validClaims(workspaceID)
+		claims["scope"] = "openid sequences:read"
+		claims["realm_access"] = map[string]any{"roles": []string{"editor"}}
+
+		principal, err := verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims))
+		assert.NoError(t, err)
+		assert.Equal(t, "user:user-1", principal.Subject)
+		assert.Equal(t, "user-1", principal.UserID)
+		assert.Equal(t, workspaceID, principal.WorkspaceID)
+		assert.Equal(t, []string{"openid", "sequences:read"}, principal.Scopes)
+		assert.Equal(t, []string{"editor"}, principal.Roles)
+	})
+
+	t.Run("should verify tokens signed with EC and Ed25519 keys", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodES256, "ec", ecKey, validClaims(workspaceID)))
+		assert.NoError(t, err)
+
+		_, err = verifier.Verify(context.Background(), signToken(t, jwt.SigningMethodEdDSA, "ed", edKey, validClaims(workspaceID)))
+		assert.NoError(t, err)
+	})
+
+	invalid := map[string]func() string{
+		"expired": func() string {
+			claims := validClaims(workspaceID)
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"without expiration": func() string {
+			claims := validClaims(workspaceID)
+			delete(claims, "exp")
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"of another issuer": func() string {
+			claims := validClaims(workspaceID)
+			claims["iss"] = "https://other.example.com"
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"of another audience": func() string {
+			claims := validClaims(workspaceID)
+			claims["aud"] = []string{"other-api"}
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"without workspace": func() string {
+			claims := validClaims(workspaceID)
+			delete(claims, "workspace_id")
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"without subject": func() string {
+			claims := validClaims(workspaceID)
+			delete(claims, "sub")
+			return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"signed by an unknown key": func() string {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			assert.NoError(t, err)
+			return signToken(t, jwt.SigningMethodRS256, "other", other, validClaims(workspaceID))
+		},
+		"signed by a key under another key id": func() string {
+			return signToken(t, jwt.SigningMethodES256, "rsa", ecKey, validClaims(workspaceID))
+		},
+		"signed with a shared secret": func() string {
+			return signToken(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims(workspaceID))
+		},
+		"not signed": func() string {
+			return signToken(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims(workspaceID))
+		},
+	}
+
+	for name, token := range invalid {
+		t.Run("return auth.ErrInvalidToken when token is "+name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), token())
+
+			assert.Nil(t, principal)
+			assert.ErrorIs(t, err, auth.ErrInvalidToken)
+		})
+	}
+}
+
+func validClaims(workspaceID uuid.UUID) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":          tokenOptions.Issuer,
+		"aud":          tokenOptions.Audience,
+		"sub":          "user-1",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"iat":          time.Now().Unix(),
+		"workspace_id": workspaceID.String(),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func writeKeySet(t *testing.T, keys map[string]crypto.PublicKey) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, keySet(t, keys), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// keySet marshals keys to a JSON Web Key Set, keyed by their key ids.
+func keySet(t *testing.T, keys map[string]crypto.PublicKey) []byte {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+
+	for kid, key := range keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": encode(key.N.Bytes()), "e": encode(big.NewInt(int64(key.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			size := (key.Curve.Params().BitSize + 7) / 8
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "EC", "kid": kid, "crv": key.Curve.Params().Name,
+				"x": encode(key.X.FillBytes(make([]byte, size))), "y": encode(key.Y.FillBytes(make([]byte, size))),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": encode(key)})
+		default:
+			t.Fatalf("unsupported key %T", key)
+		}
+	}
+
+	body, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Principal is the authenticated caller of a request. Subject identifies it,
+// e.g. api-key:<id> or user:<id>, and UserID is set for the callers with a
+// bearer token of a user only.
+type Principal struct {
+	Subject     string
+	UserID      string
+	WorkspaceID uuid.UUID
+	Scopes      []string
+	Roles       []string
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of the request, or nil when the request
+// is not authenticated.
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,21 @@
+package auth
+
+import "slices"
+
+// the scopes granted to the API keys, each route requires one of them
+const (
+	ScopeSequencesRead  = "sequences:read"
+	ScopeSequencesWrite = "sequences:write"
+	ScopeStepsWrite     = "steps:write"
+	ScopeApiKeysManage  = "api-keys:manage"
+	ScopeRolesManage    = "roles:manage"
+	ScopeAuditRead      = "audit:read"
+)
+
+// Scopes lists every known scope.
+var Scopes = []string{ScopeSequencesRead, ScopeSequencesWrite, ScopeStepsWrite, ScopeApiKeysManage, ScopeRolesManage, ScopeAuditRead}
+
+// HasScope tells whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
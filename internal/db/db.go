@@ -3,18 +3,22 @@ package db
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
 )
 
 type DB interface {
 	Queries() *dao.Queries
 	Close()
 	Tx(context.Context) (pgx.Tx, error)
+	InTx(context.Context, func(context.Context) error) error
 	Ping(context.Context) error
 }
 
@@ -22,6 +26,8 @@ type db struct {
 	pool *pgxpool.Pool
 }
 
+type txKey struct{}
+
 func New(context context.Context, cfg *config.Config) (DB, error) {
 	connString := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", cfg.PostgresUser, cfg.PostgresPassword, cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresDatabase)
 
@@ -34,6 +40,7 @@ func New(context context.Context, cfg *config.Config) (DB, error) {
 	poolConfig.MinConns = int32(cfg.MinDbConnections)
 	poolConfig.HealthCheckPeriod = 30 * time.Second
 	poolConfig.MaxConnIdleTime = time.Duration(cfg.MaxConnIdleTime) * time.Second
+	poolConfig.BeforeAcquire = setWorkspace
 
 	pool, err := pgxpool.NewWithConfig(context, poolConfig)
 	if err != nil {
@@ -43,18 +50,88 @@ func New(context context.Context, cfg *config.Config) (DB, error) {
 	return &db{pool: pool}, nil
 }
 
+// Queries runs every query in the transaction of its context when there is
+// one, see InTx, and on a connection of the pool otherwise.
 func (d *db) Queries() *dao.Queries {
-	return dao.New(d.pool)
+	return dao.New(&conn{pool: d.pool})
 }
 
 func (d *db) Close() {
 	d.pool.Close()
 }
 
+// Tx begins a transaction, or a savepoint of the transaction of the context
+// when it carries one, so the transactions of the repositories nest in InTx.
 func (d *db) Tx(context context.Context) (pgx.Tx, error) {
+	if tx, ok := context.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(context)
+	}
+
 	return d.pool.Begin(context)
 }
 
+// InTx runs fn in a transaction that is committed when fn succeeds and rolled
+// back otherwise. The queries issued with the context given to fn run in that
+// transaction. The error of fn is returned as is.
+func (d *db) InTx(ctx context.Context, fn func(context.Context) error) error {
+	tx, err := d.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (d *db) Ping(context context.Context) error {
 	return d.pool.Ping(context)
 }
+
+// conn sends the queries to the transaction of their context, or to the pool
+// when they are not part of one.
+type conn struct {
+	pool *pgxpool.Pool
+}
+
+func (c *conn) executor(ctx context.Context) dao.DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return c.pool
+}
+
+func (c *conn) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return c.executor(ctx).Exec(ctx, sql, args...)
+}
+
+func (c *conn) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return c.executor(ctx).Query(ctx, sql, args...)
+}
+
+func (c *conn) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return c.executor(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (c *conn) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return c.executor(ctx).CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// setWorkspace sets app.workspace_id, read by the row level security policies,
+// to the workspace of ctx every time a connection is acquired, so a connection
+// never keeps the workspace of the previous request. The connection is
+// discarded when the setting fails.
+func setWorkspace(ctx context.Context, conn *pgx.Conn) bool {
+	var workspaceID string
+	if id := tenancy.WorkspaceID(ctx); id != 0 {
+		workspaceID = strconv.Itoa(int(id))
+	}
+
+	_, err := conn.Exec(ctx, "select set_config('app.workspace_id', $1, false)", workspaceID)
+	return err == nil
+}
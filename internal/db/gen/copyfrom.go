@@ -34,6 +34,13 @@ func (r iteratorForCreateSteps) Values() ([]interface{}, error) {
 		r.rows[0].MailSubject,
 		r.rows[0].MailContent,
 		r.rows[0].SequenceID,
+		r.rows[0].DelayDays,
+		r.rows[0].DelayHours,
+		r.rows[0].BusinessDaysOnly,
+		r.rows[0].SendWindowStart,
+		r.rows[0].SendWindowEnd,
+		r.rows[0].MailText,
+		r.rows[0].WorkspaceID,
 	}, nil
 }
 
@@ -42,5 +49,5 @@ func (r iteratorForCreateSteps) Err() error {
 }
 
 func (q *Queries) CreateSteps(ctx context.Context, arg []CreateStepsParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"steps"}, []string{"external_id", "step_number", "mail_subject", "mail_content", "sequence_id"}, &iteratorForCreateSteps{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"steps"}, []string{"external_id", "step_number", "mail_subject", "mail_content", "sequence_id", "delay_days", "delay_hours", "business_days_only", "send_window_start", "send_window_end", "mail_text", "workspace_id"}, &iteratorForCreateSteps{rows: arg})
 }
@@ -9,6 +9,60 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID          int32            `json:"id"`
+	ExternalID  uuid.UUID        `json:"external_id"`
+	WorkspaceID int32            `json:"workspace_id"`
+	KeyName     string           `json:"key_name"`
+	KeyPrefix   string           `json:"key_prefix"`
+	KeyHash     string           `json:"key_hash"`
+	Scopes      []string         `json:"scopes"`
+	Created     pgtype.Timestamp `json:"created"`
+	LastUsedAt  pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamp `json:"revoked_at"`
+}
+
+type AuditEvent struct {
+	ID          int32            `json:"id"`
+	ExternalID  uuid.UUID        `json:"external_id"`
+	WorkspaceID int32            `json:"workspace_id"`
+	Actor       string           `json:"actor"`
+	Action      string           `json:"action"`
+	TargetType  string           `json:"target_type"`
+	TargetID    uuid.UUID        `json:"target_id"`
+	Before      []byte           `json:"before"`
+	After       []byte           `json:"after"`
+	RequestID   *string          `json:"request_id"`
+	Created     pgtype.Timestamp `json:"created"`
+}
+
+type IdempotencyKey struct {
+	IdempotencyKey  string           `json:"idempotency_key"`
+	RequestHash     string           `json:"request_hash"`
+	StatusCode      *int32           `json:"status_code"`
+	ResponseHeaders []byte           `json:"response_headers"`
+	ResponseBody    []byte           `json:"response_body"`
+	Created         pgtype.Timestamp `json:"created"`
+	WorkspaceID     int32            `json:"workspace_id"`
+}
+
+type RateLimitBucket struct {
+	BucketKey string           `json:"bucket_key"`
+	Tokens    float64          `json:"tokens"`
+	Allowed   bool             `json:"allowed"`
+	Updated   pgtype.Timestamp `json:"updated"`
+}
+
+type RoleBinding struct {
+	ID          int32            `json:"id"`
+	ExternalID  uuid.UUID        `json:"external_id"`
+	WorkspaceID int32            `json:"workspace_id"`
+	UserID      string           `json:"user_id"`
+	Role        string           `json:"role"`
+	SequenceID  *int32           `json:"sequence_id"`
+	Created     pgtype.Timestamp `json:"created"`
+}
+
 type Sequence struct {
 	ID                   int32            `json:"id"`
 	ExternalID           uuid.UUID        `json:"external_id"`
@@ -17,13 +71,66 @@ type Sequence struct {
 	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
 	Created              pgtype.Timestamp `json:"created"`
 	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+}
+
+type SequenceRevision struct {
+	ID          int32            `json:"id"`
+	SequenceID  int32            `json:"sequence_id"`
+	Revision    int32            `json:"revision"`
+	Snapshot    []byte           `json:"snapshot"`
+	Created     pgtype.Timestamp `json:"created"`
+	WorkspaceID int32            `json:"workspace_id"`
 }
 
 type Step struct {
-	ID          int32     `json:"id"`
-	ExternalID  uuid.UUID `json:"external_id"`
-	MailSubject string    `json:"mail_subject"`
-	MailContent string    `json:"mail_content"`
-	StepNumber  int32     `json:"step_number"`
-	SequenceID  int32     `json:"sequence_id"`
+	ID               int32            `json:"id"`
+	ExternalID       uuid.UUID        `json:"external_id"`
+	MailSubject      string           `json:"mail_subject"`
+	MailContent      string           `json:"mail_content"`
+	StepNumber       int32            `json:"step_number"`
+	SequenceID       int32            `json:"sequence_id"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	DelayDays        int32            `json:"delay_days"`
+	DelayHours       int32            `json:"delay_hours"`
+	BusinessDaysOnly bool             `json:"business_days_only"`
+	SendWindowStart  *int32           `json:"send_window_start"`
+	SendWindowEnd    *int32           `json:"send_window_end"`
+	MailText         string           `json:"mail_text"`
+	Version          int32            `json:"version"`
+	WorkspaceID      int32            `json:"workspace_id"`
+}
+
+type StepVariant struct {
+	ID          int32            `json:"id"`
+	ExternalID  uuid.UUID        `json:"external_id"`
+	StepID      int32            `json:"step_id"`
+	MailSubject string           `json:"mail_subject"`
+	MailContent string           `json:"mail_content"`
+	Weight      int32            `json:"weight"`
+	Created     pgtype.Timestamp `json:"created"`
+	WorkspaceID int32            `json:"workspace_id"`
+}
+
+type StepVariantAssignment struct {
+	ID                int32            `json:"id"`
+	EnrollmentID      uuid.UUID        `json:"enrollment_id"`
+	StepID            *int32           `json:"step_id"`
+	VariantID         *int32           `json:"variant_id"`
+	Assigned          pgtype.Timestamp `json:"assigned"`
+	WorkspaceID       int32            `json:"workspace_id"`
+	StepExternalID    uuid.UUID        `json:"step_external_id"`
+	VariantExternalID uuid.UUID        `json:"variant_external_id"`
+}
+
+type Workspace struct {
+	ID            int32            `json:"id"`
+	ExternalID    uuid.UUID        `json:"external_id"`
+	WorkspaceName string           `json:"workspace_name"`
+	Created       pgtype.Timestamp `json:"created"`
 }
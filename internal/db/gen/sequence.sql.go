@@ -12,20 +12,120 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cloneSequence = `-- name: CloneSequence :one
+INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled, variables, source_sequence_id, workspace_id) 
+SELECT $1::varchar, $2::boolean, $3::boolean, variables, external_id, workspace_id FROM sequences 
+WHERE id = $4 AND workspace_id = $5 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
+`
+
+type CloneSequenceParams struct {
+	SequenceName         string `json:"sequence_name"`
+	OpenTrackingEnabled  bool   `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool   `json:"click_tracking_enabled"`
+	SourceID             int32  `json:"source_id"`
+	WorkspaceID          int32  `json:"workspace_id"`
+}
+
+func (q *Queries) CloneSequence(ctx context.Context, arg CloneSequenceParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, cloneSequence,
+		arg.SequenceName,
+		arg.OpenTrackingEnabled,
+		arg.ClickTrackingEnabled,
+		arg.SourceID,
+		arg.WorkspaceID,
+	)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const countSequences = `-- name: CountSequences :one
+select count(*) from sequences s
+where s.workspace_id = $1 and s.deleted_at is null
+		and ($2::varchar is null or s.sequence_name ilike '%' || $2::varchar || '%')
+		and ($3::boolean is null or s.open_tracking_enabled = $3::boolean)
+		and ($4::boolean is null or s.click_tracking_enabled = $4::boolean)
+		and ($5::timestamp is null or s.created >= $5::timestamp)
+		and ($6::timestamp is null or s.created < $6::timestamp)
+		and ($7::timestamp is null or s.updated >= $7::timestamp)
+		and ($8::timestamp is null or s.updated < $8::timestamp)
+		and (
+			$9::varchar is null
+			or exists (
+				select 1 from steps fs
+				where fs.sequence_id = s.id
+					and fs.deleted_at is null
+					and to_tsvector('simple', fs.mail_subject || ' ' || coalesce(fs.mail_content, '')) @@ websearch_to_tsquery('simple', $9::varchar)
+			)
+		)
+`
+
+type CountSequencesParams struct {
+	WorkspaceID          int32            `json:"workspace_id"`
+	Name                 *string          `json:"name"`
+	OpenTrackingEnabled  *bool            `json:"open_tracking_enabled"`
+	ClickTrackingEnabled *bool            `json:"click_tracking_enabled"`
+	CreatedAfter         pgtype.Timestamp `json:"created_after"`
+	CreatedBefore        pgtype.Timestamp `json:"created_before"`
+	UpdatedAfter         pgtype.Timestamp `json:"updated_after"`
+	UpdatedBefore        pgtype.Timestamp `json:"updated_before"`
+	Search               *string          `json:"search"`
+}
+
+func (q *Queries) CountSequences(ctx context.Context, arg CountSequencesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSequences,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.OpenTrackingEnabled,
+		arg.ClickTrackingEnabled,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.Search,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSequence = `-- name: CreateSequence :one
-INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled) 
-VALUES ($1, $2, $3) 
-RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated
+INSERT INTO sequences (sequence_name, open_tracking_enabled, click_tracking_enabled, variables, workspace_id) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
 `
 
 type CreateSequenceParams struct {
 	SequenceName         string `json:"sequence_name"`
 	OpenTrackingEnabled  bool   `json:"open_tracking_enabled"`
 	ClickTrackingEnabled bool   `json:"click_tracking_enabled"`
+	Variables            []byte `json:"variables"`
+	WorkspaceID          int32  `json:"workspace_id"`
 }
 
 func (q *Queries) CreateSequence(ctx context.Context, arg CreateSequenceParams) (Sequence, error) {
-	row := q.db.QueryRow(ctx, createSequence, arg.SequenceName, arg.OpenTrackingEnabled, arg.ClickTrackingEnabled)
+	row := q.db.QueryRow(ctx, createSequence,
+		arg.SequenceName,
+		arg.OpenTrackingEnabled,
+		arg.ClickTrackingEnabled,
+		arg.Variables,
+		arg.WorkspaceID,
+	)
 	var i Sequence
 	err := row.Scan(
 		&i.ID,
@@ -35,27 +135,305 @@ func (q *Queries) CreateSequence(ctx context.Context, arg CreateSequenceParams)
 		&i.ClickTrackingEnabled,
 		&i.Created,
 		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
 
-const deleteSequence = `-- name: DeleteSequence :exec
-DELETE FROM sequences 
-WHERE external_id = $1
+const deleteSequence = `-- name: DeleteSequence :one
+UPDATE sequences 
+SET deleted_at = now() 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
+`
+
+type DeleteSequenceParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) DeleteSequence(ctx context.Context, arg DeleteSequenceParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, deleteSequence, arg.ExternalID, arg.WorkspaceID)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const deleteSequenceSteps = `-- name: DeleteSequenceSteps :exec
+UPDATE steps 
+SET deleted_at = $2 
+WHERE sequence_id = $1 AND workspace_id = $3 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteSequence(ctx context.Context, externalID uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteSequence, externalID)
+type DeleteSequenceStepsParams struct {
+	SequenceID  int32            `json:"sequence_id"`
+	DeletedAt   pgtype.Timestamp `json:"deleted_at"`
+	WorkspaceID int32            `json:"workspace_id"`
+}
+
+func (q *Queries) DeleteSequenceSteps(ctx context.Context, arg DeleteSequenceStepsParams) error {
+	_, err := q.db.Exec(ctx, deleteSequenceSteps, arg.SequenceID, arg.DeletedAt, arg.WorkspaceID)
 	return err
 }
 
+const getDeletedSequenceById = `-- name: GetDeletedSequenceById :one
+select 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id
+where s.external_id = $1 and s.workspace_id = $2 and s.deleted_at is not null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+`
+
+type GetDeletedSequenceByIdParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+type GetDeletedSequenceByIdRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetDeletedSequenceById(ctx context.Context, arg GetDeletedSequenceByIdParams) (GetDeletedSequenceByIdRow, error) {
+	row := q.db.QueryRow(ctx, getDeletedSequenceById, arg.ExternalID, arg.WorkspaceID)
+	var i GetDeletedSequenceByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+		&i.Steps,
+	)
+	return i, err
+}
+
+const getDeletedSequences = `-- name: GetDeletedSequences :many
+select 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id
+where s.workspace_id = $1 and s.deleted_at is not null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.deleted_at desc, s.id
+limit $2
+offset $3
+`
+
+type GetDeletedSequencesParams struct {
+	WorkspaceID int32 `json:"workspace_id"`
+	Limit       int32 `json:"limit"`
+	Offset      int32 `json:"offset"`
+}
+
+type GetDeletedSequencesRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetDeletedSequences(ctx context.Context, arg GetDeletedSequencesParams) ([]GetDeletedSequencesRow, error) {
+	rows, err := q.db.Query(ctx, getDeletedSequences, arg.WorkspaceID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDeletedSequencesRow
+	for rows.Next() {
+		var i GetDeletedSequencesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.SequenceName,
+			&i.OpenTrackingEnabled,
+			&i.ClickTrackingEnabled,
+			&i.Created,
+			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
+			&i.Steps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredSequences = `-- name: GetExpiredSequences :many
+select 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+    json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id
+where s.workspace_id = $1 and s.deleted_at is not null and s.deleted_at < now() - make_interval(secs => $2::float8)
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.deleted_at, s.id
+`
+
+type GetExpiredSequencesParams struct {
+	WorkspaceID      int32   `json:"workspace_id"`
+	RetentionSeconds float64 `json:"retention_seconds"`
+}
+
+type GetExpiredSequencesRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetExpiredSequences(ctx context.Context, arg GetExpiredSequencesParams) ([]GetExpiredSequencesRow, error) {
+	rows, err := q.db.Query(ctx, getExpiredSequences, arg.WorkspaceID, arg.RetentionSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExpiredSequencesRow
+	for rows.Next() {
+		var i GetExpiredSequencesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.SequenceName,
+			&i.OpenTrackingEnabled,
+			&i.ClickTrackingEnabled,
+			&i.Created,
+			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
+			&i.Steps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSequenceById = `-- name: GetSequenceById :one
 select 
-    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
 	json_agg(row_to_json(t))::jsonb steps 
 from sequences s
-left join steps t on t.sequence_id = s.id
-where s.external_id = $1
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.external_id = $1 and s.workspace_id = $2 and s.deleted_at is null
 group by
 	s.id,
 	s.external_id,
@@ -63,9 +441,20 @@ group by
 	s.open_tracking_enabled,
 	s.click_tracking_enabled,
 	s.created,
-	s.updated
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
 `
 
+type GetSequenceByIdParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
 type GetSequenceByIdRow struct {
 	ID                   int32            `json:"id"`
 	ExternalID           uuid.UUID        `json:"external_id"`
@@ -74,11 +463,17 @@ type GetSequenceByIdRow struct {
 	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
 	Created              pgtype.Timestamp `json:"created"`
 	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
 	Steps                []byte           `json:"steps"`
 }
 
-func (q *Queries) GetSequenceById(ctx context.Context, externalID uuid.UUID) (GetSequenceByIdRow, error) {
-	row := q.db.QueryRow(ctx, getSequenceById, externalID)
+func (q *Queries) GetSequenceById(ctx context.Context, arg GetSequenceByIdParams) (GetSequenceByIdRow, error) {
+	row := q.db.QueryRow(ctx, getSequenceById, arg.ExternalID, arg.WorkspaceID)
 	var i GetSequenceByIdRow
 	err := row.Scan(
 		&i.ID,
@@ -88,17 +483,56 @@ func (q *Queries) GetSequenceById(ctx context.Context, externalID uuid.UUID) (Ge
 		&i.ClickTrackingEnabled,
 		&i.Created,
 		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
 		&i.Steps,
 	)
 	return i, err
 }
 
+const getSequenceForUpdate = `-- name: GetSequenceForUpdate :one
+SELECT id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id FROM sequences 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+FOR UPDATE
+`
+
+type GetSequenceForUpdateParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) GetSequenceForUpdate(ctx context.Context, arg GetSequenceForUpdateParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, getSequenceForUpdate, arg.ExternalID, arg.WorkspaceID)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
 const getSequences = `-- name: GetSequences :many
 select 
-    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
     json_agg(row_to_json(t))::jsonb steps 
 from sequences s
-left join steps t on t.sequence_id = s.id
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.workspace_id = $1 and s.deleted_at is null
 group by
 	s.id,
 	s.external_id,
@@ -106,15 +540,22 @@ group by
 	s.open_tracking_enabled,
 	s.click_tracking_enabled,
 	s.created,
-	s.updated
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
 order by s.id
-limit $1
-offset $2
+limit $2
+offset $3
 `
 
 type GetSequencesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	WorkspaceID int32 `json:"workspace_id"`
+	Limit       int32 `json:"limit"`
+	Offset      int32 `json:"offset"`
 }
 
 type GetSequencesRow struct {
@@ -125,11 +566,17 @@ type GetSequencesRow struct {
 	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
 	Created              pgtype.Timestamp `json:"created"`
 	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
 	Steps                []byte           `json:"steps"`
 }
 
 func (q *Queries) GetSequences(ctx context.Context, arg GetSequencesParams) ([]GetSequencesRow, error) {
-	rows, err := q.db.Query(ctx, getSequences, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getSequences, arg.WorkspaceID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +592,330 @@ func (q *Queries) GetSequences(ctx context.Context, arg GetSequencesParams) ([]G
 			&i.ClickTrackingEnabled,
 			&i.Created,
 			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
+			&i.Steps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSequencesByExternalIds = `-- name: GetSequencesByExternalIds :many
+select 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+	json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.external_id = any($1::uuid[]) and s.workspace_id = $2 and s.deleted_at is null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.id
+`
+
+type GetSequencesByExternalIdsParams struct {
+	ExternalIds []uuid.UUID `json:"external_ids"`
+	WorkspaceID int32       `json:"workspace_id"`
+}
+
+type GetSequencesByExternalIdsRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetSequencesByExternalIds(ctx context.Context, arg GetSequencesByExternalIdsParams) ([]GetSequencesByExternalIdsRow, error) {
+	rows, err := q.db.Query(ctx, getSequencesByExternalIds, arg.ExternalIds, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSequencesByExternalIdsRow
+	for rows.Next() {
+		var i GetSequencesByExternalIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.SequenceName,
+			&i.OpenTrackingEnabled,
+			&i.ClickTrackingEnabled,
+			&i.Created,
+			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
+			&i.Steps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSequencesByNames = `-- name: GetSequencesByNames :many
+select 
+    s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+	json_agg(row_to_json(t))::jsonb steps 
+from sequences s
+left join steps t on t.sequence_id = s.id and t.deleted_at is null
+where s.sequence_name = any($1::text[]) and s.workspace_id = $2 and s.deleted_at is null
+group by
+	s.id,
+	s.external_id,
+	s.sequence_name,
+	s.open_tracking_enabled,
+	s.click_tracking_enabled,
+	s.created,
+	s.updated,
+	s.deleted_at,
+	s.variables,
+	s.status,
+	s.version,
+	s.source_sequence_id,
+	s.workspace_id
+order by s.id
+`
+
+type GetSequencesByNamesParams struct {
+	Names       []string `json:"names"`
+	WorkspaceID int32    `json:"workspace_id"`
+}
+
+type GetSequencesByNamesRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetSequencesByNames(ctx context.Context, arg GetSequencesByNamesParams) ([]GetSequencesByNamesRow, error) {
+	rows, err := q.db.Query(ctx, getSequencesByNames, arg.Names, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSequencesByNamesRow
+	for rows.Next() {
+		var i GetSequencesByNamesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.SequenceName,
+			&i.OpenTrackingEnabled,
+			&i.ClickTrackingEnabled,
+			&i.Created,
+			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
+			&i.Steps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSequencesPage = `-- name: GetSequencesPage :many
+with filtered as (
+	select 
+		s.id, s.external_id, s.sequence_name, s.open_tracking_enabled, s.click_tracking_enabled, s.created, s.updated, s.deleted_at, s.variables, s.status, s.version, s.source_sequence_id, s.workspace_id, 
+		count(t.id)::integer step_count,
+		coalesce(s.updated, s.created)::timestamp last_modified,
+		json_agg(row_to_json(t))::jsonb steps 
+	from sequences s
+	left join steps t on t.sequence_id = s.id and t.deleted_at is null
+	where s.workspace_id = $1 and s.deleted_at is null
+		and ($2::varchar is null or s.sequence_name ilike '%' || $2::varchar || '%')
+		and ($3::boolean is null or s.open_tracking_enabled = $3::boolean)
+		and ($4::boolean is null or s.click_tracking_enabled = $4::boolean)
+		and ($5::timestamp is null or s.created >= $5::timestamp)
+		and ($6::timestamp is null or s.created < $6::timestamp)
+		and ($7::timestamp is null or s.updated >= $7::timestamp)
+		and ($8::timestamp is null or s.updated < $8::timestamp)
+		and (
+			$9::varchar is null
+			or exists (
+				select 1 from steps fs
+				where fs.sequence_id = s.id
+					and fs.deleted_at is null
+					and to_tsvector('simple', fs.mail_subject || ' ' || coalesce(fs.mail_content, '')) @@ websearch_to_tsquery('simple', $9::varchar)
+			)
+		)
+	group by
+		s.id,
+		s.external_id,
+		s.sequence_name,
+		s.open_tracking_enabled,
+		s.click_tracking_enabled,
+		s.created,
+		s.updated,
+		s.deleted_at,
+		s.variables,
+		s.status,
+		s.version,
+		s.source_sequence_id,
+		s.workspace_id
+)
+select 
+	id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id, steps
+from filtered
+where
+	$10::integer is null
+	or ($11::text = 'name' and $12::boolean and (sequence_name, id) > ($13::varchar, $10::integer))
+	or ($11::text = 'name' and not $12::boolean and (sequence_name, id) < ($13::varchar, $10::integer))
+	or ($11::text = 'created' and $12::boolean and (created, id) > ($14::timestamp, $10::integer))
+	or ($11::text = 'created' and not $12::boolean and (created, id) < ($14::timestamp, $10::integer))
+	or ($11::text = 'updated' and $12::boolean and (last_modified, id) > ($14::timestamp, $10::integer))
+	or ($11::text = 'updated' and not $12::boolean and (last_modified, id) < ($14::timestamp, $10::integer))
+	or ($11::text = 'stepCount' and $12::boolean and (step_count, id) > ($15::integer, $10::integer))
+	or ($11::text = 'stepCount' and not $12::boolean and (step_count, id) < ($15::integer, $10::integer))
+order by
+	case when $11::text = 'name' and $12::boolean then sequence_name end,
+	case when $11::text = 'name' and not $12::boolean then sequence_name end desc,
+	case when $11::text = 'created' and $12::boolean then created end,
+	case when $11::text = 'created' and not $12::boolean then created end desc,
+	case when $11::text = 'updated' and $12::boolean then last_modified end,
+	case when $11::text = 'updated' and not $12::boolean then last_modified end desc,
+	case when $11::text = 'stepCount' and $12::boolean then step_count end,
+	case when $11::text = 'stepCount' and not $12::boolean then step_count end desc,
+	case when $12::boolean then id end,
+	case when not $12::boolean then id end desc
+limit $16
+`
+
+type GetSequencesPageParams struct {
+	WorkspaceID          int32            `json:"workspace_id"`
+	Name                 *string          `json:"name"`
+	OpenTrackingEnabled  *bool            `json:"open_tracking_enabled"`
+	ClickTrackingEnabled *bool            `json:"click_tracking_enabled"`
+	CreatedAfter         pgtype.Timestamp `json:"created_after"`
+	CreatedBefore        pgtype.Timestamp `json:"created_before"`
+	UpdatedAfter         pgtype.Timestamp `json:"updated_after"`
+	UpdatedBefore        pgtype.Timestamp `json:"updated_before"`
+	Search               *string          `json:"search"`
+	CursorID             *int32           `json:"cursor_id"`
+	SortBy               string           `json:"sort_by"`
+	After                bool             `json:"after"`
+	CursorName           *string          `json:"cursor_name"`
+	CursorTime           pgtype.Timestamp `json:"cursor_time"`
+	CursorCount          *int32           `json:"cursor_count"`
+	PageLimit            int32            `json:"page_limit"`
+}
+
+type GetSequencesPageRow struct {
+	ID                   int32            `json:"id"`
+	ExternalID           uuid.UUID        `json:"external_id"`
+	SequenceName         string           `json:"sequence_name"`
+	OpenTrackingEnabled  bool             `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool             `json:"click_tracking_enabled"`
+	Created              pgtype.Timestamp `json:"created"`
+	Updated              pgtype.Timestamp `json:"updated"`
+	DeletedAt            pgtype.Timestamp `json:"deleted_at"`
+	Variables            []byte           `json:"variables"`
+	Status               string           `json:"status"`
+	Version              int32            `json:"version"`
+	SourceSequenceID     *uuid.UUID       `json:"source_sequence_id"`
+	WorkspaceID          int32            `json:"workspace_id"`
+	Steps                []byte           `json:"steps"`
+}
+
+func (q *Queries) GetSequencesPage(ctx context.Context, arg GetSequencesPageParams) ([]GetSequencesPageRow, error) {
+	rows, err := q.db.Query(ctx, getSequencesPage,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.OpenTrackingEnabled,
+		arg.ClickTrackingEnabled,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.Search,
+		arg.CursorID,
+		arg.SortBy,
+		arg.After,
+		arg.CursorName,
+		arg.CursorTime,
+		arg.CursorCount,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSequencesPageRow
+	for rows.Next() {
+		var i GetSequencesPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.SequenceName,
+			&i.OpenTrackingEnabled,
+			&i.ClickTrackingEnabled,
+			&i.Created,
+			&i.Updated,
+			&i.DeletedAt,
+			&i.Variables,
+			&i.Status,
+			&i.Version,
+			&i.SourceSequenceID,
+			&i.WorkspaceID,
 			&i.Steps,
 		); err != nil {
 			return nil, err
@@ -157,21 +928,213 @@ func (q *Queries) GetSequences(ctx context.Context, arg GetSequencesParams) ([]G
 	return items, nil
 }
 
+const incrementSequenceVersion = `-- name: IncrementSequenceVersion :one
+UPDATE sequences 
+SET version = version + 1 
+WHERE id = $1 AND workspace_id = $2 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
+`
+
+type IncrementSequenceVersionParams struct {
+	ID          int32 `json:"id"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) IncrementSequenceVersion(ctx context.Context, arg IncrementSequenceVersionParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, incrementSequenceVersion, arg.ID, arg.WorkspaceID)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const lockSequence = `-- name: LockSequence :one
+SELECT id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id FROM sequences 
+WHERE id = $1 AND workspace_id = $2 
+FOR UPDATE
+`
+
+type LockSequenceParams struct {
+	ID          int32 `json:"id"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) LockSequence(ctx context.Context, arg LockSequenceParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, lockSequence, arg.ID, arg.WorkspaceID)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const purgeDeletedSequences = `-- name: PurgeDeletedSequences :many
+DELETE FROM sequences 
+WHERE workspace_id = $1 AND deleted_at IS NOT NULL AND external_id = ANY($2::uuid[]) 
+RETURNING external_id
+`
+
+type PurgeDeletedSequencesParams struct {
+	WorkspaceID int32       `json:"workspace_id"`
+	ExternalIds []uuid.UUID `json:"external_ids"`
+}
+
+func (q *Queries) PurgeDeletedSequences(ctx context.Context, arg PurgeDeletedSequencesParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedSequences, arg.WorkspaceID, arg.ExternalIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var external_id uuid.UUID
+		if err := rows.Scan(&external_id); err != nil {
+			return nil, err
+		}
+		items = append(items, external_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreSequence = `-- name: RestoreSequence :one
+UPDATE sequences 
+SET deleted_at = NULL 
+WHERE external_id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
+`
+
+type RestoreSequenceParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) RestoreSequence(ctx context.Context, arg RestoreSequenceParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, restoreSequence, arg.ExternalID, arg.WorkspaceID)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const restoreSequenceSteps = `-- name: RestoreSequenceSteps :exec
+UPDATE steps 
+SET deleted_at = NULL 
+WHERE sequence_id = $1 AND workspace_id = $2
+`
+
+type RestoreSequenceStepsParams struct {
+	SequenceID  int32 `json:"sequence_id"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) RestoreSequenceSteps(ctx context.Context, arg RestoreSequenceStepsParams) error {
+	_, err := q.db.Exec(ctx, restoreSequenceSteps, arg.SequenceID, arg.WorkspaceID)
+	return err
+}
+
 const updateSequence = `-- name: UpdateSequence :one
 UPDATE sequences 
-SET open_tracking_enabled = $2, click_tracking_enabled = $3 
-WHERE id = $1 
-RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated
+SET sequence_name = $2, open_tracking_enabled = $3, click_tracking_enabled = $4, variables = $5, version = version + 1 
+WHERE id = $1 AND version = $6 AND workspace_id = $7 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
 `
 
 type UpdateSequenceParams struct {
-	ID                   int32 `json:"id"`
-	OpenTrackingEnabled  bool  `json:"open_tracking_enabled"`
-	ClickTrackingEnabled bool  `json:"click_tracking_enabled"`
+	ID                   int32  `json:"id"`
+	SequenceName         string `json:"sequence_name"`
+	OpenTrackingEnabled  bool   `json:"open_tracking_enabled"`
+	ClickTrackingEnabled bool   `json:"click_tracking_enabled"`
+	Variables            []byte `json:"variables"`
+	Version              int32  `json:"version"`
+	WorkspaceID          int32  `json:"workspace_id"`
 }
 
 func (q *Queries) UpdateSequence(ctx context.Context, arg UpdateSequenceParams) (Sequence, error) {
-	row := q.db.QueryRow(ctx, updateSequence, arg.ID, arg.OpenTrackingEnabled, arg.ClickTrackingEnabled)
+	row := q.db.QueryRow(ctx, updateSequence,
+		arg.ID,
+		arg.SequenceName,
+		arg.OpenTrackingEnabled,
+		arg.ClickTrackingEnabled,
+		arg.Variables,
+		arg.Version,
+		arg.WorkspaceID,
+	)
+	var i Sequence
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.SequenceName,
+		&i.OpenTrackingEnabled,
+		&i.ClickTrackingEnabled,
+		&i.Created,
+		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const updateSequenceStatus = `-- name: UpdateSequenceStatus :one
+UPDATE sequences 
+SET status = $1, version = version + 1 
+WHERE external_id = $2 AND status = $3 AND workspace_id = $4 AND deleted_at IS NULL 
+RETURNING id, external_id, sequence_name, open_tracking_enabled, click_tracking_enabled, created, updated, deleted_at, variables, status, version, source_sequence_id, workspace_id
+`
+
+type UpdateSequenceStatusParams struct {
+	ToStatus    string    `json:"to_status"`
+	ExternalID  uuid.UUID `json:"external_id"`
+	FromStatus  string    `json:"from_status"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) UpdateSequenceStatus(ctx context.Context, arg UpdateSequenceStatusParams) (Sequence, error) {
+	row := q.db.QueryRow(ctx, updateSequenceStatus, arg.ToStatus, arg.ExternalID, arg.FromStatus, arg.WorkspaceID)
 	var i Sequence
 	err := row.Scan(
 		&i.ID,
@@ -181,6 +1144,12 @@ func (q *Queries) UpdateSequence(ctx context.Context, arg UpdateSequenceParams)
 		&i.ClickTrackingEnabled,
 		&i.Created,
 		&i.Updated,
+		&i.DeletedAt,
+		&i.Variables,
+		&i.Status,
+		&i.Version,
+		&i.SourceSequenceID,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
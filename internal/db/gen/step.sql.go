@@ -11,17 +11,61 @@ import (
 	"github.com/google/uuid"
 )
 
+const cloneSteps = `-- name: CloneSteps :execrows
+INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id) 
+SELECT step_number, mail_subject, mail_content, $1::integer, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id FROM steps 
+WHERE sequence_id = $2 AND workspace_id = $3 AND deleted_at IS NULL
+`
+
+type CloneStepsParams struct {
+	TargetID    int32 `json:"target_id"`
+	SourceID    int32 `json:"source_id"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) CloneSteps(ctx context.Context, arg CloneStepsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, cloneSteps, arg.TargetID, arg.SourceID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const closeStepGap = `-- name: CloseStepGap :exec
+UPDATE steps 
+SET step_number = step_number - 1, version = version + 1 
+WHERE sequence_id = $1 AND step_number > $2 AND workspace_id = $3 AND deleted_at IS NULL
+`
+
+type CloseStepGapParams struct {
+	SequenceID  int32 `json:"sequence_id"`
+	StepNumber  int32 `json:"step_number"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) CloseStepGap(ctx context.Context, arg CloseStepGapParams) error {
+	_, err := q.db.Exec(ctx, closeStepGap, arg.SequenceID, arg.StepNumber, arg.WorkspaceID)
+	return err
+}
+
 const createStep = `-- name: CreateStep :one
-INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id) 
-VALUES ($1, $2, $3, $4)
-RETURNING id, external_id, mail_subject, mail_content, step_number, sequence_id
+INSERT INTO steps (step_number, mail_subject, mail_content, sequence_id, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, workspace_id) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, external_id, mail_subject, mail_content, step_number, sequence_id, deleted_at, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, version, workspace_id
 `
 
 type CreateStepParams struct {
-	StepNumber  int32  `json:"step_number"`
-	MailSubject string `json:"mail_subject"`
-	MailContent string `json:"mail_content"`
-	SequenceID  int32  `json:"sequence_id"`
+	StepNumber       int32  `json:"step_number"`
+	MailSubject      string `json:"mail_subject"`
+	MailContent      string `json:"mail_content"`
+	SequenceID       int32  `json:"sequence_id"`
+	DelayDays        int32  `json:"delay_days"`
+	DelayHours       int32  `json:"delay_hours"`
+	BusinessDaysOnly bool   `json:"business_days_only"`
+	SendWindowStart  *int32 `json:"send_window_start"`
+	SendWindowEnd    *int32 `json:"send_window_end"`
+	MailText         string `json:"mail_text"`
+	WorkspaceID      int32  `json:"workspace_id"`
 }
 
 func (q *Queries) CreateStep(ctx context.Context, arg CreateStepParams) (Step, error) {
@@ -30,6 +74,13 @@ func (q *Queries) CreateStep(ctx context.Context, arg CreateStepParams) (Step, e
 		arg.MailSubject,
 		arg.MailContent,
 		arg.SequenceID,
+		arg.DelayDays,
+		arg.DelayHours,
+		arg.BusinessDaysOnly,
+		arg.SendWindowStart,
+		arg.SendWindowEnd,
+		arg.MailText,
+		arg.WorkspaceID,
 	)
 	var i Step
 	err := row.Scan(
@@ -39,41 +90,189 @@ func (q *Queries) CreateStep(ctx context.Context, arg CreateStepParams) (Step, e
 		&i.MailContent,
 		&i.StepNumber,
 		&i.SequenceID,
+		&i.DeletedAt,
+		&i.DelayDays,
+		&i.DelayHours,
+		&i.BusinessDaysOnly,
+		&i.SendWindowStart,
+		&i.SendWindowEnd,
+		&i.MailText,
+		&i.Version,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
 
 type CreateStepsParams struct {
-	ExternalID  uuid.UUID `json:"external_id"`
-	StepNumber  int32     `json:"step_number"`
-	MailSubject string    `json:"mail_subject"`
-	MailContent string    `json:"mail_content"`
-	SequenceID  int32     `json:"sequence_id"`
+	ExternalID       uuid.UUID `json:"external_id"`
+	StepNumber       int32     `json:"step_number"`
+	MailSubject      string    `json:"mail_subject"`
+	MailContent      string    `json:"mail_content"`
+	SequenceID       int32     `json:"sequence_id"`
+	DelayDays        int32     `json:"delay_days"`
+	DelayHours       int32     `json:"delay_hours"`
+	BusinessDaysOnly bool      `json:"business_days_only"`
+	SendWindowStart  *int32    `json:"send_window_start"`
+	SendWindowEnd    *int32    `json:"send_window_end"`
+	MailText         string    `json:"mail_text"`
+	WorkspaceID      int32     `json:"workspace_id"`
 }
 
-const deleteStep = `-- name: DeleteStep :exec
-DELETE FROM steps 
-WHERE external_id = $1
+const deferStepNumbers = `-- name: DeferStepNumbers :exec
+SET CONSTRAINTS steps_sequence_id_step_number_key DEFERRED
 `
 
-func (q *Queries) DeleteStep(ctx context.Context, externalID uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteStep, externalID)
+func (q *Queries) DeferStepNumbers(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deferStepNumbers)
 	return err
 }
 
+const deleteStep = `-- name: DeleteStep :one
+DELETE FROM steps 
+WHERE external_id = $1 AND workspace_id = $2 
+RETURNING id, external_id, mail_subject, mail_content, step_number, sequence_id, deleted_at, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, version, workspace_id
+`
+
+type DeleteStepParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) DeleteStep(ctx context.Context, arg DeleteStepParams) (Step, error) {
+	row := q.db.QueryRow(ctx, deleteStep, arg.ExternalID, arg.WorkspaceID)
+	var i Step
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.MailSubject,
+		&i.MailContent,
+		&i.StepNumber,
+		&i.SequenceID,
+		&i.DeletedAt,
+		&i.DelayDays,
+		&i.DelayHours,
+		&i.BusinessDaysOnly,
+		&i.SendWindowStart,
+		&i.SendWindowEnd,
+		&i.MailText,
+		&i.Version,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
+
+const getSequenceSteps = `-- name: GetSequenceSteps :many
+SELECT id, external_id, mail_subject, mail_content, step_number, sequence_id, deleted_at, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, version, workspace_id FROM steps 
+WHERE sequence_id = $1 AND workspace_id = $2 AND deleted_at IS NULL 
+ORDER BY step_number
+`
+
+type GetSequenceStepsParams struct {
+	SequenceID  int32 `json:"sequence_id"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) GetSequenceSteps(ctx context.Context, arg GetSequenceStepsParams) ([]Step, error) {
+	rows, err := q.db.Query(ctx, getSequenceSteps, arg.SequenceID, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Step
+	for rows.Next() {
+		var i Step
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.MailSubject,
+			&i.MailContent,
+			&i.StepNumber,
+			&i.SequenceID,
+			&i.DeletedAt,
+			&i.DelayDays,
+			&i.DelayHours,
+			&i.BusinessDaysOnly,
+			&i.SendWindowStart,
+			&i.SendWindowEnd,
+			&i.MailText,
+			&i.Version,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSequenceStepsPage = `-- name: GetSequenceStepsPage :many
+SELECT steps.id, steps.external_id, steps.mail_subject, steps.mail_content, steps.step_number, steps.sequence_id, steps.deleted_at, steps.delay_days, steps.delay_hours, steps.business_days_only, steps.send_window_start, steps.send_window_end, steps.mail_text, steps.version, steps.workspace_id FROM steps
+JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $1 AND sequences.deleted_at IS NULL
+WHERE steps.workspace_id = $4 AND steps.deleted_at IS NULL AND steps.step_number > $2
+ORDER BY steps.step_number
+LIMIT $3
+`
+
+type GetSequenceStepsPageParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	StepNumber  int32     `json:"step_number"`
+	Limit       int32     `json:"limit"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) GetSequenceStepsPage(ctx context.Context, arg GetSequenceStepsPageParams) ([]Step, error) {
+	rows, err := q.db.Query(ctx, getSequenceStepsPage, arg.ExternalID, arg.StepNumber, arg.Limit, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Step
+	for rows.Next() {
+		var i Step
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.MailSubject,
+			&i.MailContent,
+			&i.StepNumber,
+			&i.SequenceID,
+			&i.DeletedAt,
+			&i.DelayDays,
+			&i.DelayHours,
+			&i.BusinessDaysOnly,
+			&i.SendWindowStart,
+			&i.SendWindowEnd,
+			&i.MailText,
+			&i.Version,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStepById = `-- name: GetStepById :one
-SELECT steps.id, steps.external_id, steps.mail_subject, steps.mail_content, steps.step_number, steps.sequence_id FROM steps
-JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $2
-WHERE steps.external_id = $1
+SELECT steps.id, steps.external_id, steps.mail_subject, steps.mail_content, steps.step_number, steps.sequence_id, steps.deleted_at, steps.delay_days, steps.delay_hours, steps.business_days_only, steps.send_window_start, steps.send_window_end, steps.mail_text, steps.version, steps.workspace_id FROM steps
+JOIN sequences ON steps.sequence_id = sequences.id AND sequences.external_id = $2 AND sequences.deleted_at IS NULL
+WHERE steps.external_id = $1 AND steps.workspace_id = $3 AND steps.deleted_at IS NULL
 `
 
 type GetStepByIdParams struct {
 	ExternalID   uuid.UUID `json:"external_id"`
 	ExternalID_2 uuid.UUID `json:"external_id_2"`
+	WorkspaceID  int32     `json:"workspace_id"`
 }
 
 func (q *Queries) GetStepById(ctx context.Context, arg GetStepByIdParams) (Step, error) {
-	row := q.db.QueryRow(ctx, getStepById, arg.ExternalID, arg.ExternalID_2)
+	row := q.db.QueryRow(ctx, getStepById, arg.ExternalID, arg.ExternalID_2, arg.WorkspaceID)
 	var i Step
 	err := row.Scan(
 		&i.ID,
@@ -82,22 +281,96 @@ func (q *Queries) GetStepById(ctx context.Context, arg GetStepByIdParams) (Step,
 		&i.MailContent,
 		&i.StepNumber,
 		&i.SequenceID,
+		&i.DeletedAt,
+		&i.DelayDays,
+		&i.DelayHours,
+		&i.BusinessDaysOnly,
+		&i.SendWindowStart,
+		&i.SendWindowEnd,
+		&i.MailText,
+		&i.Version,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
 
+const lockStepSequence = `-- name: LockStepSequence :one
+SELECT sequences.id FROM sequences
+JOIN steps ON steps.sequence_id = sequences.id
+WHERE steps.external_id = $1 AND steps.workspace_id = $2 AND steps.deleted_at IS NULL
+FOR UPDATE OF sequences
+`
+
+type LockStepSequenceParams struct {
+	ExternalID  uuid.UUID `json:"external_id"`
+	WorkspaceID int32     `json:"workspace_id"`
+}
+
+func (q *Queries) LockStepSequence(ctx context.Context, arg LockStepSequenceParams) (int32, error) {
+	row := q.db.QueryRow(ctx, lockStepSequence, arg.ExternalID, arg.WorkspaceID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const reorderSteps = `-- name: ReorderSteps :execrows
+UPDATE steps 
+SET step_number = ordered.position, version = version + 1 
+FROM unnest($1::uuid[]) WITH ORDINALITY AS ordered(external_id, position) 
+WHERE steps.external_id = ordered.external_id AND steps.sequence_id = $2 AND steps.workspace_id = $3 AND steps.deleted_at IS NULL
+`
+
+type ReorderStepsParams struct {
+	StepIds     []uuid.UUID `json:"step_ids"`
+	SequenceID  int32       `json:"sequence_id"`
+	WorkspaceID int32       `json:"workspace_id"`
+}
+
+func (q *Queries) ReorderSteps(ctx context.Context, arg ReorderStepsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reorderSteps, arg.StepIds, arg.SequenceID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const shiftSteps = `-- name: ShiftSteps :exec
+UPDATE steps 
+SET step_number = step_number + 1, version = version + 1 
+WHERE sequence_id = $1 AND step_number >= $2 AND workspace_id = $3 AND deleted_at IS NULL
+`
+
+type ShiftStepsParams struct {
+	SequenceID  int32 `json:"sequence_id"`
+	StepNumber  int32 `json:"step_number"`
+	WorkspaceID int32 `json:"workspace_id"`
+}
+
+func (q *Queries) ShiftSteps(ctx context.Context, arg ShiftStepsParams) error {
+	_, err := q.db.Exec(ctx, shiftSteps, arg.SequenceID, arg.StepNumber, arg.WorkspaceID)
+	return err
+}
+
 const updateStep = `-- name: UpdateStep :one
 UPDATE steps 
-SET mail_subject = $2, mail_content = $3 , step_number = $4
-WHERE external_id = $1 
-RETURNING id, external_id, mail_subject, mail_content, step_number, sequence_id
+SET mail_subject = $2, mail_content = $3 , step_number = $4, delay_days = $5, delay_hours = $6, business_days_only = $7, send_window_start = $8, send_window_end = $9, mail_text = $10, version = version + 1
+WHERE external_id = $1 AND version = $11 AND workspace_id = $12 
+RETURNING id, external_id, mail_subject, mail_content, step_number, sequence_id, deleted_at, delay_days, delay_hours, business_days_only, send_window_start, send_window_end, mail_text, version, workspace_id
 `
 
 type UpdateStepParams struct {
-	ExternalID  uuid.UUID `json:"external_id"`
-	MailSubject string    `json:"mail_subject"`
-	MailContent string    `json:"mail_content"`
-	StepNumber  int32     `json:"step_number"`
+	ExternalID       uuid.UUID `json:"external_id"`
+	MailSubject      string    `json:"mail_subject"`
+	MailContent      string    `json:"mail_content"`
+	StepNumber       int32     `json:"step_number"`
+	DelayDays        int32     `json:"delay_days"`
+	DelayHours       int32     `json:"delay_hours"`
+	BusinessDaysOnly bool      `json:"business_days_only"`
+	SendWindowStart  *int32    `json:"send_window_start"`
+	SendWindowEnd    *int32    `json:"send_window_end"`
+	MailText         string    `json:"mail_text"`
+	Version          int32     `json:"version"`
+	WorkspaceID      int32     `json:"workspace_id"`
 }
 
 func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, error) {
@@ -106,6 +379,14 @@ func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, e
 		arg.MailSubject,
 		arg.MailContent,
 		arg.StepNumber,
+		arg.DelayDays,
+		arg.DelayHours,
+		arg.BusinessDaysOnly,
+		arg.SendWindowStart,
+		arg.SendWindowEnd,
+		arg.MailText,
+		arg.Version,
+		arg.WorkspaceID,
 	)
 	var i Step
 	err := row.Scan(
@@ -115,6 +396,15 @@ func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, e
 		&i.MailContent,
 		&i.StepNumber,
 		&i.SequenceID,
+		&i.DeletedAt,
+		&i.DelayDays,
+		&i.DelayHours,
+		&i.BusinessDaysOnly,
+		&i.SendWindowStart,
+		&i.SendWindowEnd,
+		&i.MailText,
+		&i.Version,
+		&i.WorkspaceID,
 	)
 	return i, err
 }
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+)
+
+type CreateApiKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+func (req *CreateApiKeyRequest) Validate() error {
+	var v validation
+
+	if req.Name == "" {
+		v.fail("/name", "name is required")
+	}
+
+	if len(req.Scopes) == 0 {
+		v.fail("/scopes", "at least one scope is required")
+	}
+
+	for i, scope := range req.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			v.fail(fmt.Sprintf("/scopes/%d", i), "unknown scope %q", scope)
+		}
+	}
+
+	return v.err()
+}
+
+// ApiKeyResponse describes an API key. Key holds the whole key only in the
+// responses of its creation and rotation, it cannot be read again.
+type ApiKeyResponse struct {
+	ExternalID string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	Key        string   `json:"key,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt *string  `json:"lastUsedAt,omitempty"`
+	RevokedAt  *string  `json:"revokedAt,omitempty"`
+}
@@ -0,0 +1,42 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateApiKeyRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{"sequences:read", "steps:write"}}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when name is empty", func(t *testing.T) {
+		req := dto.CreateApiKeyRequest{Scopes: []string{"sequences:read"}}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "name is required")
+	})
+
+	t.Run("should return error when scopes are empty", func(t *testing.T) {
+		req := dto.CreateApiKeyRequest{Name: "ci"}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "at least one scope is required")
+	})
+
+	t.Run("should return error when a scope is unknown", func(t *testing.T) {
+		req := dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{"sequences:read", "sequences:delete"}}
+
+		err := req.Validate()
+		assert.EqualError(t, err, `unknown scope "sequences:delete"`)
+
+		var errs dto.ValidationErrors
+		assert.ErrorAs(t, err, &errs)
+		assert.Equal(t, "/scopes/1", errs[0].Pointer)
+	})
+}
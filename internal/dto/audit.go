@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditTargetTypes lists the kinds of resources the audit log records changes of.
+var AuditTargetTypes = []string{"sequence", "step", "variant"}
+
+// AuditEventPageRequest filters the audit events, nil fields match every event.
+type AuditEventPageRequest struct {
+	Cursor        string
+	Limit         int
+	Actor         *string
+	Action        *string
+	TargetType    *string
+	TargetID      *uuid.UUID
+	RequestID     *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// Validate checks the target type and the date range.
+func (req *AuditEventPageRequest) Validate() error {
+	if req.TargetType != nil && !slices.Contains(AuditTargetTypes, *req.TargetType) {
+		return fmt.Errorf("target type %s is not supported", *req.TargetType)
+	}
+
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return fmt.Errorf("createdAfter must be before createdBefore")
+	}
+
+	return nil
+}
+
+type AuditEventPageResponse struct {
+	Items      []*AuditEventResponse `json:"items"`
+	NextCursor *string               `json:"nextCursor"`
+}
+
+// AuditEventResponse is a change made to a sequence or a step. Before and
+// After hold the target as the API returned it around the change, null when it
+// did not exist.
+type AuditEventResponse struct {
+	ExternalID string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  *string         `json:"requestId"`
+	CreatedAt  string          `json:"createdAt"`
+}
@@ -0,0 +1,37 @@
+package dto_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEventPageRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		targetType := "step"
+		after, before := time.Now().Add(-time.Hour), time.Now()
+
+		req := dto.AuditEventPageRequest{TargetType: &targetType, CreatedAfter: &after, CreatedBefore: &before}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when target type is unknown", func(t *testing.T) {
+		targetType := "enrollment"
+
+		req := dto.AuditEventPageRequest{TargetType: &targetType}
+
+		assert.EqualError(t, req.Validate(), "target type enrollment is not supported")
+	})
+
+	t.Run("should return error when createdAfter is not before createdBefore", func(t *testing.T) {
+		now := time.Now()
+
+		req := dto.AuditEventPageRequest{CreatedAfter: &now, CreatedBefore: &now}
+
+		assert.EqualError(t, req.Validate(), "createdAfter must be before createdBefore")
+	})
+}
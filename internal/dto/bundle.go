@@ -0,0 +1,192 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BundleVersion is the version of the bundles written by the exports, imports
+// refuse bundles of any other version.
+const BundleVersion = 1
+
+// the policies to import a sequence named after an existing one
+const (
+	ConflictSkip      = "skip"
+	ConflictRename    = "rename"
+	ConflictOverwrite = "overwrite"
+)
+
+// the actions an import takes for each sequence of the bundle
+const (
+	ImportCreated     = "created"
+	ImportRenamed     = "renamed"
+	ImportOverwritten = "overwritten"
+	ImportSkipped     = "skipped"
+)
+
+// SequenceBundle is the portable form of sequences, used to move them between
+// environments. It leaves out the ids, statuses and versions, which only make
+// sense in the environment the sequences were exported from.
+type SequenceBundle struct {
+	Version    int               `json:"version" yaml:"version"`
+	ExportedAt string            `json:"exportedAt,omitempty" yaml:"exportedAt,omitempty"`
+	Sequences  []*BundleSequence `json:"sequences" yaml:"sequences"`
+}
+
+type BundleSequence struct {
+	Name                 string            `json:"name" yaml:"name"`
+	OpenTrackingEnabled  bool              `json:"openTrackingEnabled" yaml:"openTrackingEnabled"`
+	ClickTrackingEnabled bool              `json:"clickTrackingEnabled" yaml:"clickTrackingEnabled"`
+	Variables            map[string]string `json:"variables" yaml:"variables"`
+	Steps                []*BundleStep     `json:"steps" yaml:"steps"`
+}
+
+// BundleStep holds the plain text body only when the step has one of its own,
+// otherwise it is derived from the HTML body again once imported.
+type BundleStep struct {
+	StepNumber       int         `json:"stepNumber" yaml:"stepNumber"`
+	MailSubject      string      `json:"mailSubject" yaml:"mailSubject"`
+	MailHTML         string      `json:"mailHtml" yaml:"mailHtml"`
+	MailText         string      `json:"mailText,omitempty" yaml:"mailText,omitempty"`
+	DelayDays        int         `json:"delayDays" yaml:"delayDays"`
+	DelayHours       int         `json:"delayHours" yaml:"delayHours"`
+	BusinessDaysOnly bool        `json:"businessDaysOnly" yaml:"businessDaysOnly"`
+	SendWindow       *SendWindow `json:"sendWindow,omitempty" yaml:"sendWindow,omitempty"`
+}
+
+func (b *SequenceBundle) Validate() error {
+	var v validation
+
+	if b.Version != BundleVersion {
+		v.fail("/version", "bundle version %d is not supported, expected %d", b.Version, BundleVersion)
+	}
+	if len(b.Sequences) == 0 {
+		v.fail("/sequences", "bundle sequences are required")
+	}
+
+	// sequences are matched by name on import, so names are unique in a bundle
+	names := make(map[string]bool)
+	for i, sequence := range b.Sequences {
+		if sequence == nil {
+			v.fail(pointer("sequences", i), "sequence cannot be null")
+			continue
+		}
+
+		v.check(pointer("sequences", i), sequence.Validate())
+
+		if names[sequence.Name] {
+			v.fail(pointer("sequences", i, "name"), "sequence name %q is not unique", sequence.Name)
+		}
+		names[sequence.Name] = true
+	}
+
+	return v.err()
+}
+
+func (s *BundleSequence) Validate() error {
+	var v validation
+
+	if s.Name == "" {
+		v.fail("/name", "sequence name is required")
+	}
+	if len(s.Steps) == 0 {
+		v.fail("/steps", "sequence steps are required")
+	}
+
+	validateVariables(&v, s.Variables)
+
+	// checks if the step numbers are unique
+	stepNumbers := make(map[int]bool)
+	for i, step := range s.Steps {
+		if step == nil {
+			v.fail(pointer("steps", i), "step cannot be null")
+			continue
+		}
+
+		v.check(pointer("steps", i), step.Validate())
+
+		if _, ok := stepNumbers[step.StepNumber]; ok {
+			v.fail(pointer("steps", i, "stepNumber"), "step number %d is not unique", step.StepNumber)
+		}
+		stepNumbers[step.StepNumber] = true
+	}
+
+	return v.err()
+}
+
+func (s *BundleStep) Validate() error {
+	var v validation
+
+	if s.StepNumber <= 0 {
+		v.fail("/stepNumber", "step number is required")
+	}
+
+	if s.MailSubject == "" {
+		v.fail("/mailSubject", "mail subject is required")
+	}
+	v.check("/mailSubject", validateTemplate("mail subject", s.MailSubject))
+
+	if s.MailHTML == "" {
+		v.fail("/mailHtml", "mail html is required")
+	}
+	v.check("/mailHtml", validateTemplate("mail content", s.MailHTML))
+
+	v.check("/mailText", validateTemplate("mail text", s.MailText))
+	v.check("/delayDays", validateDelayDays(s.DelayDays))
+	v.check("/delayHours", validateDelayHours(s.DelayHours))
+
+	if s.SendWindow != nil {
+		v.check("/sendWindow", s.SendWindow.Validate())
+	}
+
+	return v.err()
+}
+
+// Request returns the step as a step creation, which the services store.
+func (s *BundleStep) Request() *CreateStepRequest {
+	return &CreateStepRequest{
+		StepNumber:       s.StepNumber,
+		MailSubject:      s.MailSubject,
+		MailHTML:         s.MailHTML,
+		MailText:         s.MailText,
+		DelayDays:        s.DelayDays,
+		DelayHours:       s.DelayHours,
+		BusinessDaysOnly: s.BusinessDaysOnly,
+		SendWindow:       s.SendWindow,
+	}
+}
+
+// ImportOptions tell how to import a bundle: OnConflict is the policy for the
+// sequences named after an existing one and DryRun only reports what the
+// import would do.
+type ImportOptions struct {
+	OnConflict string
+	DryRun     bool
+}
+
+func (o *ImportOptions) Validate() error {
+	policies := []string{ConflictSkip, ConflictRename, ConflictOverwrite}
+
+	if !slices.Contains(policies, o.OnConflict) {
+		return fmt.Errorf("onConflict %s is not supported, use skip, rename or overwrite", o.OnConflict)
+	}
+
+	return nil
+}
+
+type ImportResponse struct {
+	DryRun    bool                `json:"dryRun"`
+	Sequences []*ImportedSequence `json:"sequences"`
+}
+
+// ImportedSequence is what the import did, or would do on a dry run, with a
+// sequence of the bundle. ID is the sequence created, overwritten or skipped,
+// which is unknown for the sequences a dry run would create. Changes lists
+// what an overwrite changes in the existing sequence.
+type ImportedSequence struct {
+	Name       string        `json:"name"`
+	Action     string        `json:"action"`
+	ID         *string       `json:"id"`
+	ImportedAs string        `json:"importedAs,omitempty"`
+	Changes    *SequenceDiff `json:"changes,omitempty"`
+}
@@ -0,0 +1,70 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceBundle_Validate(t *testing.T) {
+	t.Parallel()
+
+	sequence := func(name string) *dto.BundleSequence {
+		return &dto.BundleSequence{
+			Name:  name,
+			Steps: []*dto.BundleStep{{StepNumber: 1, MailSubject: "subject", MailHTML: "<p>content</p>"}},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		bundle := dto.SequenceBundle{Version: dto.BundleVersion, Sequences: []*dto.BundleSequence{sequence("first"), sequence("second")}}
+		assert.NoError(t, bundle.Validate())
+	})
+
+	t.Run("should return error when version is not supported", func(t *testing.T) {
+		bundle := dto.SequenceBundle{Version: 2, Sequences: []*dto.BundleSequence{sequence("first")}}
+
+		err := bundle.Validate()
+		assert.EqualError(t, err, "bundle version 2 is not supported, expected 1")
+	})
+
+	t.Run("should return error when sequence names are not unique", func(t *testing.T) {
+		bundle := dto.SequenceBundle{Version: dto.BundleVersion, Sequences: []*dto.BundleSequence{sequence("first"), sequence("first")}}
+
+		err := bundle.Validate()
+		assert.Equal(t, dto.ValidationErrors{
+			{Pointer: "/sequences/1/name", Detail: `sequence name "first" is not unique`},
+		}, err)
+	})
+
+	t.Run("should return every invalid field of the sequences with its pointer", func(t *testing.T) {
+		invalid := sequence("")
+		invalid.Steps = append(invalid.Steps, &dto.BundleStep{StepNumber: 1, MailSubject: "subject"})
+
+		bundle := dto.SequenceBundle{Version: dto.BundleVersion, Sequences: []*dto.BundleSequence{sequence("first"), invalid}}
+
+		err := bundle.Validate()
+		assert.Equal(t, dto.ValidationErrors{
+			{Pointer: "/sequences/1/name", Detail: "sequence name is required"},
+			{Pointer: "/sequences/1/steps/1/mailHtml", Detail: "mail html is required"},
+			{Pointer: "/sequences/1/steps/1/stepNumber", Detail: "step number 1 is not unique"},
+		}, err)
+	})
+}
+
+func TestImportOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		opts := dto.ImportOptions{OnConflict: dto.ConflictOverwrite, DryRun: true}
+		assert.NoError(t, opts.Validate())
+	})
+
+	t.Run("should return error when conflict policy is not supported", func(t *testing.T) {
+		opts := dto.ImportOptions{OnConflict: "merge"}
+
+		err := opts.Validate()
+		assert.EqualError(t, err, "onConflict merge is not supported, use skip, rename or overwrite")
+	})
+}
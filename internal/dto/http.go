@@ -1,5 +1,12 @@
 package dto
 
-type HTTPError struct {
-	Message string `json:"message"`
+// Problem is the RFC 7807 body of the error responses, served as
+// application/problem+json. Errors lists the invalid fields of the request.
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Instance string        `json:"instance,omitempty"`
+	Errors   []*FieldError `json:"errors,omitempty"`
 }
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+)
+
+// PreviewStepRequest holds the attributes of the contact a step is rendered
+// for. Strict fails the preview when a variable is not resolved instead of
+// warning about it.
+type PreviewStepRequest struct {
+	Contact map[string]string `json:"contact,omitempty"`
+	Strict  bool              `json:"strict"`
+}
+
+func (req *PreviewStepRequest) Validate() error {
+	var v validation
+
+	for _, name := range slices.Sorted(maps.Keys(req.Contact)) {
+		if !templating.IsValidName(name) {
+			v.fail(pointer("contact", name), "contact attribute name %q must contain only letters, digits and underscores", name)
+		}
+	}
+
+	return v.err()
+}
+
+type StepPreviewResponse struct {
+	Subject         string                 `json:"subject"`
+	HTML            string                 `json:"html"`
+	Text            string                 `json:"text"`
+	Warnings        []string               `json:"warnings"`
+	TrackedLinks    []*TrackedLinkResponse `json:"trackedLinks"`
+	OpenTrackingURL *string                `json:"openTrackingUrl,omitempty"`
+}
+
+type TrackedLinkResponse struct {
+	URL        string `json:"url"`
+	TrackedURL string `json:"trackedUrl"`
+}
@@ -0,0 +1,24 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewStepRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.PreviewStepRequest{Contact: map[string]string{"firstName": "Ana"}}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when contact attribute name is invalid", func(t *testing.T) {
+		req := dto.PreviewStepRequest{Contact: map[string]string{"first-name": "Ana"}}
+
+		err := req.Validate()
+		assert.EqualError(t, err, `contact attribute name "first-name" must contain only letters, digits and underscores`)
+	})
+}
@@ -0,0 +1,39 @@
+package dto
+
+type RevisionResponse struct {
+	Revision             int               `json:"revision"`
+	Name                 string            `json:"name"`
+	OpenTrackingEnabled  bool              `json:"openTrackingEnabled"`
+	ClickTrackingEnabled bool              `json:"clickTrackingEnabled"`
+	Variables            map[string]string `json:"variables"`
+	Deleted              bool              `json:"deleted"`
+	Steps                []*StepResponse   `json:"steps"`
+	CreatedAt            string            `json:"createdAt"`
+}
+
+// RevisionDiffResponse lists what changed to go from one revision to another.
+type RevisionDiffResponse struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	SequenceDiff
+}
+
+// SequenceDiff lists what changed to go from one content of a sequence to
+// another, steps are matched by id.
+type SequenceDiff struct {
+	Changes      []*FieldChange  `json:"changes"`
+	AddedSteps   []*StepResponse `json:"addedSteps"`
+	RemovedSteps []*StepResponse `json:"removedSteps"`
+	ChangedSteps []*StepDiff     `json:"changedSteps"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type StepDiff struct {
+	ExternalID string         `json:"id"`
+	Changes    []*FieldChange `json:"changes"`
+}
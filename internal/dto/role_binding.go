@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+)
+
+// CreateRoleBindingRequest grants a role in the whole workspace, or in the
+// sequence of SequenceID only.
+type CreateRoleBindingRequest struct {
+	UserID     string     `json:"userId"`
+	Role       string     `json:"role"`
+	SequenceID *uuid.UUID `json:"sequenceId,omitempty"`
+}
+
+func (req *CreateRoleBindingRequest) Validate() error {
+	var v validation
+
+	if req.UserID == "" {
+		v.fail("/userId", "userId is required")
+	}
+
+	if len(req.UserID) > 255 {
+		v.fail("/userId", "userId must be at most 255 characters")
+	}
+
+	if !models.Role(req.Role).Valid() {
+		v.fail("/role", "unknown role %q", req.Role)
+	}
+
+	return v.err()
+}
+
+type RoleBindingResponse struct {
+	ExternalID string  `json:"id"`
+	UserID     string  `json:"userId"`
+	Role       string  `json:"role"`
+	SequenceID *string `json:"sequenceId,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
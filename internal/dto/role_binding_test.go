@@ -0,0 +1,35 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRoleBindingRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CreateRoleBindingRequest{UserID: "user-1", Role: "editor"}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when userId is empty", func(t *testing.T) {
+		req := dto.CreateRoleBindingRequest{Role: "viewer"}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "userId is required")
+	})
+
+	t.Run("should return error when role is unknown", func(t *testing.T) {
+		req := dto.CreateRoleBindingRequest{UserID: "user-1", Role: "owner"}
+
+		err := req.Validate()
+		assert.EqualError(t, err, `unknown role "owner"`)
+
+		var errs dto.ValidationErrors
+		assert.ErrorAs(t, err, &errs)
+		assert.Equal(t, "/role", errs[0].Pointer)
+	})
+}
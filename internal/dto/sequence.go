@@ -1,58 +1,244 @@
 package dto
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+)
 
 type CreateSequenceRequest struct {
-	Name                 string               `json:"Name"`
+	Name                 string               `json:"name"`
 	OpenTrackingEnabled  bool                 `json:"openTrackingEnabled"`
 	ClickTrackingEnabled bool                 `json:"clickTrackingEnabled"`
+	Variables            map[string]string    `json:"variables,omitempty"`
 	Steps                []*CreateStepRequest `json:"steps"`
 }
 
 func (req *CreateSequenceRequest) Validate() error {
+	var v validation
+
 	if req.Name == "" {
-		return fmt.Errorf("sequence name is required")
+		v.fail("/name", "sequence name is required")
 	}
 	if len(req.Steps) == 0 {
-		return fmt.Errorf("sequence steps are required")
+		v.fail("/steps", "sequence steps are required")
 	}
 
-	for _, step := range req.Steps {
-		if err := step.Validate(); err != nil {
-			return err
+	validateVariables(&v, req.Variables)
+
+	// checks if the step numbers are unique
+	stepNumbers := make(map[int]bool)
+	for i, step := range req.Steps {
+		if step == nil {
+			v.fail(pointer("steps", i), "step cannot be null")
+			continue
+		}
+
+		v.check(pointer("steps", i), step.Validate())
+
+		if _, ok := stepNumbers[step.StepNumber]; ok {
+			v.fail(pointer("steps", i, "stepNumber"), "step number %d is not unique", step.StepNumber)
 		}
+		stepNumbers[step.StepNumber] = true
 	}
 
-	// checks if the step numbers are unique
+	return v.err()
+}
+
+type ReplaceSequenceRequest struct {
+	Name                 string                `json:"name"`
+	OpenTrackingEnabled  bool                  `json:"openTrackingEnabled"`
+	ClickTrackingEnabled bool                  `json:"clickTrackingEnabled"`
+	Variables            map[string]string     `json:"variables,omitempty"`
+	Steps                []*ReplaceStepRequest `json:"steps"`
+}
+
+func (req *ReplaceSequenceRequest) Validate() error {
+	var v validation
+
+	if req.Name == "" {
+		v.fail("/name", "sequence name is required")
+	}
+	if len(req.Steps) == 0 {
+		v.fail("/steps", "sequence steps are required")
+	}
+
+	validateVariables(&v, req.Variables)
+
+	// checks if the step numbers and ids are unique
 	stepNumbers := make(map[int]bool)
-	for _, step := range req.Steps {
+	stepIDs := make(map[uuid.UUID]bool)
+	for i, step := range req.Steps {
+		if step == nil {
+			v.fail(pointer("steps", i), "step cannot be null")
+			continue
+		}
+
+		v.check(pointer("steps", i), step.Validate())
+
 		if _, ok := stepNumbers[step.StepNumber]; ok {
-			return fmt.Errorf("step number %d is not unique", step.StepNumber)
+			v.fail(pointer("steps", i, "stepNumber"), "step number %d is not unique", step.StepNumber)
 		}
 		stepNumbers[step.StepNumber] = true
+
+		if step.ExternalID == nil {
+			continue
+		}
+
+		if _, ok := stepIDs[*step.ExternalID]; ok {
+			v.fail(pointer("steps", i, "id"), "step id %s is not unique", *step.ExternalID)
+		}
+		stepIDs[*step.ExternalID] = true
 	}
 
-	return nil
+	return v.err()
 }
 
+// UpdateSequenceRequest partially updates a sequence, variables replace all
+// the variables of the sequence when present.
 type UpdateSequenceRequest struct {
-	OpenTrackingEnabled  *bool `json:"openTrackingEnabled"`
-	ClickTrackingEnabled *bool `json:"clickTrackingEnabled"`
+	Name                 *string           `json:"name,omitempty"`
+	OpenTrackingEnabled  *bool             `json:"openTrackingEnabled,omitempty"`
+	ClickTrackingEnabled *bool             `json:"clickTrackingEnabled,omitempty"`
+	Variables            map[string]string `json:"variables,omitempty"`
+}
+
+func (req *UpdateSequenceRequest) Validate() error {
+	var v validation
+
+	if req.Name != nil && *req.Name == "" {
+		v.fail("/name", "sequence name cannot be empty")
+	}
+
+	validateVariables(&v, req.Variables)
+
+	return v.err()
+}
+
+// CloneSequenceRequest overrides the name and the tracking settings of the
+// copy, which keeps the ones of the cloned sequence otherwise.
+type CloneSequenceRequest struct {
+	Name                 *string `json:"name,omitempty"`
+	OpenTrackingEnabled  *bool   `json:"openTrackingEnabled,omitempty"`
+	ClickTrackingEnabled *bool   `json:"clickTrackingEnabled,omitempty"`
+}
+
+func (req *CloneSequenceRequest) Validate() error {
+	var v validation
+
+	if req.Name != nil && *req.Name == "" {
+		v.fail("/name", "sequence name cannot be empty")
+	}
+
+	return v.err()
+}
+
+// validateVariables checks the names of the sequence variables, which are
+// referenced by the step templates as {{sequence.name}}.
+func validateVariables(v *validation, variables map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if !templating.IsValidName(name) {
+			v.fail(pointer("variables", name), "sequence variable name %q must contain only letters, digits and underscores", name)
+		}
+	}
+}
+
+type SequencePageRequest struct {
+	Cursor               string
+	Limit                int
+	IncludeTotalCount    bool
+	Name                 *string
+	OpenTrackingEnabled  *bool
+	ClickTrackingEnabled *bool
+	CreatedAfter         *time.Time
+	CreatedBefore        *time.Time
+	UpdatedAfter         *time.Time
+	UpdatedBefore        *time.Time
+	Search               *string
+	Sort                 string
+}
+
+var sequenceSortFields = []string{"name", "created", "updated", "stepCount"}
+
+// Validate checks the date ranges and the sort expression, which is one of the
+// sortable fields optionally followed by ":asc" or ":desc".
+func (req *SequencePageRequest) Validate() error {
+	if req.Sort != "" {
+		field, direction, _ := strings.Cut(req.Sort, ":")
+
+		if !slices.Contains(sequenceSortFields, field) {
+			return fmt.Errorf("sort field %s is not supported", field)
+		}
+
+		if direction != "" && direction != "asc" && direction != "desc" {
+			return fmt.Errorf("sort direction %s is not supported", direction)
+		}
+	}
+
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return fmt.Errorf("createdAfter must be before createdBefore")
+	}
+
+	if req.UpdatedAfter != nil && req.UpdatedBefore != nil && !req.UpdatedAfter.Before(*req.UpdatedBefore) {
+		return fmt.Errorf("updatedAfter must be before updatedBefore")
+	}
+
+	return nil
+}
+
+// SortBy returns the sort field and whether it is descending, sequences are
+// sorted by creation date in ascending order by default.
+func (req *SequencePageRequest) SortBy() (string, bool) {
+	if req.Sort == "" {
+		return "created", false
+	}
+
+	field, direction, _ := strings.Cut(req.Sort, ":")
+
+	return field, direction == "desc"
+}
+
+type SequencePageResponse struct {
+	Items      []*SequenceResponse `json:"items"`
+	NextCursor *string             `json:"nextCursor"`
+	PrevCursor *string             `json:"prevCursor"`
+	TotalCount *int64              `json:"totalCount,omitempty"`
 }
 
 type SequenceResponse struct {
-	ExternalID           string          `json:"id"`
-	Name                 string          `json:"name"`
-	OpenTrackingEnabled  bool            `json:"openTrackingEnabled"`
-	ClickTrackingEnabled bool            `json:"clickTrackingEnabled"`
-	Steps                []*StepResponse `json:"steps"`
-	CreatedAt            string          `json:"createdAt"`
-	LastUpdatedAt        *string         `json:"lastUpdatedAt"`
+	ExternalID           string            `json:"id"`
+	Name                 string            `json:"name"`
+	OpenTrackingEnabled  bool              `json:"openTrackingEnabled"`
+	ClickTrackingEnabled bool              `json:"clickTrackingEnabled"`
+	Variables            map[string]string `json:"variables"`
+	Status               string            `json:"status"`
+	Version              int32             `json:"version"`
+	Steps                []*StepResponse   `json:"steps"`
+	CreatedAt            string            `json:"createdAt"`
+	LastUpdatedAt        *string           `json:"lastUpdatedAt"`
+	DeletedAt            *string           `json:"deletedAt,omitempty"`
+	SourceSequenceID     *string           `json:"sourceSequenceId,omitempty"`
 }
 
+// StepResponse repeats the HTML body in mailContent for the clients that
+// predate the plain text body. The version is left out of the steps of
+// revisions, which do not keep it.
 type StepResponse struct {
-	ExternalID  string `json:"id"`
-	StepNumber  int    `json:"stepNumber"`
-	MailSubject string `json:"mailSubject"`
-	MailContent string `json:"mailContent"`
+	ExternalID       string      `json:"id"`
+	StepNumber       int         `json:"stepNumber"`
+	MailSubject      string      `json:"mailSubject"`
+	MailContent      string      `json:"mailContent"`
+	MailHTML         string      `json:"mailHtml"`
+	MailText         string      `json:"mailText"`
+	DelayDays        int         `json:"delayDays"`
+	DelayHours       int         `json:"delayHours"`
+	BusinessDaysOnly bool        `json:"businessDaysOnly"`
+	SendWindow       *SendWindow `json:"sendWindow,omitempty"`
+	Variables        []string    `json:"variables"`
+	Version          int32       `json:"version,omitempty"`
 }
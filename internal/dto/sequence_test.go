@@ -2,6 +2,9 @@ package dto_test
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/stretchr/testify/assert"
@@ -81,4 +84,211 @@ func TestCreateSequenceRequest_Validate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, "step number 1 is not unique", err.Error())
 	})
+
+	t.Run("should return every invalid field with its pointer", func(t *testing.T) {
+		req := dto.CreateSequenceRequest{
+			Variables: map[string]string{"product-name": "Mailbox"},
+			Steps: []*dto.CreateStepRequest{
+				{
+					StepNumber:  1,
+					MailSubject: "subject",
+					MailContent: "content",
+				},
+				{
+					StepNumber:  2,
+					MailContent: "content",
+					SendWindow:  &dto.SendWindow{Start: "18:00", End: "08:00"},
+				},
+				{
+					StepNumber:  2,
+					MailSubject: "subject",
+				},
+			},
+		}
+
+		var errs dto.ValidationErrors
+		assert.ErrorAs(t, req.Validate(), &errs)
+		assert.Equal(t, dto.ValidationErrors{
+			{Pointer: "/name", Detail: "sequence name is required"},
+			{Pointer: "/variables/product-name", Detail: `sequence variable name "product-name" must contain only letters, digits and underscores`},
+			{Pointer: "/steps/1/mailSubject", Detail: "mail subject is required"},
+			{Pointer: "/steps/1/sendWindow/end", Detail: "send window end must be after start"},
+			{Pointer: "/steps/2/mailContent", Detail: "mail content is required"},
+			{Pointer: "/steps/2/stepNumber", Detail: "step number 2 is not unique"},
+		}, errs)
+	})
+}
+
+func TestSequencePageRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	after := time.Date(2025, 8, 31, 16, 0, 0, 0, time.UTC)
+	before := after.Add(time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.SequencePageRequest{
+			Sort:          "stepCount:desc",
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+		}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when sort field is not supported", func(t *testing.T) {
+		req := dto.SequencePageRequest{Sort: "mailSubject"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sort field mailSubject is not supported", err.Error())
+	})
+
+	t.Run("should return error when sort direction is not supported", func(t *testing.T) {
+		req := dto.SequencePageRequest{Sort: "name:up"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sort direction up is not supported", err.Error())
+	})
+
+	t.Run("should return error when created range is inverted", func(t *testing.T) {
+		req := dto.SequencePageRequest{CreatedAfter: &before, CreatedBefore: &after}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "createdAfter must be before createdBefore", err.Error())
+	})
+
+	t.Run("should return error when updated range is inverted", func(t *testing.T) {
+		req := dto.SequencePageRequest{UpdatedAfter: &before, UpdatedBefore: &after}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "updatedAfter must be before updatedBefore", err.Error())
+	})
+}
+
+func TestSequencePageRequest_SortBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sort       string
+		field      string
+		descending bool
+	}{
+		{"", "created", false},
+		{"name", "name", false},
+		{"updated:asc", "updated", false},
+		{"stepCount:desc", "stepCount", true},
+	}
+
+	for _, tt := range tests {
+		req := dto.SequencePageRequest{Sort: tt.sort}
+		field, descending := req.SortBy()
+		assert.Equal(t, tt.field, field)
+		assert.Equal(t, tt.descending, descending)
+	}
+}
+
+func TestReplaceSequenceRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	stepID := uuid.New()
+
+	newStep := func(id *uuid.UUID, number int) *dto.ReplaceStepRequest {
+		return &dto.ReplaceStepRequest{
+			ExternalID:        id,
+			CreateStepRequest: dto.CreateStepRequest{StepNumber: number, MailSubject: "subject", MailContent: "content"},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.ReplaceSequenceRequest{
+			Name:  "name",
+			Steps: []*dto.ReplaceStepRequest{newStep(&stepID, 1), newStep(nil, 2)},
+		}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when name is empty", func(t *testing.T) {
+		req := dto.ReplaceSequenceRequest{Steps: []*dto.ReplaceStepRequest{newStep(nil, 1)}}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sequence name is required", err.Error())
+	})
+
+	t.Run("should return error when steps array is empty", func(t *testing.T) {
+		req := dto.ReplaceSequenceRequest{Name: "name"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sequence steps are required", err.Error())
+	})
+
+	t.Run("should return error when step number is not unique", func(t *testing.T) {
+		req := dto.ReplaceSequenceRequest{
+			Name:  "name",
+			Steps: []*dto.ReplaceStepRequest{newStep(&stepID, 1), newStep(nil, 1)},
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "step number 1 is not unique", err.Error())
+	})
+
+	t.Run("should return error when step id is not unique", func(t *testing.T) {
+		req := dto.ReplaceSequenceRequest{
+			Name:  "name",
+			Steps: []*dto.ReplaceStepRequest{newStep(&stepID, 1), newStep(&stepID, 2)},
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "step id "+stepID.String()+" is not unique", err.Error())
+	})
+}
+
+func TestUpdateSequenceRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		name := "name"
+		req := dto.UpdateSequenceRequest{Name: &name}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when name is empty", func(t *testing.T) {
+		name := ""
+		req := dto.UpdateSequenceRequest{Name: &name}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sequence name cannot be empty", err.Error())
+	})
+
+	t.Run("should return error when variable name is invalid", func(t *testing.T) {
+		req := dto.UpdateSequenceRequest{Variables: map[string]string{"product name": "Mailbox"}}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, `sequence variable name "product name" must contain only letters, digits and underscores`, err.Error())
+	})
+}
+
+func TestCloneSequenceRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CloneSequenceRequest{}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when name is empty", func(t *testing.T) {
+		name := ""
+		req := dto.CloneSequenceRequest{Name: &name}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "sequence name cannot be empty", err.Error())
+	})
 }
@@ -1,29 +1,273 @@
 package dto
 
-import "fmt"
+import (
+	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+)
+
+const sendWindowLayout = "15:04"
+
+// SendWindow is the preferred time of day to send a step, in the contact's
+// timezone. Both bounds use the HH:MM format and end must be after start.
+type SendWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// NewSendWindow builds a send window from its bounds in minutes after
+// midnight, returning nil when the step has no window.
+func NewSendWindow(start, end *int32) *SendWindow {
+	if start == nil || end == nil {
+		return nil
+	}
+
+	return &SendWindow{
+		Start: fmt.Sprintf("%02d:%02d", *start/60, *start%60),
+		End:   fmt.Sprintf("%02d:%02d", *end/60, *end%60),
+	}
+}
+
+// IsZero reports whether both bounds are empty, which on updates removes
+// the send window of the step.
+func (w *SendWindow) IsZero() bool {
+	return w.Start == "" && w.End == ""
+}
+
+// Minutes returns the bounds of the window in minutes after midnight, or the
+// error of Validate when the window is not valid.
+func (w *SendWindow) Minutes() (int32, int32, error) {
+	if err := w.Validate(); err != nil {
+		return 0, 0, err
+	}
+
+	start, _ := parseSendWindowBound(w.Start)
+	end, _ := parseSendWindowBound(w.End)
+
+	return start, end, nil
+}
+
+func (w *SendWindow) Validate() error {
+	var v validation
+
+	start, startErr := parseSendWindowBound(w.Start)
+	if startErr != nil {
+		v.fail("/start", "send window start must use the HH:MM format")
+	}
+
+	end, endErr := parseSendWindowBound(w.End)
+	if endErr != nil {
+		v.fail("/end", "send window end must use the HH:MM format")
+	}
+
+	if startErr == nil && endErr == nil && start >= end {
+		v.fail("/end", "send window end must be after start")
+	}
+
+	return v.err()
+}
+
+// parseSendWindowBound parses a bound of the window into minutes after midnight.
+func parseSendWindowBound(bound string) (int32, error) {
+	parsed, err := time.Parse(sendWindowLayout, bound)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(parsed.Hour()*60 + parsed.Minute()), nil
+}
+
+// UpdateStepRequest accepts the HTML body in either mailHtml or mailContent,
+// its former name. An empty mailText goes back to deriving the plain text body
+// from the HTML one.
 type UpdateStepRequest struct {
-	StepNumber  *int    `json:"stepNumber"`
-	MailSubject *string `json:"mailSubject"`
-	MailContent *string `json:"mailContent"`
+	StepNumber       *int        `json:"stepNumber,omitempty"`
+	MailSubject      *string     `json:"mailSubject,omitempty"`
+	MailContent      *string     `json:"mailContent,omitempty"`
+	MailHTML         *string     `json:"mailHtml,omitempty"`
+	MailText         *string     `json:"mailText,omitempty"`
+	DelayDays        *int        `json:"delayDays,omitempty"`
+	DelayHours       *int        `json:"delayHours,omitempty"`
+	BusinessDaysOnly *bool       `json:"businessDaysOnly,omitempty"`
+	SendWindow       *SendWindow `json:"sendWindow,omitempty"`
+}
+
+func (req *UpdateStepRequest) Validate() error {
+	var v validation
+
+	if req.StepNumber != nil && *req.StepNumber <= 0 {
+		v.fail("/stepNumber", "step number must be greater than zero")
+	}
+
+	if req.MailSubject != nil {
+		if *req.MailSubject == "" {
+			v.fail("/mailSubject", "mail subject cannot be empty")
+		}
+		v.check("/mailSubject", validateTemplate("mail subject", *req.MailSubject))
+	}
+
+	if req.MailContent != nil && req.MailHTML != nil {
+		v.fail("/mailHtml", "mail content and mail html cannot be sent together")
+	}
+
+	htmlPointer := "/mailContent"
+	if req.MailHTML != nil {
+		htmlPointer = "/mailHtml"
+	}
+
+	if html := req.HTML(); html != nil {
+		if *html == "" {
+			v.fail(htmlPointer, "mail content cannot be empty")
+		}
+		v.check(htmlPointer, validateTemplate("mail content", *html))
+	}
+
+	if req.MailText != nil {
+		v.check("/mailText", validateTemplate("mail text", *req.MailText))
+	}
+
+	if req.DelayDays != nil {
+		v.check("/delayDays", validateDelayDays(*req.DelayDays))
+	}
+
+	if req.DelayHours != nil {
+		v.check("/delayHours", validateDelayHours(*req.DelayHours))
+	}
+
+	if req.SendWindow != nil && !req.SendWindow.IsZero() {
+		v.check("/sendWindow", req.SendWindow.Validate())
+	}
+
+	return v.err()
+}
+
+// HTML returns the HTML body sent in either mailHtml or mailContent.
+func (req *UpdateStepRequest) HTML() *string {
+	if req.MailHTML != nil {
+		return req.MailHTML
+	}
+	return req.MailContent
 }
 
+// CreateStepRequest accepts the HTML body in either mailHtml or mailContent,
+// its former name. When mailText is empty the plain text body is derived from
+// the HTML one.
 type CreateStepRequest struct {
-	StepNumber  int    `json:"stepNumber"`
-	MailSubject string `json:"mailSubject"`
-	MailContent string `json:"mailContent"`
+	StepNumber       int         `json:"stepNumber"`
+	MailSubject      string      `json:"mailSubject"`
+	MailContent      string      `json:"mailContent"`
+	MailHTML         string      `json:"mailHtml"`
+	MailText         string      `json:"mailText"`
+	DelayDays        int         `json:"delayDays"`
+	DelayHours       int         `json:"delayHours"`
+	BusinessDaysOnly bool        `json:"businessDaysOnly"`
+	SendWindow       *SendWindow `json:"sendWindow,omitempty"`
+}
+
+// ReplaceStepRequest is a step of a sequence replacement, steps with an id
+// update the existing step and steps without one are created.
+type ReplaceStepRequest struct {
+	ExternalID *uuid.UUID `json:"id,omitempty"`
+	CreateStepRequest
 }
 
 func (req *CreateStepRequest) Validate() error {
+	var v validation
+
 	if req.StepNumber <= 0 {
-		return fmt.Errorf("step number is required")
+		v.fail("/stepNumber", "step number is required")
 	}
 
 	if req.MailSubject == "" {
-		return fmt.Errorf("mail subject is required")
+		v.fail("/mailSubject", "mail subject is required")
+	}
+	v.check("/mailSubject", validateTemplate("mail subject", req.MailSubject))
+
+	if req.MailContent != "" && req.MailHTML != "" {
+		v.fail("/mailHtml", "mail content and mail html cannot be sent together")
+	}
+
+	htmlPointer := "/mailContent"
+	if req.MailHTML != "" {
+		htmlPointer = "/mailHtml"
+	}
+
+	if req.HTML() == "" {
+		v.fail(htmlPointer, "mail content is required")
+	}
+	v.check(htmlPointer, validateTemplate("mail content", req.HTML()))
+
+	v.check("/mailText", validateTemplate("mail text", req.MailText))
+	v.check("/delayDays", validateDelayDays(req.DelayDays))
+	v.check("/delayHours", validateDelayHours(req.DelayHours))
+
+	if req.SendWindow != nil {
+		v.check("/sendWindow", req.SendWindow.Validate())
+	}
+
+	return v.err()
+}
+
+// HTML returns the HTML body sent in either mailHtml or mailContent.
+func (req *CreateStepRequest) HTML() string {
+	if req.MailHTML != "" {
+		return req.MailHTML
 	}
-	if req.MailContent == "" {
-		return fmt.Errorf("mail content is required")
+	return req.MailContent
+}
+
+func validateTemplate(field string, src string) error {
+	if _, err := templating.Parse(src); err != nil {
+		return fmt.Errorf("invalid %s template: %w", field, err)
+	}
+	return nil
+}
+
+func validateDelayDays(days int) error {
+	if days < 0 {
+		return fmt.Errorf("delay days cannot be negative")
 	}
 	return nil
 }
+
+func validateDelayHours(hours int) error {
+	if hours < 0 || hours > 23 {
+		return fmt.Errorf("delay hours must be between 0 and 23")
+	}
+	return nil
+}
+
+type StepPageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+type StepPageResponse struct {
+	Items      []*StepResponse `json:"items"`
+	NextCursor *string         `json:"nextCursor"`
+}
+
+type ReorderStepsRequest struct {
+	StepIDs []uuid.UUID `json:"stepIds"`
+}
+
+func (req *ReorderStepsRequest) Validate() error {
+	var v validation
+
+	if len(req.StepIDs) == 0 {
+		v.fail("/stepIds", "step ids are required")
+	}
+
+	// checks if the step ids are unique
+	stepIDs := make(map[uuid.UUID]bool)
+	for i, id := range req.StepIDs {
+		if _, ok := stepIDs[id]; ok {
+			v.fail(pointer("stepIds", i), "step id %s is not unique", id)
+		}
+		stepIDs[id] = true
+	}
+
+	return v.err()
+}
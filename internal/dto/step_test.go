@@ -3,6 +3,8 @@ package dto_test
 import (
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/stretchr/testify/assert"
 )
@@ -78,3 +80,241 @@ func TestCreateStepRequest_Validate(t *testing.T) {
 		assert.Equal(t, "step number is required", err.Error())
 	})
 }
+
+func TestCreateStepRequest_Validate_Template(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: `Hi {{contact.firstName | default "there"}}`,
+			MailContent: "{{#if sequence.product}}Meet {{sequence.product}}{{/if}}",
+		}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when mail subject template is invalid", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "Hi {{contact.firstName",
+			MailContent: "content",
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "invalid mail subject template: tag at position 3 is not closed", err.Error())
+	})
+
+	t.Run("should return error when mail content template is invalid", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "subject",
+			MailContent: "{{account.name}}",
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, `invalid mail content template: unknown variable namespace "account" at position 0`, err.Error())
+	})
+}
+
+func TestCreateStepRequest_Validate_Bodies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "subject",
+			MailHTML:    "<p>Hi {{contact.firstName}}</p>",
+			MailText:    "Hi {{contact.firstName}}",
+		}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, req.MailHTML, req.HTML())
+	})
+
+	t.Run("should return error when mail content and mail html are sent", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "subject",
+			MailContent: "content",
+			MailHTML:    "<p>content</p>",
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "mail content and mail html cannot be sent together", err.Error())
+	})
+
+	t.Run("should return error when mail text template is invalid", func(t *testing.T) {
+		req := dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "subject",
+			MailHTML:    "<p>content</p>",
+			MailText:    "Hi {{firstName}}",
+		}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, `invalid mail text template: invalid variable "firstName", variables must be namespace.name at position 3`, err.Error())
+	})
+}
+
+func TestCreateStepRequest_Validate_Timing(t *testing.T) {
+	t.Parallel()
+
+	newStep := func() dto.CreateStepRequest {
+		return dto.CreateStepRequest{
+			StepNumber:  1,
+			MailSubject: "subject",
+			MailContent: "content",
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		req := newStep()
+		req.DelayDays = 3
+		req.DelayHours = 23
+		req.BusinessDaysOnly = true
+		req.SendWindow = &dto.SendWindow{Start: "09:00", End: "17:30"}
+
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when delay days is negative", func(t *testing.T) {
+		req := newStep()
+		req.DelayDays = -1
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "delay days cannot be negative", err.Error())
+	})
+
+	t.Run("should return error when delay hours is out of range", func(t *testing.T) {
+		req := newStep()
+		req.DelayHours = 24
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "delay hours must be between 0 and 23", err.Error())
+	})
+
+	t.Run("should return error when send window start is malformed", func(t *testing.T) {
+		req := newStep()
+		req.SendWindow = &dto.SendWindow{Start: "9am", End: "17:00"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "send window start must use the HH:MM format", err.Error())
+	})
+
+	t.Run("should return error when send window end is missing", func(t *testing.T) {
+		req := newStep()
+		req.SendWindow = &dto.SendWindow{Start: "09:00"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "send window end must use the HH:MM format", err.Error())
+	})
+
+	t.Run("should return error when send window end is not after start", func(t *testing.T) {
+		req := newStep()
+		req.SendWindow = &dto.SendWindow{Start: "17:00", End: "09:00"}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "send window end must be after start", err.Error())
+	})
+}
+
+func TestUpdateStepRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		delayHours := 4
+		req := dto.UpdateStepRequest{DelayHours: &delayHours, SendWindow: &dto.SendWindow{}}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when mail subject is empty", func(t *testing.T) {
+		mailSubject := ""
+		req := dto.UpdateStepRequest{MailSubject: &mailSubject}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "mail subject cannot be empty", err.Error())
+	})
+
+	t.Run("should return error when mail content template is invalid", func(t *testing.T) {
+		mailContent := "{{#if contact.company}}Hi"
+		req := dto.UpdateStepRequest{MailContent: &mailContent}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "invalid mail content template: {{#if}} at position 0 is not closed with {{/if}}", err.Error())
+	})
+
+	t.Run("should return error when mail html is empty", func(t *testing.T) {
+		mailHTML := ""
+		req := dto.UpdateStepRequest{MailHTML: &mailHTML}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "mail content cannot be empty", err.Error())
+	})
+
+	t.Run("should return error when delay days is negative", func(t *testing.T) {
+		delayDays := -2
+		req := dto.UpdateStepRequest{DelayDays: &delayDays}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "delay days cannot be negative", err.Error())
+	})
+
+	t.Run("should return error when send window is inverted", func(t *testing.T) {
+		req := dto.UpdateStepRequest{SendWindow: &dto.SendWindow{Start: "18:00", End: "08:00"}}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "send window end must be after start", err.Error())
+	})
+}
+
+func TestNewSendWindow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		start, end := int32(485), int32(1439)
+		assert.Equal(t, &dto.SendWindow{Start: "08:05", End: "23:59"}, dto.NewSendWindow(&start, &end))
+	})
+
+	t.Run("should return nil when the step has no window", func(t *testing.T) {
+		assert.Nil(t, dto.NewSendWindow(nil, nil))
+	})
+}
+
+func TestReorderStepsRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.ReorderStepsRequest{StepIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when step ids are empty", func(t *testing.T) {
+		req := dto.ReorderStepsRequest{}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "step ids are required", err.Error())
+	})
+
+	t.Run("should return error when step id is repeated", func(t *testing.T) {
+		stepID := uuid.New()
+		req := dto.ReorderStepsRequest{StepIDs: []uuid.UUID{stepID, stepID}}
+
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Equal(t, "step id "+stepID.String()+" is not unique", err.Error())
+	})
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldError is an invalid field of a request body, located by a JSON pointer
+// (RFC 6901), e.g. /steps/2/mailSubject.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+}
+
+// ValidationErrors holds every invalid field of a request body.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	details := make([]string, 0, len(e))
+	for _, fe := range e {
+		details = append(details, fe.Detail)
+	}
+	return strings.Join(details, "; ")
+}
+
+// validation collects the field errors of a request, so all of them are
+// reported at once.
+type validation struct {
+	errs ValidationErrors
+}
+
+func (v *validation) fail(pointer string, format string, args ...any) {
+	v.errs = append(v.errs, &FieldError{Pointer: pointer, Detail: fmt.Sprintf(format, args...)})
+}
+
+// check records err, if any, for the field at pointer. The errors of a nested
+// object are recorded under its pointer.
+func (v *validation) check(pointer string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		for _, fe := range nested {
+			v.errs = append(v.errs, &FieldError{Pointer: pointer + fe.Pointer, Detail: fe.Detail})
+		}
+		return
+	}
+
+	v.errs = append(v.errs, &FieldError{Pointer: pointer, Detail: err.Error()})
+}
+
+func (v *validation) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// pointer joins the tokens into a JSON pointer, escaping them as needed.
+func pointer(tokens ...any) string {
+	var sb strings.Builder
+
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		switch token := token.(type) {
+		case int:
+			sb.WriteString(strconv.Itoa(token))
+		case string:
+			sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type CreateVariantRequest struct {
+	MailSubject string `json:"mailSubject"`
+	MailContent string `json:"mailContent"`
+	Weight      int    `json:"weight"`
+}
+
+func (req *CreateVariantRequest) Validate() error {
+	var v validation
+
+	if req.MailSubject == "" {
+		v.fail("/mailSubject", "mail subject is required")
+	}
+	v.check("/mailSubject", validateTemplate("mail subject", req.MailSubject))
+
+	if req.MailContent == "" {
+		v.fail("/mailContent", "mail content is required")
+	}
+	v.check("/mailContent", validateTemplate("mail content", req.MailContent))
+
+	v.check("/weight", validateWeight(req.Weight))
+
+	return v.err()
+}
+
+type UpdateVariantRequest struct {
+	MailSubject *string `json:"mailSubject,omitempty"`
+	MailContent *string `json:"mailContent,omitempty"`
+	Weight      *int    `json:"weight,omitempty"`
+}
+
+func (req *UpdateVariantRequest) Validate() error {
+	var v validation
+
+	if req.MailSubject != nil {
+		if *req.MailSubject == "" {
+			v.fail("/mailSubject", "mail subject cannot be empty")
+		}
+		v.check("/mailSubject", validateTemplate("mail subject", *req.MailSubject))
+	}
+
+	if req.MailContent != nil {
+		if *req.MailContent == "" {
+			v.fail("/mailContent", "mail content cannot be empty")
+		}
+		v.check("/mailContent", validateTemplate("mail content", *req.MailContent))
+	}
+
+	if req.Weight != nil {
+		v.check("/weight", validateWeight(*req.Weight))
+	}
+
+	return v.err()
+}
+
+func validateWeight(weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("weight must be greater than zero")
+	}
+	return nil
+}
+
+type AssignVariantRequest struct {
+	EnrollmentID uuid.UUID `json:"enrollmentId"`
+}
+
+func (req *AssignVariantRequest) Validate() error {
+	var v validation
+
+	if req.EnrollmentID == uuid.Nil {
+		v.fail("/enrollmentId", "enrollment id is required")
+	}
+
+	return v.err()
+}
+
+type VariantResponse struct {
+	ExternalID  string   `json:"id"`
+	MailSubject string   `json:"mailSubject"`
+	MailContent string   `json:"mailContent"`
+	Weight      int      `json:"weight"`
+	Assignments int64    `json:"assignments"`
+	Variables   []string `json:"variables"`
+	CreatedAt   string   `json:"createdAt"`
+}
+
+type VariantAssignmentResponse struct {
+	EnrollmentID string `json:"enrollmentId"`
+	VariantID    string `json:"variantId"`
+	MailSubject  string `json:"mailSubject"`
+	MailContent  string `json:"mailContent"`
+}
@@ -0,0 +1,81 @@
+package dto_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVariantRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.CreateVariantRequest{MailSubject: "Hi {{contact.firstName}}", MailContent: "content", Weight: 1}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when mail subject is empty", func(t *testing.T) {
+		req := dto.CreateVariantRequest{MailContent: "content", Weight: 1}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "mail subject is required")
+	})
+
+	t.Run("should return error when mail content template is invalid", func(t *testing.T) {
+		req := dto.CreateVariantRequest{MailSubject: "subject", MailContent: "{{account.name}}", Weight: 1}
+
+		err := req.Validate()
+		assert.EqualError(t, err, `invalid mail content template: unknown variable namespace "account" at position 0`)
+	})
+
+	t.Run("should return error when weight is not positive", func(t *testing.T) {
+		req := dto.CreateVariantRequest{MailSubject: "subject", MailContent: "content"}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "weight must be greater than zero")
+	})
+}
+
+func TestUpdateVariantRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		weight := 3
+		req := dto.UpdateVariantRequest{Weight: &weight}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when mail subject is empty", func(t *testing.T) {
+		mailSubject := ""
+		req := dto.UpdateVariantRequest{MailSubject: &mailSubject}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "mail subject cannot be empty")
+	})
+
+	t.Run("should return error when weight is negative", func(t *testing.T) {
+		weight := -1
+		req := dto.UpdateVariantRequest{Weight: &weight}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "weight must be greater than zero")
+	})
+}
+
+func TestAssignVariantRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		req := dto.AssignVariantRequest{EnrollmentID: uuid.New()}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("should return error when enrollment id is missing", func(t *testing.T) {
+		req := dto.AssignVariantRequest{}
+
+		err := req.Validate()
+		assert.EqualError(t, err, "enrollment id is required")
+	})
+}
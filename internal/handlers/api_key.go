@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+type ApiKeyHandler interface {
+	GetApiKeys(w http.ResponseWriter, r *http.Request)
+	CreateApiKey(w http.ResponseWriter, r *http.Request)
+	RevokeApiKey(w http.ResponseWriter, r *http.Request)
+	RotateApiKey(w http.ResponseWriter, r *http.Request)
+}
+
+// apiKeyHandler does not cache its responses, the last use of the keys
+// changes with the requests they authenticate.
+type apiKeyHandler struct {
+	apiKeyService services.ApiKeyService
+}
+
+var _ ApiKeyHandler = (*apiKeyHandler)(nil)
+
+func NewApiKeyHandler(apiKeyService services.ApiKeyService) *apiKeyHandler {
+	return &apiKeyHandler{apiKeyService: apiKeyService}
+}
+
+func (h *apiKeyHandler) GetApiKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.GetApiKeys(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(keys)
+}
+
+func (h *apiKeyHandler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateApiKeyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateApiKey(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+func (h *apiKeyHandler) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "api key id must be a UUID")
+		return
+	}
+
+	if err := h.apiKeyService.RevokeApiKey(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *apiKeyHandler) RotateApiKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "api key id must be a UUID")
+		return
+	}
+
+	key, err := h.apiKeyService.RotateApiKey(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(key)
+}
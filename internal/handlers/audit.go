@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+)
+
+type AuditHandler interface {
+	GetAuditEvents(w http.ResponseWriter, r *http.Request)
+}
+
+// auditHandler does not cache its responses, every change appends an event
+// to the log.
+type auditHandler struct {
+	cfg          *config.Config
+	auditService services.AuditService
+}
+
+var _ AuditHandler = (*auditHandler)(nil)
+
+func NewAuditHandler(cfg *config.Config, auditService services.AuditService) *auditHandler {
+	return &auditHandler{cfg: cfg, auditService: auditService}
+}
+
+func (h *auditHandler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := utils.SafeAtoi(query.Get("limit"), 50)
+	if limit <= 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "limit must be greater than zero")
+		return
+	}
+
+	req, err := parseAuditEventPageRequest(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	req.Limit = min(limit, h.cfg.MaxSequencePagination)
+
+	if err := req.Validate(); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	page, err := h.auditService.GetAuditEvents(r.Context(), *req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setPaginationLinks(w, r, nil, page.NextCursor)
+	json.NewEncoder(w).Encode(page)
+}
+
+func parseAuditEventPageRequest(query url.Values) (*dto.AuditEventPageRequest, error) {
+	req := &dto.AuditEventPageRequest{
+		Cursor:     query.Get("cursor"),
+		Actor:      queryString(query, "actor"),
+		Action:     queryString(query, "action"),
+		TargetType: queryString(query, "targetType"),
+		RequestID:  queryString(query, "requestId"),
+	}
+
+	if targetID := query.Get("targetId"); targetID != "" {
+		id, err := uuid.Parse(targetID)
+		if err != nil {
+			return nil, fmt.Errorf("targetId must be a UUID")
+		}
+		req.TargetID = &id
+	}
+
+	var err error
+
+	if req.CreatedAfter, err = queryTime(query, "createdAfter"); err != nil {
+		return nil, err
+	}
+
+	if req.CreatedBefore, err = queryTime(query, "createdBefore"); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+type AuthHandler interface {
+	Authenticate(next http.Handler) http.Handler
+	Require(scope string, next http.HandlerFunc) http.HandlerFunc
+}
+
+type authHandler struct {
+	apiKeyService services.ApiKeyService
+	tokenVerifier *auth.TokenVerifier
+}
+
+var _ AuthHandler = (*authHandler)(nil)
+
+// NewAuthHandler accepts the bearer tokens of the users besides the API keys
+// when a JWKS is configured, in which case it is loaded right away so a wrong
+// configuration is found on startup.
+func NewAuthHandler(ctx context.Context, cfg *config.Config, apiKeyService services.ApiKeyService) (*authHandler, error) {
+	h := &authHandler{apiKeyService: apiKeyService}
+
+	if cfg.JWKSFile == "" && cfg.JWKSURL == "" {
+		return h, nil
+	}
+
+	if cfg.JWKSFile != "" && cfg.JWKSURL != "" {
+		return nil, errors.New("JWT_JWKS_FILE and JWT_JWKS_URL cannot be both set")
+	}
+
+	if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+		return nil, errors.New("JWT_ISSUER and JWT_AUDIENCE are required to verify bearer tokens")
+	}
+
+	refreshInterval := time.Duration(cfg.JWKSRefreshInterval) * time.Minute
+
+	var keys *auth.KeySet
+	if cfg.JWKSFile != "" {
+		keys = auth.NewFileKeySet(cfg.JWKSFile, refreshInterval)
+	} else {
+		keys = auth.NewURLKeySet(cfg.JWKSURL, &http.Client{Timeout: 10 * time.Second}, refreshInterval)
+	}
+
+	if err := keys.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	h.tokenVerifier = auth.NewTokenVerifier(keys, auth.TokenOptions{
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+		WorkspaceClaim: cfg.JWTWorkspaceClaim,
+		RolesClaim:     cfg.JWTRolesClaim,
+	})
+
+	return h, nil
+}
+
+// Authenticate sets the principal of the requests sending an API key or the
+// bearer token of a user in the Authorization header, answering with 401 when
+// it is invalid. Requests without the header go on without a principal, the
+// routes that need one are wrapped by Require.
+func (h *authHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_request"`)
+			writeProblem(w, r, http.StatusUnauthorized, problemUnauthorized, "Authorization header must be a bearer token")
+			return
+		}
+
+		if _, _, isApiKey := auth.ParseApiKey(token); !isApiKey && h.tokenVerifier != nil {
+			principal, err := h.tokenVerifier.Verify(r.Context(), token)
+			if err != nil {
+				slog.Warn("rejected bearer token", "error", err.Error())
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeProblem(w, r, http.StatusUnauthorized, problemInvalidToken, err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+			return
+		}
+
+		principal, err := h.apiKeyService.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, services.ErrorInvalidApiKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			writeError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// Require answers with 401 to the requests without a principal and with 403
+// to the ones whose principal was not granted scope.
+func (h *authHandler) Require(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.PrincipalFrom(r.Context())
+		if principal == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeProblem(w, r, http.StatusUnauthorized, problemUnauthorized, "request must be authenticated with an API key or a bearer token")
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+			writeProblem(w, r, http.StatusForbidden, problemInsufficientScope, fmt.Sprintf("caller was not granted the %s scope", scope))
+			return
+		}
+
+		next(w, r)
+	}
+}
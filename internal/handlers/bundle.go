@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/cache"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"gopkg.in/yaml.v3"
+)
+
+type BundleHandler interface {
+	ExportSequence(w http.ResponseWriter, r *http.Request)
+	ExportSequences(w http.ResponseWriter, r *http.Request)
+	ImportSequences(w http.ResponseWriter, r *http.Request)
+}
+
+type bundleHandler struct {
+	cfg           *config.Config
+	cache         cache.Cache
+	bundleService services.BundleService
+}
+
+var _ BundleHandler = (*bundleHandler)(nil)
+
+func NewBundleHandler(cfg *config.Config, cache cache.Cache, bundleService services.BundleService) *bundleHandler {
+	return &bundleHandler{cfg: cfg, cache: cache, bundleService: bundleService}
+}
+
+func (h *bundleHandler) ExportSequence(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	h.export(w, r, []uuid.UUID{uid})
+}
+
+// ExportSequences bundles the sequences listed in the ids parameter, which may
+// be repeated or hold several ids separated by commas.
+func (h *bundleHandler) ExportSequences(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+
+	for _, values := range r.URL.Query()["ids"] {
+		for value := range strings.SplitSeq(values, ",") {
+			uid, err := uuid.Parse(strings.TrimSpace(value))
+			if err != nil {
+				writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "ids must be UUIDs separated by commas")
+				return
+			}
+			ids = append(ids, uid)
+		}
+	}
+
+	if len(ids) == 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "ids is required")
+		return
+	}
+
+	if len(ids) > h.cfg.MaxSequencePagination {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, fmt.Sprintf("ids must have at most %d sequences", h.cfg.MaxSequencePagination))
+		return
+	}
+
+	h.export(w, r, ids)
+}
+
+// export writes the bundle as JSON, or as YAML when the format parameter or
+// the Accept header ask for it, as a file to download.
+func (h *bundleHandler) export(w http.ResponseWriter, r *http.Request, ids []uuid.UUID) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+		if strings.Contains(r.Header.Get("Accept"), "yaml") {
+			format = "yaml"
+		}
+	}
+
+	if format != "json" && format != "yaml" {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "format must be json or yaml")
+		return
+	}
+
+	bundle, err := h.bundleService.ExportSequences(r.Context(), ids)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="sequences.%s"`, format))
+
+	if format == "yaml" {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		yaml.NewEncoder(w).Encode(bundle)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bundle)
+}
+
+// ImportSequences reads the bundle as YAML when the request says so with its
+// Content-Type, and as JSON otherwise.
+func (h *bundleHandler) ImportSequences(w http.ResponseWriter, r *http.Request) {
+	opts := dto.ImportOptions{OnConflict: r.URL.Query().Get("onConflict")}
+	if opts.OnConflict == "" {
+		opts.OnConflict = dto.ConflictSkip
+	}
+
+	if err := opts.Validate(); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	dryRun, err := queryBool(r.URL.Query(), "dryRun")
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	opts.DryRun = dryRun != nil && *dryRun
+
+	decode := decodeJSON
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); isYAML(mediaType) {
+		decode = decodeYAML
+	}
+
+	var bundle dto.SequenceBundle
+	if err := decode(r, &bundle); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := bundle.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response, err := h.bundleService.ImportSequences(r.Context(), bundle, opts)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+
+	if !opts.DryRun {
+		h.cache.EvictAll()
+	}
+}
+
+func isYAML(mediaType string) bool {
+	return mediaType == "application/yaml" || mediaType == "application/x-yaml"
+}
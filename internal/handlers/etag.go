@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag is the strong entity tag of a resource version, e.g. "3".
+func etag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// cachedETag reads the version of a cached sequence or step, so the cached
+// bytes can be revalidated without a query.
+func cachedETag(raw []byte) (string, bool) {
+	var resource struct {
+		Version int32 `json:"version"`
+	}
+
+	if err := json.Unmarshal(raw, &resource); err != nil || resource.Version == 0 {
+		return "", false
+	}
+
+	return etag(resource.Version), true
+}
+
+// notModified reports whether the If-None-Match header of the request matches
+// the tag. Weak tags match their strong counterparts, as RFC 9110 asks for
+// If-None-Match.
+func notModified(r *http.Request, tag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatchVersion returns the version the If-Match header of the request asks
+// for, where 0 stands for any version, i.e. a missing header or "*". When it
+// returns false the response was already written: 428 for a missing header
+// when it is required, 412 for a header that can never match.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request, required bool) (int32, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	if header == "" {
+		if required {
+			writeProblem(w, r, http.StatusPreconditionRequired, problemPreconditionRequired, "If-Match header is required")
+			return 0, false
+		}
+		return 0, true
+	}
+
+	if header == "*" {
+		return 0, true
+	}
+
+	// If-Match uses the strong comparison, so weak tags never match
+	value, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		value, ok = strings.CutSuffix(value, `"`)
+	}
+
+	version, err := strconv.ParseInt(value, 10, 32)
+	if !ok || err != nil || version <= 0 {
+		writeProblem(w, r, http.StatusPreconditionFailed, problemPreconditionFailed, "If-Match must be a single ETag of the resource or *")
+		return 0, false
+	}
+
+	return int32(version), true
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyHandler interface {
+	Idempotent(next http.HandlerFunc) http.HandlerFunc
+}
+
+type idempotencyHandler struct {
+	idempotencyService services.IdempotencyService
+}
+
+var _ IdempotencyHandler = (*idempotencyHandler)(nil)
+
+func NewIdempotencyHandler(idempotencyService services.IdempotencyService) *idempotencyHandler {
+	return &idempotencyHandler{idempotencyService: idempotencyService}
+}
+
+// Idempotent makes next safe to retry with the Idempotency-Key header: the
+// first response with a key is stored and replayed to the requests sending the
+// key again, which must be the same request. Server errors are not stored, so
+// they can be retried. Requests without the header go straight to next.
+func (h *idempotencyHandler) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			writeProblem(w, r, http.StatusBadRequest, problemInvalidHeader, "Idempotency-Key must have at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, problemMalformedJSON, "request body could not be read")
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := h.idempotencyService.Begin(r.Context(), key, requestHash(r, body))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		if stored != nil {
+			for name, values := range stored.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(int(stored.StatusCode))
+			w.Write(stored.Body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+		// the key must not stay taken when the client goes away mid request
+		ctx := context.WithoutCancel(r.Context())
+
+		// nor when next panics, the panic goes on to the server once the key
+		// is released
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				h.idempotencyService.Release(ctx, key)
+				panic(recovered)
+			}
+		}()
+
+		next(recorder, r)
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			h.idempotencyService.Release(ctx, key)
+			return
+		}
+
+		h.idempotencyService.Complete(ctx, &models.IdempotencyKey{
+			Key:        key,
+			StatusCode: int32(recorder.statusCode),
+			Header:     w.Header().Clone(),
+			Body:       recorder.body.Bytes(),
+		})
+	}
+}
+
+// requestHash identifies the request by method, path and body, so a key cannot
+// be reused for another endpoint or sequence either.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder writes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
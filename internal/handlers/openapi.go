@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/openapi"
+)
+
+type OpenAPIHandler interface {
+	GetSpec(w http.ResponseWriter, r *http.Request)
+	GetDocs(w http.ResponseWriter, r *http.Request)
+}
+
+type openAPIHandler struct{}
+
+var _ OpenAPIHandler = (*openAPIHandler)(nil)
+
+func NewOpenAPIHandler() *openAPIHandler {
+	return &openAPIHandler{}
+}
+
+func (h *openAPIHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.Spec)
+}
+
+func (h *openAPIHandler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(openapi.Docs)
+}
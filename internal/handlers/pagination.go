@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// setPaginationLinks writes the RFC 8288 Link header for a cursor paginated
+// response, keeping every query parameter of the current request but the cursor.
+func setPaginationLinks(w http.ResponseWriter, r *http.Request, prevCursor *string, nextCursor *string) {
+	links := []string{paginationLink(r, "", "first")}
+
+	if prevCursor != nil {
+		links = append(links, paginationLink(r, *prevCursor, "prev"))
+	}
+
+	if nextCursor != nil {
+		links = append(links, paginationLink(r, *nextCursor, "next"))
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func paginationLink(r *http.Request, cursor string, rel string) string {
+	query := r.URL.Query()
+
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+type PreviewHandler interface {
+	PreviewStep(w http.ResponseWriter, r *http.Request)
+}
+
+type previewHandler struct {
+	previewService services.PreviewService
+}
+
+var _ PreviewHandler = (*previewHandler)(nil)
+
+func NewPreviewHandler(previewService services.PreviewService) *previewHandler {
+	return &previewHandler{previewService: previewService}
+}
+
+func (h *previewHandler) PreviewStep(w http.ResponseWriter, r *http.Request) {
+	seqid, err := uuid.Parse(r.PathValue("sequence_id"))
+	if err != nil {
+		slog.Warn("failed to parse sequence id", err.Error(), err)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
+		return
+	}
+
+	stid, err := uuid.Parse(r.PathValue("step_id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "step id must be a UUID")
+		return
+	}
+
+	var req dto.PreviewStepRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	preview, err := h.previewService.PreviewStep(r.Context(), seqid, stid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(preview)
+}
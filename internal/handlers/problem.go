@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"gopkg.in/yaml.v3"
+)
+
+// the codes of the problems raised by the handlers themselves, the services
+// errors carry their own
+const (
+	problemInvalidParameter     = "invalid-parameter"
+	problemInvalidHeader        = "invalid-header"
+	problemMalformedJSON        = "malformed-json"
+	problemMalformedYAML        = "malformed-yaml"
+	problemValidationError      = "validation-error"
+	problemNotFound             = "not-found"
+	problemPreconditionFailed   = "precondition-failed"
+	problemPreconditionRequired = "precondition-required"
+	problemInternalError        = "internal-error"
+	problemUnauthorized         = "unauthorized"
+	problemInvalidToken         = "invalid-token"
+	problemInsufficientScope    = "insufficient-scope"
+	problemRateLimited          = "rate-limited"
+)
+
+var statusByKind = map[services.ErrorKind]int{
+	services.KindNotFound:           http.StatusNotFound,
+	services.KindInvalid:            http.StatusBadRequest,
+	services.KindConflict:           http.StatusConflict,
+	services.KindPreconditionFailed: http.StatusPreconditionFailed,
+	services.KindUnprocessable:      http.StatusUnprocessableEntity,
+	services.KindForbidden:          http.StatusForbidden,
+	services.KindUnauthorized:       http.StatusUnauthorized,
+}
+
+// malformedBodyError is a request body that could not be decoded into the
+// request, Pointer locates the offending field when known. Code is the problem
+// of the error, malformed JSON unless told otherwise.
+type malformedBodyError struct {
+	Code    string
+	Pointer string
+	Detail  string
+}
+
+func (e *malformedBodyError) Error() string {
+	return e.Detail
+}
+
+// writeProblem writes an application/problem+json response.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code string, detail string) {
+	writeProblemBody(w, &dto.Problem{
+		Type:     "/problems/" + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+func writeProblemBody(w http.ResponseWriter, problem *dto.Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// writeError maps err to its problem: validation and decoding errors are 400,
+// services errors get the status of their kind and anything else is a 500
+// without details, as the services already logged it.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErrs dto.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		writeProblemBody(w, &dto.Problem{
+			Type:     "/problems/" + problemValidationError,
+			Title:    http.StatusText(http.StatusBadRequest),
+			Status:   http.StatusBadRequest,
+			Detail:   "request body has invalid fields",
+			Instance: r.URL.Path,
+			Errors:   validationErrs,
+		})
+		return
+	}
+
+	var malformed *malformedBodyError
+	if errors.As(err, &malformed) {
+		problem := &dto.Problem{
+			Type:     "/problems/" + cmp.Or(malformed.Code, problemMalformedJSON),
+			Title:    http.StatusText(http.StatusBadRequest),
+			Status:   http.StatusBadRequest,
+			Detail:   malformed.Detail,
+			Instance: r.URL.Path,
+		}
+		if malformed.Pointer != "" {
+			problem.Errors = []*dto.FieldError{{Pointer: malformed.Pointer, Detail: malformed.Detail}}
+		}
+		writeProblemBody(w, problem)
+		return
+	}
+
+	var serviceErr *services.Error
+	if errors.As(err, &serviceErr) {
+		writeProblem(w, r, statusByKind[serviceErr.Kind], serviceErr.Code, err.Error())
+		return
+	}
+
+	writeProblem(w, r, http.StatusInternalServerError, problemInternalError, "")
+}
+
+// decodeJSON reads the request body into v. Its errors tell where the body is
+// malformed, e.g. the offset of a syntax error or the field of a wrong type.
+func decodeJSON(r *http.Request, v any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return &malformedBodyError{Detail: "request body could not be read"}
+	}
+
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return &malformedBodyError{Detail: "request body is required"}
+	}
+
+	err = json.Unmarshal(body, v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &malformedBodyError{Detail: fmt.Sprintf("malformed JSON at offset %d: %s", syntaxErr.Offset, syntaxErr.Error())}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		malformed := &malformedBodyError{
+			Detail: fmt.Sprintf("must be %s, got %s at offset %d", typeErr.Type, typeErr.Value, typeErr.Offset),
+		}
+		if typeErr.Field != "" {
+			malformed.Pointer = "/" + strings.ReplaceAll(typeErr.Field, ".", "/")
+		}
+		return malformed
+	}
+
+	return &malformedBodyError{Detail: err.Error()}
+}
+
+// decodeYAML reads the YAML request body into v, its errors tell the line
+// where the body is malformed.
+func decodeYAML(r *http.Request, v any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return &malformedBodyError{Code: problemMalformedYAML, Detail: "request body could not be read"}
+	}
+
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return &malformedBodyError{Code: problemMalformedYAML, Detail: "request body is required"}
+	}
+
+	if err := yaml.Unmarshal(body, v); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return &malformedBodyError{Code: problemMalformedYAML, Detail: "malformed YAML: " + strings.Join(typeErr.Errors, "; ")}
+		}
+		return &malformedBodyError{Code: problemMalformedYAML, Detail: "malformed YAML: " + strings.TrimPrefix(err.Error(), "yaml: ")}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// queryString returns nil when the parameter is absent or empty.
+func queryString(query url.Values, key string) *string {
+	value := query.Get(key)
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func queryBool(query url.Values, key string) (*bool, error) {
+	if !query.Has(key) {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(query.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a boolean", key)
+	}
+
+	return &value, nil
+}
+
+func queryTime(query url.Values, key string) (*time.Time, error) {
+	if !query.Has(key) {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, query.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a RFC 3339 date", key)
+	}
+
+	return &value, nil
+}
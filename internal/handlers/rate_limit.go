@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/ratelimit"
+)
+
+type RateLimitHandler interface {
+	Limit(next http.Handler) http.Handler
+}
+
+type rateLimitHandler struct {
+	enabled    bool
+	limiter    ratelimit.Limiter
+	routes     *http.ServeMux
+	costs      map[string]int
+	trustProxy bool
+}
+
+var _ RateLimitHandler = (*rateLimitHandler)(nil)
+
+// NewRateLimitHandler parses the costs of the routes, RATE_LIMIT_COSTS, so a
+// wrong configuration is found on startup. The limiter is not used when the
+// rate limiting is disabled.
+func NewRateLimitHandler(cfg *config.Config, limiter ratelimit.Limiter) (*rateLimitHandler, error) {
+	h := &rateLimitHandler{enabled: cfg.RateLimitEnabled, limiter: limiter, trustProxy: cfg.RateLimitTrustProxy}
+
+	if !h.enabled {
+		return h, nil
+	}
+
+	routes, costs, err := parseRouteCosts(cfg.RateLimitCosts, cfg.RateLimitBurst)
+	if err != nil {
+		return nil, err
+	}
+
+	h.routes = routes
+	h.costs = costs
+
+	return h, nil
+}
+
+// Limit takes the cost of the route of the request from the bucket of its
+// client, answering with 429 when the bucket does not hold it. It runs before
+// the requests are authenticated, so floods of requests are refused before
+// their credentials are looked up or verified. Requests go on when the
+// limiter fails, as the database being down must not make the API refuse the
+// requests it could still serve.
+func (h *rateLimitHandler) Limit(next http.Handler) http.Handler {
+	if !h.enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := h.limiter.Take(r.Context(), h.client(r), h.cost(r))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", seconds(result.Reset))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", seconds(result.RetryAfter))
+			writeProblem(w, r, http.StatusTooManyRequests, problemRateLimited, "rate limit exceeded, retry in "+seconds(result.RetryAfter)+" seconds")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// client is the credentials of the request, hashed so the buckets do not hold
+// them, or its IP without any. The credentials are not verified yet, but
+// requests with invalid ones are refused by the authentication, spending the
+// tokens of a bucket no valid client uses.
+func (h *rateLimitHandler) client(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		sum := sha256.Sum256([]byte(header))
+		return "credentials:" + hex.EncodeToString(sum[:])
+	}
+
+	return "ip:" + h.clientIP(r)
+}
+
+// clientIP is the address the request comes from. Behind a reverse proxy it
+// is the last address of X-Forwarded-For, the one the proxy appended, as the
+// ones before it are sent by the client and can be anything.
+func (h *rateLimitHandler) clientIP(r *http.Request) string {
+	if h.trustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(addresses[len(addresses)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func (h *rateLimitHandler) cost(r *http.Request) int {
+	_, pattern := h.routes.Handler(r)
+	if cost, ok := h.costs[pattern]; ok {
+		return cost
+	}
+
+	return 1
+}
+
+// parseRouteCosts parses the comma separated costs of the routes, as in
+// "GET /sequences=5", the routes being patterns of http.ServeMux. It returns
+// a mux matching the requests to the patterns and the cost of each pattern.
+func parseRouteCosts(value string, burst int) (routes *http.ServeMux, costs map[string]int, err error) {
+	routes = http.NewServeMux()
+	costs = make(map[string]int)
+
+	// the mux panics on invalid and conflicting patterns
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			routes, costs, err = nil, nil, fmt.Errorf("invalid RATE_LIMIT_COSTS: %v", recovered)
+		}
+	}()
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, rawCost, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid RATE_LIMIT_COSTS entry %q, it must be as in \"GET /sequences=5\"", entry)
+		}
+
+		pattern = strings.TrimSpace(pattern)
+
+		cost, err := strconv.Atoi(strings.TrimSpace(rawCost))
+		if err != nil || cost < 0 {
+			return nil, nil, fmt.Errorf("invalid RATE_LIMIT_COSTS cost of %q", pattern)
+		}
+
+		if cost > burst {
+			return nil, nil, fmt.Errorf("RATE_LIMIT_COSTS cost of %q is above RATE_LIMIT_BURST, its requests would never be allowed", pattern)
+		}
+
+		routes.Handle(pattern, http.NotFoundHandler())
+		costs[pattern] = cost
+	}
+
+	return routes, costs, nil
+}
+
+// seconds formats d as whole seconds, rounding up so clients retrying after
+// them are not refused again.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
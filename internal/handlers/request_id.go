@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tracing"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength is the longest request id taken from a client, it matches
+// the size of the column of the audit events.
+const maxRequestIDLength = 128
+
+type RequestIDHandler interface {
+	Identify(next http.Handler) http.Handler
+}
+
+type requestIDHandler struct{}
+
+var _ RequestIDHandler = (*requestIDHandler)(nil)
+
+func NewRequestIDHandler() *requestIDHandler {
+	return &requestIDHandler{}
+}
+
+// Identify gives every request an id, the one sent by the client in the
+// X-Request-Id header when it is valid or a random one otherwise, and echoes
+// it in the response so a client can look up what its request did.
+func (h *requestIDHandler) Identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+
+		next.ServeHTTP(w, r.WithContext(tracing.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// validRequestID accepts ids of printable ASCII characters only, so they are
+// safe to store and to write back in a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
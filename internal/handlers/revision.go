@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/cache"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+)
+
+type RevisionHandler interface {
+	GetRevisions(w http.ResponseWriter, r *http.Request)
+	GetRevision(w http.ResponseWriter, r *http.Request)
+	DiffRevisions(w http.ResponseWriter, r *http.Request)
+	RollbackRevision(w http.ResponseWriter, r *http.Request)
+}
+
+type revisionHandler struct {
+	cfg             *config.Config
+	cache           cache.Cache
+	revisionService services.RevisionService
+}
+
+var _ RevisionHandler = (*revisionHandler)(nil)
+
+func NewRevisionHandler(cfg *config.Config, cache cache.Cache, revisionService services.RevisionService) *revisionHandler {
+	return &revisionHandler{cfg: cfg, cache: cache, revisionService: revisionService}
+}
+
+func (h *revisionHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	size := utils.SafeAtoi(r.URL.Query().Get("size"), 50)
+
+	size = min(size, h.cfg.MaxSequencePagination)
+
+	page := utils.SafeAtoi(r.URL.Query().Get("page"), 0)
+
+	revisions, err := h.revisionService.GetRevisions(r.Context(), uid, size, page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(revisions)
+}
+
+func (h *revisionHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	revision, err := parseRevision(r.PathValue("revision"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "revision must be a revision number")
+		return
+	}
+
+	found, err := h.revisionService.GetRevision(r.Context(), uid, revision)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(found)
+}
+
+func (h *revisionHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	from, err := parseRevision(r.URL.Query().Get("from"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "from must be a revision number")
+		return
+	}
+
+	to, err := parseRevision(r.URL.Query().Get("to"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "to must be a revision number")
+		return
+	}
+
+	diff, err := h.revisionService.DiffRevisions(r.Context(), uid, from, to)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(diff)
+}
+
+func (h *revisionHandler) RollbackRevision(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	revision, err := parseRevision(r.PathValue("revision"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "revision must be a revision number")
+		return
+	}
+
+	sequence, err := h.revisionService.RollbackRevision(r.Context(), uid, revision)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sequence)
+
+	h.cache.EvictAll()
+}
+
+func parseRevision(value string) (int, error) {
+	revision, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if revision <= 0 {
+		return 0, strconv.ErrRange
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+type RoleBindingHandler interface {
+	GetRoleBindings(w http.ResponseWriter, r *http.Request)
+	CreateRoleBinding(w http.ResponseWriter, r *http.Request)
+	DeleteRoleBinding(w http.ResponseWriter, r *http.Request)
+}
+
+// roleBindingHandler does not cache its responses, a stale binding would
+// keep granting a role after its removal.
+type roleBindingHandler struct {
+	roleBindingService services.RoleBindingService
+}
+
+var _ RoleBindingHandler = (*roleBindingHandler)(nil)
+
+func NewRoleBindingHandler(roleBindingService services.RoleBindingService) *roleBindingHandler {
+	return &roleBindingHandler{roleBindingService: roleBindingService}
+}
+
+func (h *roleBindingHandler) GetRoleBindings(w http.ResponseWriter, r *http.Request) {
+	bindings, err := h.roleBindingService.GetRoleBindings(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(bindings)
+}
+
+func (h *roleBindingHandler) CreateRoleBinding(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRoleBindingRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	binding, err := h.roleBindingService.CreateRoleBinding(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(binding)
+}
+
+func (h *roleBindingHandler) DeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "role binding id must be a UUID")
+		return
+	}
+
+	if err := h.roleBindingService.DeleteRoleBinding(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
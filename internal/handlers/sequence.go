@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
@@ -17,7 +19,13 @@ type SequenceHandler interface {
 	GetSequences(w http.ResponseWriter, r *http.Request)
 	GetSequence(w http.ResponseWriter, r *http.Request)
 	UpdateSequence(w http.ResponseWriter, r *http.Request)
+	ReplaceSequence(w http.ResponseWriter, r *http.Request)
 	CreateSequence(w http.ResponseWriter, r *http.Request)
+	DeleteSequence(w http.ResponseWriter, r *http.Request)
+	GetDeletedSequences(w http.ResponseWriter, r *http.Request)
+	RestoreSequence(w http.ResponseWriter, r *http.Request)
+	TransitionSequence(w http.ResponseWriter, r *http.Request)
+	CloneSequence(w http.ResponseWriter, r *http.Request)
 }
 
 type sequenceHandler struct {
@@ -31,61 +39,149 @@ func NewSequenceHandler(cfg *config.Config, cache cache.Cache, sequenceService s
 }
 
 func (h *sequenceHandler) GetSequences(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Has("page") || query.Has("size") {
+		h.getSequencesByOffset(w, r)
+		return
+	}
+
+	limit := utils.SafeAtoi(query.Get("limit"), 50)
+	if limit <= 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "limit must be greater than zero")
+		return
+	}
+
+	req, err := parseSequencePageRequest(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	req.Limit = min(limit, h.cfg.MaxSequencePagination)
+
+	if err := req.Validate(); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, err.Error())
+		return
+	}
+
+	// every parsed parameter takes part in the key, so each filter combination gets its own entry
+	rawReq, err := json.Marshal(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	key, cacheable := workspaceKey(r, "sequences-page-"+string(rawReq))
+
+	if raw := h.cache.Get(key); cacheable && raw != nil {
+		var page dto.SequencePageResponse
+		if err := json.Unmarshal(raw, &page); err == nil {
+			setPaginationLinks(w, r, page.PrevCursor, page.NextCursor)
+			w.Write(raw)
+			return
+		}
+	}
+
+	page, err := h.sequenceService.GetSequencesPage(r.Context(), *req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	raw, err := json.Marshal(page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setPaginationLinks(w, r, page.PrevCursor, page.NextCursor)
+	w.Write(raw)
+
+	if cacheable {
+		h.cache.Set(key, raw)
+	}
+}
+
+// getSequencesByOffset serves the deprecated page/size pagination, kept for the
+// clients that did not move to cursors yet.
+func (h *sequenceHandler) getSequencesByOffset(w http.ResponseWriter, r *http.Request) {
 	size := utils.SafeAtoi(r.URL.Query().Get("size"), 50)
 
 	size = min(size, h.cfg.MaxSequencePagination)
 
 	page := utils.SafeAtoi(r.URL.Query().Get("page"), 0)
 
-	key := fmt.Sprintf("sequences-%d-%d", size, page)
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", fmt.Sprintf(`<%s?limit=%d>; rel="successor-version"`, r.URL.Path, size))
+
+	key, cacheable := workspaceKey(r, fmt.Sprintf("sequences-%d-%d", size, page))
 
-	if h.cache.Get(key) != nil {
+	if cacheable && h.cache.Get(key) != nil {
 		w.Write(h.cache.Get(key))
 		return
 	}
 
 	sequences, err := h.sequenceService.GetSequences(r.Context(), size, page)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	json.NewEncoder(w).Encode(sequences)
 
-	if raw, err := json.Marshal(sequences); err == nil {
+	if raw, err := json.Marshal(sequences); err == nil && cacheable {
 		h.cache.Set(key, raw)
 	}
 }
 
 func (h *sequenceHandler) GetSequence(w http.ResponseWriter, r *http.Request) {
-	if h.cache.Get("sequence-"+r.PathValue("id")) != nil {
-		w.Write(h.cache.Get("sequence-" + r.PathValue("id")))
+	id := r.PathValue("id")
+
+	key, cacheable := workspaceKey(r, "sequence-"+id)
+
+	if raw := h.cache.Get(key); cacheable && raw != nil {
+		if tag, ok := cachedETag(raw); ok {
+			w.Header().Set("ETag", tag)
+			if notModified(r, tag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Write(raw)
 		return
 	}
 
-	id := r.PathValue("id")
-
 	uid, err := uuid.Parse(id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
 		return
 	}
 
 	sequence, err := h.sequenceService.GetSequence(r.Context(), uid)
 	if err != nil {
-		if err == services.ErrorSequenceNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(sequence)
+	raw, err := json.Marshal(sequence)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if cacheable {
+		h.cache.Set(key, raw)
+	}
 
-	if raw, err := json.Marshal(sequence); err == nil {
-		h.cache.Set("sequence-"+id, raw)
+	tag := etag(sequence.Version)
+	w.Header().Set("ETag", tag)
+	if notModified(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
+
+	w.Write(raw)
 }
 
 func (h *sequenceHandler) UpdateSequence(w http.ResponseWriter, r *http.Request) {
@@ -93,26 +189,71 @@ func (h *sequenceHandler) UpdateSequence(w http.ResponseWriter, r *http.Request)
 
 	uid, err := uuid.Parse(id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
 		return
 	}
 
 	var req dto.UpdateSequenceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	sequence, err := h.sequenceService.UpdateSequence(r.Context(), uid, req)
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
+		return
+	}
+
+	sequence, err := h.sequenceService.UpdateSequence(r.Context(), uid, version, req)
 	if err != nil {
-		if err == services.ErrorSequenceNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag(sequence.Version))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sequence)
+
+	h.cache.EvictAll()
+}
+
+func (h *sequenceHandler) ReplaceSequence(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	var req dto.ReplaceSequenceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
+		return
+	}
+
+	sequence, err := h.sequenceService.ReplaceSequence(r.Context(), uid, version, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(sequence.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(sequence)
 
@@ -121,25 +262,185 @@ func (h *sequenceHandler) UpdateSequence(w http.ResponseWriter, r *http.Request)
 
 func (h *sequenceHandler) CreateSequence(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateSequenceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(&dto.HTTPError{Message: err.Error()})
+		writeError(w, r, err)
 		return
 	}
 
 	sequence, err := h.sequenceService.CreateSequence(r.Context(), req)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(sequence.Version))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sequence)
+
+	h.cache.EvictAll()
+}
+
+func (h *sequenceHandler) DeleteSequence(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
+		return
+	}
+
+	if err := h.sequenceService.DeleteSequence(r.Context(), uid, version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	h.cache.EvictAll()
+}
+
+func (h *sequenceHandler) GetDeletedSequences(w http.ResponseWriter, r *http.Request) {
+	size := utils.SafeAtoi(r.URL.Query().Get("size"), 50)
+
+	size = min(size, h.cfg.MaxSequencePagination)
+
+	page := utils.SafeAtoi(r.URL.Query().Get("page"), 0)
+
+	sequences, err := h.sequenceService.GetDeletedSequences(r.Context(), size, page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(sequences)
+}
+
+func (h *sequenceHandler) RestoreSequence(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	sequence, err := h.sequenceService.RestoreSequence(r.Context(), uid)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(sequence.Version))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sequence)
+
+	h.cache.EvictAll()
+}
+
+// TransitionSequence serves the lifecycle actions, e.g. POST
+// /sequences/{id}:activate. The action is part of the last path segment, which
+// the mux cannot match, so it is split from the id here.
+func (h *sequenceHandler) TransitionSequence(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := strings.Cut(r.PathValue("id"), ":")
+	if !ok {
+		writeProblem(w, r, http.StatusNotFound, problemNotFound, "")
 		return
 	}
 
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	sequence, err := h.sequenceService.TransitionSequence(r.Context(), uid, action)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(sequence.Version))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sequence)
+
+	h.cache.EvictAll()
+}
+
+// CloneSequence accepts requests without a body, which clone the sequence as
+// it is.
+func (h *sequenceHandler) CloneSequence(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "id must be a UUID")
+		return
+	}
+
+	var req dto.CloneSequenceRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sequence, err := h.sequenceService.CloneSequence(r.Context(), uid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(sequence.Version))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(sequence)
 
 	h.cache.EvictAll()
 }
+
+func parseSequencePageRequest(query url.Values) (*dto.SequencePageRequest, error) {
+	req := &dto.SequencePageRequest{
+		Cursor:            query.Get("cursor"),
+		IncludeTotalCount: query.Get("includeTotalCount") == "true",
+		Name:              queryString(query, "name"),
+		Search:            queryString(query, "q"),
+		Sort:              query.Get("sort"),
+	}
+
+	var err error
+
+	if req.OpenTrackingEnabled, err = queryBool(query, "openTrackingEnabled"); err != nil {
+		return nil, err
+	}
+
+	if req.ClickTrackingEnabled, err = queryBool(query, "clickTrackingEnabled"); err != nil {
+		return nil, err
+	}
+
+	if req.CreatedAfter, err = queryTime(query, "createdAfter"); err != nil {
+		return nil, err
+	}
+
+	if req.CreatedBefore, err = queryTime(query, "createdBefore"); err != nil {
+		return nil, err
+	}
+
+	if req.UpdatedAfter, err = queryTime(query, "updatedAfter"); err != nil {
+		return nil, err
+	}
+
+	if req.UpdatedBefore, err = queryTime(query, "updatedBefore"); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
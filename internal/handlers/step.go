@@ -1,64 +1,168 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/cache"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
 	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
 )
 
 type StepHandler interface {
+	GetSteps(w http.ResponseWriter, r *http.Request)
+	GetStep(w http.ResponseWriter, r *http.Request)
 	CreateStep(w http.ResponseWriter, r *http.Request)
 	UpdateStep(w http.ResponseWriter, r *http.Request)
 	DeleteStep(w http.ResponseWriter, r *http.Request)
+	ReorderSteps(w http.ResponseWriter, r *http.Request)
 }
 
 type stepHandler struct {
+	cfg         *config.Config
 	cache       cache.Cache
 	stepService services.StepService
 }
 
 var _ StepHandler = (*stepHandler)(nil)
 
-func NewStepHandler(cache cache.Cache, stepService services.StepService) *stepHandler {
-	return &stepHandler{stepService: stepService, cache: cache}
+func NewStepHandler(cfg *config.Config, cache cache.Cache, stepService services.StepService) *stepHandler {
+	return &stepHandler{cfg: cfg, stepService: stepService, cache: cache}
+}
+
+func (h *stepHandler) GetSteps(w http.ResponseWriter, r *http.Request) {
+	sequenceId := r.PathValue("sequence_id")
+
+	seqid, err := uuid.Parse(sequenceId)
+	if err != nil {
+		slog.Warn("failed to parse sequence id", err.Error(), err)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := utils.SafeAtoi(query.Get("limit"), 50)
+	if limit <= 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "limit must be greater than zero")
+		return
+	}
+
+	req := dto.StepPageRequest{
+		Cursor: query.Get("cursor"),
+		Limit:  min(limit, h.cfg.MaxSequencePagination),
+	}
+
+	key, cacheable := workspaceKey(r, fmt.Sprintf("steps-%s-%d-%s", sequenceId, req.Limit, req.Cursor))
+
+	if raw := h.cache.Get(key); cacheable && raw != nil {
+		var page dto.StepPageResponse
+		if err := json.Unmarshal(raw, &page); err == nil {
+			setPaginationLinks(w, r, nil, page.NextCursor)
+			w.Write(raw)
+			return
+		}
+	}
+
+	page, err := h.stepService.GetSteps(r.Context(), seqid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	raw, err := json.Marshal(page)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setPaginationLinks(w, r, nil, page.NextCursor)
+	w.Write(raw)
+
+	if cacheable {
+		h.cache.Set(key, raw)
+	}
+}
+
+func (h *stepHandler) GetStep(w http.ResponseWriter, r *http.Request) {
+	sequenceId := r.PathValue("sequence_id")
+	stepId := r.PathValue("step_id")
+
+	// the key holds both ids so a step is never served under another sequence
+	key, cacheable := workspaceKey(r, "step-"+sequenceId+"-"+stepId)
+
+	if raw := h.cache.Get(key); cacheable && raw != nil {
+		if tag, ok := cachedETag(raw); ok {
+			w.Header().Set("ETag", tag)
+		}
+		w.Write(raw)
+		return
+	}
+
+	seqid, err := uuid.Parse(sequenceId)
+	if err != nil {
+		slog.Warn("failed to parse sequence id", err.Error(), err)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
+		return
+	}
+
+	stid, err := uuid.Parse(stepId)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "step id must be a UUID")
+		return
+	}
+
+	step, err := h.stepService.GetStep(r.Context(), seqid, stid)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(step.Version))
+	json.NewEncoder(w).Encode(step)
+
+	if raw, err := json.Marshal(step); err == nil && cacheable {
+		h.cache.Set(key, raw)
+	}
 }
 
 func (h *stepHandler) CreateStep(w http.ResponseWriter, r *http.Request) {
 	sequenceID, err := uuid.Parse(r.PathValue("sequence_id"))
 	if err != nil {
 		slog.Warn("failed to parse sequence id", err.Error(), err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
 		return
 	}
 
 	var req dto.CreateStepRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(&dto.HTTPError{Message: err.Error()})
+		writeError(w, r, err)
 		return
 	}
 
 	step, err := h.stepService.CreateStep(r.Context(), sequenceID, req)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag(step.Version))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(step)
 
-	h.cache.Evict("sequence-" + r.PathValue("sequence_id"))
+	// steps are embedded in the sequence and step entries, whose keys cannot
+	// be listed, so the whole cache is dropped
+	h.cache.EvictAll()
 }
 
 func (h *stepHandler) UpdateStep(w http.ResponseWriter, r *http.Request) {
@@ -68,36 +172,43 @@ func (h *stepHandler) UpdateStep(w http.ResponseWriter, r *http.Request) {
 	seqid, err := uuid.Parse(sequenceId)
 	if err != nil {
 		slog.Warn("failed to parse sequence id", err.Error(), err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
 		return
 	}
 
 	stid, err := uuid.Parse(stepId)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "step id must be a UUID")
 		return
 	}
 
 	var req dto.UpdateStepRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
 		return
 	}
 
-	step, err := h.stepService.UpdateStep(r.Context(), seqid, stid, req)
+	step, err := h.stepService.UpdateStep(r.Context(), seqid, stid, version, req)
 	if err != nil {
-		if err == services.ErrorStepNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag(step.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(step)
 
-	h.cache.Evict("sequence-" + sequenceId)
+	h.cache.EvictAll()
 }
 
 func (h *stepHandler) DeleteStep(w http.ResponseWriter, r *http.Request) {
@@ -107,23 +218,67 @@ func (h *stepHandler) DeleteStep(w http.ResponseWriter, r *http.Request) {
 	_, err := uuid.Parse(sequenceId)
 	if err != nil {
 		slog.Warn("failed to parse sequence id", err.Error(), err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
 		return
 	}
 
 	stid, err := uuid.Parse(stepId)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "step id must be a UUID")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
 		return
 	}
 
-	err = h.stepService.DeleteStep(context.Background(), stid)
+	err = h.stepService.DeleteStep(r.Context(), stid, version)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 
-	h.cache.Evict("sequence-" + sequenceId)
+	h.cache.EvictAll()
+}
+
+func (h *stepHandler) ReorderSteps(w http.ResponseWriter, r *http.Request) {
+	sequenceId := r.PathValue("sequence_id")
+
+	seqid, err := uuid.Parse(sequenceId)
+	if err != nil {
+		slog.Warn("failed to parse sequence id", err.Error(), err)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
+		return
+	}
+
+	var req dto.ReorderStepsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// the order belongs to the sequence, so If-Match carries the sequence ETag
+	version, ok := ifMatchVersion(w, r, h.cfg.RequireIfMatch)
+	if !ok {
+		return
+	}
+
+	steps, err := h.stepService.ReorderSteps(r.Context(), seqid, version, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(steps)
+
+	h.cache.EvictAll()
 }
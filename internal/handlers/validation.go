@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"gopkg.in/yaml.v3"
+)
+
+const problemResponseValidationError = "response-validation-error"
+
+type ValidationHandler interface {
+	Validate(next http.Handler) http.Handler
+}
+
+type validationHandler struct {
+	cfg    *config.Config
+	router routers.Router
+}
+
+var _ ValidationHandler = (*validationHandler)(nil)
+
+func NewValidationHandler(cfg *config.Config, doc *openapi3.T) (*validationHandler, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	openapi3filter.RegisterBodyDecoder("application/yaml", yamlBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-yaml", yamlBodyDecoder)
+
+	return &validationHandler{cfg: cfg, router: router}, nil
+}
+
+// Validate checks the requests to the routes of the OpenAPI document against
+// it, when VALIDATE_REQUESTS is set, and so do the responses when
+// VALIDATE_RESPONSES is set, which is meant for the tests: a response that
+// drifted from the document is replaced by a 500. Routes left out of the
+// document go straight to next.
+func (h *validationHandler) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := h.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the bodies were always read as JSON, whatever the content type, so
+		// bodies without one are validated as JSON. The header is only set on
+		// a copy of the request, next gets the request as it was sent.
+		validated := r
+		if r.Header.Get("Content-Type") == "" {
+			validated = r.Clone(r.Context())
+			validated.Header.Set("Content-Type", "application/json")
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    validated,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:          true,
+				SkipSettingDefaults: true,
+				// the credentials were already checked by the AuthHandler
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if h.cfg.ValidateRequests {
+			err := openapi3filter.ValidateRequest(r.Context(), input)
+
+			// the validation read the body and left a copy of it in its place
+			r.Body = validated.Body
+
+			if err != nil && writeRequestError(w, r, err) {
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if !h.cfg.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res := &bufferedResponse{header: w.Header(), statusCode: http.StatusOK}
+
+		next.ServeHTTP(res, r)
+
+		err = openapi3filter.ValidateResponse(context.WithoutCancel(r.Context()), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 res.statusCode,
+			Header:                 res.header,
+			Body:                   io.NopCloser(bytes.NewReader(res.body.Bytes())),
+			Options:                &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true},
+		})
+		if err != nil {
+			slog.Error("response does not match the OpenAPI document", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+			writeProblem(w, r, http.StatusInternalServerError, problemResponseValidationError, err.Error())
+			return
+		}
+
+		w.WriteHeader(res.statusCode)
+		w.Write(res.body.Bytes())
+	})
+}
+
+// writeRequestError writes the problem of a request that does not match the
+// document and reports whether it did. Bodies that are not valid JSON are left
+// to the handlers, which tell where the JSON is malformed.
+func writeRequestError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var requestErrs []*openapi3filter.RequestError
+
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, err := range multi {
+			var requestErr *openapi3filter.RequestError
+			if errors.As(err, &requestErr) {
+				requestErrs = append(requestErrs, requestErr)
+			}
+		}
+	} else {
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) {
+			requestErrs = append(requestErrs, requestErr)
+		}
+	}
+
+	if len(requestErrs) == 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemValidationError, err.Error())
+		return true
+	}
+
+	var (
+		parameters []string
+		fields     []*dto.FieldError
+	)
+
+	for _, requestErr := range requestErrs {
+		if requestErr.Parameter != nil {
+			parameters = append(parameters, parameterError(requestErr))
+			continue
+		}
+
+		var parseErr *openapi3filter.ParseError
+		if errors.As(requestErr.Err, &parseErr) {
+			return false
+		}
+
+		fields = append(fields, schemaFieldErrors(requestErr)...)
+	}
+
+	slices.SortStableFunc(fields, func(a, b *dto.FieldError) int {
+		return strings.Compare(a.Pointer, b.Pointer)
+	})
+
+	if len(parameters) > 0 {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, strings.Join(parameters, "; "))
+		return true
+	}
+
+	writeProblemBody(w, &dto.Problem{
+		Type:     "/problems/" + problemValidationError,
+		Title:    http.StatusText(http.StatusBadRequest),
+		Status:   http.StatusBadRequest,
+		Detail:   "request body has invalid fields",
+		Instance: r.URL.Path,
+		Errors:   fields,
+	})
+	return true
+}
+
+// parameterError describes the error of a parameter by the reason of its schema
+// error, leaving out the schema and the value the error carries.
+func parameterError(requestErr *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		return fmt.Sprintf("parameter %q in %s has an error: %s", requestErr.Parameter.Name, requestErr.Parameter.In, schemaErr.Reason)
+	}
+
+	return requestErr.Error()
+}
+
+// schemaFieldErrors locates the schema errors of the request body by JSON
+// pointer, errors that are not about a field point at the whole body.
+func schemaFieldErrors(requestErr *openapi3filter.RequestError) []*dto.FieldError {
+	var schemaErrs []*openapi3.SchemaError
+
+	var multi openapi3.MultiError
+	if errors.As(requestErr.Err, &multi) {
+		for _, err := range multi {
+			var schemaErr *openapi3.SchemaError
+			if errors.As(err, &schemaErr) {
+				schemaErrs = append(schemaErrs, schemaErr)
+			}
+		}
+	} else {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			schemaErrs = append(schemaErrs, schemaErr)
+		}
+	}
+
+	if errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired) {
+		return []*dto.FieldError{{Pointer: "", Detail: "request body is required"}}
+	}
+
+	if len(schemaErrs) == 0 {
+		return []*dto.FieldError{{Pointer: "", Detail: requestErr.Error()}}
+	}
+
+	fields := make([]*dto.FieldError, 0, len(schemaErrs))
+	for _, schemaErr := range schemaErrs {
+		fields = append(fields, &dto.FieldError{Pointer: jsonPointer(schemaErr.JSONPointer()), Detail: schemaErr.Reason})
+	}
+
+	return fields
+}
+
+// yamlBodyDecoder reads the timestamps of YAML bodies as strings, like
+// decodeYAML does when decoding into the string fields of the requests, as no
+// schema accepts the dates they would become otherwise.
+func yamlBodyDecoder(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(body).Decode(&node); err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	timestampsAsStrings(&node)
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	return value, nil
+}
+
+func timestampsAsStrings(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+
+	for _, child := range node.Content {
+		timestampsAsStrings(child)
+	}
+}
+
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+
+	return sb.String()
+}
+
+// bufferedResponse holds the response back until it is validated.
+type bufferedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *bufferedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponse) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+type VariantHandler interface {
+	GetVariants(w http.ResponseWriter, r *http.Request)
+	GetVariant(w http.ResponseWriter, r *http.Request)
+	CreateVariant(w http.ResponseWriter, r *http.Request)
+	UpdateVariant(w http.ResponseWriter, r *http.Request)
+	DeleteVariant(w http.ResponseWriter, r *http.Request)
+	AssignVariant(w http.ResponseWriter, r *http.Request)
+}
+
+// variantHandler does not cache its responses, since the assignment counts of
+// the variants change with every new enrollment.
+type variantHandler struct {
+	variantService services.VariantService
+}
+
+var _ VariantHandler = (*variantHandler)(nil)
+
+func NewVariantHandler(variantService services.VariantService) *variantHandler {
+	return &variantHandler{variantService: variantService}
+}
+
+func (h *variantHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	variants, err := h.variantService.GetVariants(r.Context(), seqid, stid)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(variants)
+}
+
+func (h *variantHandler) GetVariant(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	vid, err := uuid.Parse(r.PathValue("variant_id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "variant id must be a UUID")
+		return
+	}
+
+	variant, err := h.variantService.GetVariant(r.Context(), seqid, stid, vid)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(variant)
+}
+
+func (h *variantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateVariantRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	variant, err := h.variantService.CreateVariant(r.Context(), seqid, stid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(variant)
+}
+
+func (h *variantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	vid, err := uuid.Parse(r.PathValue("variant_id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "variant id must be a UUID")
+		return
+	}
+
+	var req dto.UpdateVariantRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	variant, err := h.variantService.UpdateVariant(r.Context(), seqid, stid, vid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(variant)
+}
+
+func (h *variantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	vid, err := uuid.Parse(r.PathValue("variant_id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "variant id must be a UUID")
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(r.Context(), seqid, stid, vid); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *variantHandler) AssignVariant(w http.ResponseWriter, r *http.Request) {
+	seqid, stid, ok := parseStepPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.AssignVariantRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	assignment, err := h.variantService.AssignVariant(r.Context(), seqid, stid, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// parseStepPath parses the sequence and step ids of the path, answering with
+// 400 when any of them is invalid.
+func parseStepPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	seqid, err := uuid.Parse(r.PathValue("sequence_id"))
+	if err != nil {
+		slog.Warn("failed to parse sequence id", err.Error(), err)
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "sequence id must be a UUID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	stid, err := uuid.Parse(r.PathValue("step_id"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, problemInvalidParameter, "step id must be a UUID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return seqid, stid, true
+}
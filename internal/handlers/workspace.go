@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type WorkspaceHandler interface {
+	Resolve(next http.Handler) http.Handler
+}
+
+type workspaceHandler struct {
+	defaultWorkspaceID uuid.UUID
+	workspaceService   services.WorkspaceService
+}
+
+var _ WorkspaceHandler = (*workspaceHandler)(nil)
+
+func NewWorkspaceHandler(cfg *config.Config, workspaceService services.WorkspaceService) (*workspaceHandler, error) {
+	defaultWorkspaceID, err := uuid.Parse(cfg.DefaultWorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEFAULT_WORKSPACE_ID: %w", err)
+	}
+
+	return &workspaceHandler{defaultWorkspaceID: defaultWorkspaceID, workspaceService: workspaceService}, nil
+}
+
+// Resolve scopes the request to the workspace of its principal, or to the
+// default workspace when the request is not authenticated. Requests whose
+// workspace does not exist are rejected with 403.
+func (h *workspaceHandler) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaceID := h.defaultWorkspaceID
+		if principal := auth.PrincipalFrom(r.Context()); principal != nil {
+			workspaceID = principal.WorkspaceID
+		}
+
+		workspace, err := h.workspaceService.GetWorkspace(r.Context(), workspaceID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenancy.WithWorkspace(r.Context(), workspace.ID)))
+	})
+}
+
+// workspaceKey namespaces a cache key with the workspace of the request, so
+// a response cached for a workspace is never served to another one. Requests
+// of users are not cached, their role bindings decide what they may read and
+// the key does not hold them, so it returns false for them.
+func workspaceKey(r *http.Request, key string) (string, bool) {
+	if principal := auth.PrincipalFrom(r.Context()); principal != nil && principal.UserID != "" {
+		return "", false
+	}
+
+	return fmt.Sprintf("workspace-%d-%s", tenancy.WorkspaceID(r.Context()), key), true
+}
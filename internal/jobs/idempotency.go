@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+// idempotencyPurgeInterval is how often the expired keys are removed, they are
+// already ignored once expired so this only bounds the size of the table.
+const idempotencyPurgeInterval = time.Hour
+
+// StartIdempotencyKeyPurge periodically removes the expired idempotency keys of
+// every workspace.
+// It blocks until ctx is done.
+func StartIdempotencyKeyPurge(ctx context.Context, workspaceService services.WorkspaceService, idempotencyService services.IdempotencyService) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			forEachWorkspace(ctx, workspaceService, func(ctx context.Context) {
+				purged, err := idempotencyService.PurgeExpiredKeys(ctx)
+				if err != nil {
+					return
+				}
+
+				if purged > 0 {
+					slog.Info("purged expired idempotency keys", "count", purged)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/ratelimit"
+)
+
+// rateLimitPurgeInterval is how often the full buckets are forgotten, they are
+// the same as the missing ones so this only bounds the buckets kept.
+const rateLimitPurgeInterval = 10 * time.Minute
+
+// StartRateLimitPurge periodically removes the full buckets of the limiter.
+// It blocks until ctx is done, and returns right away when the rate limiting
+// is disabled.
+func StartRateLimitPurge(ctx context.Context, cfg *config.Config, limiter ratelimit.Limiter) {
+	if !cfg.RateLimitEnabled {
+		return
+	}
+
+	ticker := time.NewTicker(rateLimitPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := limiter.Purge(ctx)
+			if err != nil {
+				continue
+			}
+
+			if purged > 0 {
+				slog.Info("purged full rate limit buckets", "count", purged)
+			}
+		}
+	}
+}
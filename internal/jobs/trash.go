@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+)
+
+// StartTrashPurge periodically removes the sequences that stayed in the trash
+// for longer than the configured retention, in every workspace. It blocks until
+// ctx is done, and returns right away when the purge interval is not positive.
+func StartTrashPurge(ctx context.Context, cfg *config.Config, workspaceService services.WorkspaceService, sequenceService services.SequenceService) {
+	if cfg.TrashPurgeInterval <= 0 {
+		slog.Warn("trash purge is disabled", "interval", cfg.TrashPurgeInterval)
+		return
+	}
+
+	retention := time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+
+	ticker := time.NewTicker(time.Duration(cfg.TrashPurgeInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			forEachWorkspace(ctx, workspaceService, func(ctx context.Context) {
+				purged, err := sequenceService.PurgeDeletedSequences(ctx, retention)
+				if err != nil {
+					return
+				}
+
+				if purged > 0 {
+					slog.Info("purged deleted sequences", "count", purged)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+// forEachWorkspace runs fn once per workspace with ctx scoped to it, as the
+// queries only see the rows of the workspace of their context.
+func forEachWorkspace(ctx context.Context, workspaceService services.WorkspaceService, fn func(ctx context.Context)) {
+	workspaces, err := workspaceService.GetWorkspaces(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, workspace := range workspaces {
+		fn(tenancy.WithWorkspace(ctx, workspace.ID))
+	}
+}
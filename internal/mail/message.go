@@ -0,0 +1,145 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Message is an email with an HTML body and its plain text alternative.
+type Message struct {
+	From      string
+	To        string
+	Subject   string
+	HTML      string
+	Text      string
+	Date      time.Time
+	MessageID string
+	Headers   map[string]string
+}
+
+// Bytes builds the RFC 5322 message, with a multipart/alternative body where
+// the plain text part comes first so clients prefer the HTML one. When Text is
+// empty it is derived from HTML.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+
+	to, err := netmail.ParseAddressList(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+
+	if m.HTML == "" {
+		return nil, errors.New("html body is required")
+	}
+
+	text := m.Text
+	if text == "" {
+		text = PlainText(m.HTML)
+	}
+
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var buf bytes.Buffer
+
+	body := multipart.NewWriter(&buf)
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from.String())
+	header.Set("To", formatAddressList(to))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", date.Format(time.RFC1123Z))
+	if m.MessageID != "" {
+		header.Set("Message-ID", "<"+m.MessageID+">")
+	}
+	for key, value := range m.Headers {
+		header.Set(key, mime.QEncoding.Encode("utf-8", value))
+	}
+	header.Set("MIME-Version", "1.0")
+	header.Set("Content-Type", "multipart/alternative; boundary="+body.Boundary())
+
+	var msg bytes.Buffer
+	writeHeader(&msg, header)
+
+	if err := writePart(body, "text/plain; charset=utf-8", text); err != nil {
+		return nil, err
+	}
+
+	if err := writePart(body, "text/html; charset=utf-8", m.HTML); err != nil {
+		return nil, err
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	msg.Write(buf.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+func writePart(w *multipart.Writer, contentType string, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+// writeHeader writes the header in a stable order, the standard fields first.
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	order := []string{"From", "To", "Subject", "Date", "Message-Id"}
+
+	written := make(map[string]bool, len(header))
+	for _, key := range order {
+		if values, ok := header[key]; ok {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, values[0])
+			written[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(buf, "%s: %s\r\n", key, header[key][0])
+	}
+
+	buf.WriteString("\r\n")
+}
+
+func formatAddressList(addresses []*netmail.Address) string {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		formatted = append(formatted, address.String())
+	}
+	return strings.Join(formatted, ", ")
+}
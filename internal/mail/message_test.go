@@ -0,0 +1,94 @@
+package mail_test
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"testing"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2025, time.March, 10, 9, 30, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		msg := mail.Message{
+			From:      "Team <team@example.com>",
+			To:        "ana@example.com",
+			Subject:   "Olá Ana",
+			HTML:      `<p>Hi <a href="https://example.com">Ana</a></p>`,
+			Date:      date,
+			MessageID: "step-1@example.com",
+			Headers:   map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+		}
+
+		raw, err := msg.Bytes()
+		assert.NoError(t, err)
+
+		parsed, err := netmail.ReadMessage(bytes.NewReader(raw))
+		assert.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Olá Ana", subject)
+		assert.Equal(t, `"Team" <team@example.com>`, parsed.Header.Get("From"))
+		assert.Equal(t, "<ana@example.com>", parsed.Header.Get("To"))
+		assert.Equal(t, "<step-1@example.com>", parsed.Header.Get("Message-Id"))
+		assert.Equal(t, "<https://example.com/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+		assert.Equal(t, "1.0", parsed.Header.Get("MIME-Version"))
+
+		parsedDate, err := parsed.Header.Date()
+		assert.NoError(t, err)
+		assert.True(t, date.Equal(parsedDate))
+
+		mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+		assert.NoError(t, err)
+		assert.Equal(t, "multipart/alternative", mediaType)
+
+		parts := multipart.NewReader(parsed.Body, params["boundary"])
+
+		text, err := parts.NextPart()
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain; charset=utf-8", text.Header.Get("Content-Type"))
+		content, _ := io.ReadAll(text)
+		assert.Equal(t, "Hi Ana (https://example.com)", string(content))
+
+		html, err := parts.NextPart()
+		assert.NoError(t, err)
+		assert.Equal(t, "text/html; charset=utf-8", html.Header.Get("Content-Type"))
+		content, _ = io.ReadAll(html)
+		assert.Equal(t, msg.HTML, string(content))
+
+		_, err = parts.NextPart()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("success keeping the plain text body", func(t *testing.T) {
+		msg := mail.Message{From: "team@example.com", To: "ana@example.com", Subject: "Hi", HTML: "<p>Hi</p>", Text: "Hello there", Date: date}
+
+		raw, err := msg.Bytes()
+		assert.NoError(t, err)
+		assert.Contains(t, string(raw), "\r\n\r\nHello there\r\n")
+	})
+
+	t.Run("should return error when address is invalid", func(t *testing.T) {
+		msg := mail.Message{From: "team", To: "ana@example.com", HTML: "<p>Hi</p>"}
+
+		_, err := msg.Bytes()
+		assert.ErrorContains(t, err, "invalid from address")
+	})
+
+	t.Run("should return error when html body is empty", func(t *testing.T) {
+		msg := mail.Message{From: "team@example.com", To: "ana@example.com"}
+
+		_, err := msg.Bytes()
+		assert.EqualError(t, err, "html body is required")
+	})
+}
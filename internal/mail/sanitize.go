@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// templateTagRegex matches the personalization tags of the templating package,
+// which must survive the sanitisation untouched. Tags with markup are not valid
+// templates and are sanitised like the rest of the body.
+var templateTagRegex = regexp.MustCompile(`{{[^<>]*?}}`)
+
+var placeholderRegex = regexp.MustCompile(`tmpltag(\d+)x`)
+
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// emails are styled inline and laid out with tables, which the user
+	// generated content policy already allows
+	p.AllowAttrs("style").Globally()
+	p.AllowAttrs("align", "valign", "bgcolor", "width", "height").Globally()
+	p.AllowStyles("color", "background-color", "font-size", "font-weight", "font-family", "text-align", "text-decoration", "padding", "margin", "border", "width", "height").Globally()
+	p.AllowElements("center", "font")
+	p.AllowAttrs("color", "face", "size").OnElements("font")
+	p.RequireNoFollowOnLinks(false)
+
+	return p
+}
+
+// SanitizeHTML removes the scripts, event handlers and any other markup that is
+// unsafe or not supported by mail clients from the body, keeping the template
+// tags as they are.
+func SanitizeHTML(body string) string {
+	tags := make([]string, 0)
+
+	// tags are swapped by placeholders that are valid both as text and urls,
+	// otherwise quotes would be escaped and links with variables dropped
+	protected := templateTagRegex.ReplaceAllStringFunc(body, func(tag string) string {
+		tags = append(tags, tag)
+		return fmt.Sprintf("tmpltag%dx", len(tags)-1)
+	})
+
+	sanitized := policy.Sanitize(protected)
+
+	return placeholderRegex.ReplaceAllStringFunc(sanitized, func(placeholder string) string {
+		i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(placeholder, "tmpltag"), "x"))
+		if err != nil || i >= len(tags) {
+			return placeholder
+		}
+		return tags[i]
+	})
+}
+
+// SanitizeRenderedHTML is SanitizeHTML for a body whose template tags were
+// already rendered, the values of the variables are not trusted either.
+func SanitizeRenderedHTML(body string) string {
+	return policy.Sanitize(body)
+}
@@ -0,0 +1,87 @@
+package mail_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name      string
+		body      string
+		sanitized string
+	}{
+		{
+			name:      "plain text is kept",
+			body:      "Hi Ana, how are you?",
+			sanitized: "Hi Ana, how are you?",
+		},
+		{
+			name:      "scripts and event handlers are removed",
+			body:      `<p onclick="steal()">Hi</p><script>alert(1)</script>`,
+			sanitized: "<p>Hi</p>",
+		},
+		{
+			name:      "unsafe links are removed",
+			body:      `<a href="javascript:alert(1)">Click</a>`,
+			sanitized: "Click",
+		},
+		{
+			name:      "inline styles and table layouts are kept",
+			body:      `<table width="100%"><tr><td align="center" style="color: red; position: fixed">Hi</td></tr></table>`,
+			sanitized: `<table width="100%"><tr><td align="center" style="color: red">Hi</td></tr></table>`,
+		},
+		{
+			name:      "template tags are kept",
+			body:      `<p>Hi {{contact.firstName | default "there"}}</p><a href="https://example.com/{{sequence.product | lower}}">{{#if contact.company}}More{{/if}}</a>`,
+			sanitized: `<p>Hi {{contact.firstName | default "there"}}</p><a href="https://example.com/{{sequence.product | lower}}">{{#if contact.company}}More{{/if}}</a>`,
+		},
+		{
+			name:      "template tags with markup are sanitised",
+			body:      `<p>Hi {{contact.firstName | default "<img src=x onerror=alert(1)>"}}</p>`,
+			sanitized: `<p>Hi {{contact.firstName | default &#34;<img src="x">&#34;}}</p>`,
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.sanitized, mail.SanitizeHTML(tc.body))
+		})
+	}
+}
+
+func TestSanitizeRenderedHTML(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name      string
+		body      string
+		sanitized string
+	}{
+		{
+			name:      "escaped values are kept as text",
+			body:      `<p>Hi &lt;script&gt;alert(1)&lt;/script&gt;</p>`,
+			sanitized: `<p>Hi &lt;script&gt;alert(1)&lt;/script&gt;</p>`,
+		},
+		{
+			name:      "unsafe links are removed",
+			body:      `<a href="javascript:alert(1)">Click</a>`,
+			sanitized: "Click",
+		},
+		{
+			name:      "template tags are not protected",
+			body:      `<p>{{x}}<a href="javascript:alert(1)//{{x}}">Click</a></p>`,
+			sanitized: "<p>{{x}}Click</p>",
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.sanitized, mail.SanitizeRenderedHTML(tc.body))
+		})
+	}
+}
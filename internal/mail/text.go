@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	spacesRegex   = regexp.MustCompile(`[ \t\r\f\v]+`)
+	newLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// blockTags end a line in the plain text version of a body.
+var blockTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// PlainText derives the plain text version of an HTML body, keeping the text,
+// one line per block and the target of the links next to their text.
+func PlainText(body string) string {
+	var sb strings.Builder
+
+	var hrefs []string
+	skip := 0
+
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		token := z.Token()
+
+		switch tt {
+		case html.TextToken:
+			if skip == 0 {
+				sb.WriteString(strings.ReplaceAll(token.Data, "\n", " "))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.Data {
+			case "script", "style", "head":
+				if tt == html.StartTagToken {
+					skip++
+				}
+			case "a":
+				hrefs = append(hrefs, attr(token, "href"))
+			}
+
+			if token.Data == "br" {
+				sb.WriteString("\n")
+			}
+		case html.EndTagToken:
+			switch token.Data {
+			case "script", "style", "head":
+				skip = max(skip-1, 0)
+			case "a":
+				if len(hrefs) > 0 {
+					href := hrefs[len(hrefs)-1]
+					hrefs = hrefs[:len(hrefs)-1]
+					if href != "" {
+						sb.WriteString(" (" + href + ")")
+					}
+				}
+			}
+
+			if blockTags[token.Data] {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	lines := strings.Split(sb.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spacesRegex.ReplaceAllString(line, " "))
+	}
+
+	text := strings.Join(lines, "\n")
+
+	return strings.TrimSpace(newLinesRegex.ReplaceAllString(text, "\n\n"))
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
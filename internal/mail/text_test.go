@@ -0,0 +1,45 @@
+package mail_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		body string
+		text string
+	}{
+		{
+			name: "plain text is kept",
+			body: "Hi Ana, how are you?",
+			text: "Hi Ana, how are you?",
+		},
+		{
+			name: "blocks become lines",
+			body: "<h1>Hi   Ana</h1><p>How are you?</p><p>Bye<br>Team</p>",
+			text: "Hi Ana\nHow are you?\nBye\nTeam",
+		},
+		{
+			name: "links keep their target",
+			body: `<p>See <a href="https://example.com">our site</a></p>`,
+			text: "See our site (https://example.com)",
+		},
+		{
+			name: "styles and scripts are dropped",
+			body: "<html><head><title>x</title><style>p { color: red }</style></head><body><script>alert(1)</script>Hi &amp; bye</body></html>",
+			text: "Hi & bye",
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.text, mail.PlainText(tc.body))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+)
+
+const defaultTrackingBaseURL = "http://localhost:8000"
+
+// TrackedLink is a link of a body and the tracking URL it was replaced by.
+type TrackedLink struct {
+	URL        string
+	TrackedURL string
+}
+
+// Tracker rewrites bodies so opens and clicks go through the tracking
+// endpoints served under baseURL.
+type Tracker struct {
+	baseURL string
+}
+
+func NewTracker(baseURL string) *Tracker {
+	if baseURL == "" {
+		baseURL = defaultTrackingBaseURL
+	}
+
+	return &Tracker{baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// ClickURL is the URL that records the click on target before redirecting to it.
+func (t *Tracker) ClickURL(stepID uuid.UUID, target string) string {
+	query := url.Values{"step": {stepID.String()}, "url": {target}}
+	return t.baseURL + "/track/click?" + query.Encode()
+}
+
+// OpenURL is the URL of the pixel that records the opening of the email.
+func (t *Tracker) OpenURL(stepID uuid.UUID) string {
+	query := url.Values{"step": {stepID.String()}}
+	return t.baseURL + "/track/open?" + query.Encode()
+}
+
+// TrackLinks replaces the http(s) links of the body with their click tracking
+// URL, leaving the rest of the body untouched.
+func (t *Tracker) TrackLinks(body string, stepID uuid.UUID) (string, []TrackedLink) {
+	var sb strings.Builder
+
+	links := make([]TrackedLink, 0)
+
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		if tt != html.StartTagToken {
+			sb.Write(z.Raw())
+			continue
+		}
+
+		raw := string(z.Raw())
+		token := z.Token()
+
+		href := attr(token, "href")
+		if token.Data != "a" || !isTrackable(href) {
+			sb.WriteString(raw)
+			continue
+		}
+
+		tracked := t.ClickURL(stepID, href)
+		links = append(links, TrackedLink{URL: href, TrackedURL: tracked})
+
+		for i := range token.Attr {
+			if token.Attr[i].Key == "href" {
+				token.Attr[i].Val = tracked
+			}
+		}
+
+		sb.WriteString(token.String())
+	}
+
+	return sb.String(), links
+}
+
+// AddOpenPixel appends the open tracking pixel to the body, inside the body
+// tag when there is one.
+func (t *Tracker) AddOpenPixel(body string, stepID uuid.UUID) string {
+	pixel := `<img src="` + html.EscapeString(t.OpenURL(stepID)) + `" width="1" height="1" alt="" style="display:none">`
+
+	if i := strings.LastIndex(strings.ToLower(body), "</body>"); i >= 0 {
+		return body[:i] + pixel + body[i:]
+	}
+
+	return body + pixel
+}
+
+func isTrackable(href string) bool {
+	u, err := url.Parse(href)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,48 @@
+package mail_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_TrackLinks(t *testing.T) {
+	t.Parallel()
+
+	tracker := mail.NewTracker("https://mail.example.com/")
+	stepID := uuid.MustParse("1e8126af-35dc-4ba7-9e8b-bb9b5902ba82")
+
+	t.Run("success", func(t *testing.T) {
+		body, links := tracker.TrackLinks(`<p class="x">Visit <a href="https://example.com/a?b=1" class="link">us</a> or <a href="mailto:team@example.com">mail</a></p>`, stepID)
+
+		tracked := "https://mail.example.com/track/click?step=1e8126af-35dc-4ba7-9e8b-bb9b5902ba82&url=https%3A%2F%2Fexample.com%2Fa%3Fb%3D1"
+
+		assert.Equal(t, []mail.TrackedLink{{URL: "https://example.com/a?b=1", TrackedURL: tracked}}, links)
+		assert.Equal(t, `<p class="x">Visit <a href="https://mail.example.com/track/click?step=1e8126af-35dc-4ba7-9e8b-bb9b5902ba82&amp;url=https%3A%2F%2Fexample.com%2Fa%3Fb%3D1" class="link">us</a> or <a href="mailto:team@example.com">mail</a></p>`, body)
+	})
+
+	t.Run("success without links", func(t *testing.T) {
+		body, links := tracker.TrackLinks("Hi Ana", stepID)
+		assert.Equal(t, "Hi Ana", body)
+		assert.Empty(t, links)
+	})
+}
+
+func TestTracker_AddOpenPixel(t *testing.T) {
+	t.Parallel()
+
+	tracker := mail.NewTracker("")
+	stepID := uuid.MustParse("1e8126af-35dc-4ba7-9e8b-bb9b5902ba82")
+
+	pixel := `<img src="http://localhost:8000/track/open?step=1e8126af-35dc-4ba7-9e8b-bb9b5902ba82" width="1" height="1" alt="" style="display:none">`
+
+	t.Run("success", func(t *testing.T) {
+		assert.Equal(t, "<p>Hi</p>"+pixel, tracker.AddOpenPixel("<p>Hi</p>", stepID))
+	})
+
+	t.Run("success inside the body tag", func(t *testing.T) {
+		assert.Equal(t, "<html><body>Hi"+pixel+"</body></html>", tracker.AddOpenPixel("<html><body>Hi</body></html>", stepID))
+	})
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiKey authenticates the requests of a workspace. Only the hash of its
+// secret is stored, Prefix is how the key is found.
+type ApiKey struct {
+	ID                  int32
+	ExternalID          uuid.UUID
+	WorkspaceID         int32
+	WorkspaceExternalID uuid.UUID
+	Name                string
+	Prefix              string
+	Hash                string
+	Scopes              []string
+	Created             time.Time
+	LastUsed            *time.Time
+	Revoked             *time.Time
+}
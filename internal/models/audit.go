@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent records a change made to a sequence or a step. Before and After
+// hold the target as returned by the API before and after the change, each one
+// is nil when the target did not exist at that point.
+type AuditEvent struct {
+	ID         int32
+	ExternalID uuid.UUID
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestID  *string
+	Created    time.Time
+}
+
+// AuditEventFilter narrows the audit events down, nil fields match every event.
+type AuditEventFilter struct {
+	Actor         *string
+	Action        *string
+	TargetType    *string
+	TargetID      *uuid.UUID
+	RequestID     *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyKey is a request sent with an Idempotency-Key header and, once it
+// finished, its response. StatusCode is 0 while the request is still running.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	StatusCode  int32
+	Header      http.Header
+	Body        []byte
+	Created     time.Time
+}
+
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SequenceRevision struct {
+	Revision int32
+	Created  time.Time
+	Snapshot SequenceSnapshot
+}
+
+// SequenceSnapshot is the content of a sequence at a given revision, it is
+// stored as JSON so its tags must stay backwards compatible.
+type SequenceSnapshot struct {
+	Name                 string            `json:"name"`
+	OpenTrackingEnabled  bool              `json:"openTrackingEnabled"`
+	ClickTrackingEnabled bool              `json:"clickTrackingEnabled"`
+	Variables            map[string]string `json:"variables,omitempty"`
+	Deleted              bool              `json:"deleted,omitempty"`
+	Steps                []*StepSnapshot   `json:"steps"`
+}
+
+type StepSnapshot struct {
+	ExternalID       uuid.UUID          `json:"id"`
+	StepNumber       int32              `json:"stepNumber"`
+	MailSubject      string             `json:"mailSubject"`
+	MailContent      string             `json:"mailContent"`
+	MailText         string             `json:"mailText,omitempty"`
+	DelayDays        int32              `json:"delayDays"`
+	DelayHours       int32              `json:"delayHours"`
+	BusinessDaysOnly bool               `json:"businessDaysOnly"`
+	SendWindowStart  *int32             `json:"sendWindowStart,omitempty"`
+	SendWindowEnd    *int32             `json:"sendWindowEnd,omitempty"`
+	Variants         []*VariantSnapshot `json:"variants,omitempty"`
+}
+
+type VariantSnapshot struct {
+	ExternalID  uuid.UUID `json:"id"`
+	MailSubject string    `json:"mailSubject"`
+	MailContent string    `json:"mailContent"`
+	Weight      int32     `json:"weight"`
+}
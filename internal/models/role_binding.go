@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is what a user is allowed to do in a workspace or a sequence, each role
+// allowing everything the roles before it do.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// Valid tells whether r is a known role.
+func (r Role) Valid() bool {
+	return roleRanks[r] > 0
+}
+
+// Includes tells whether r allows everything other does.
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[other]
+}
+
+// RoleBinding grants a role to a user, in the whole workspace or, when it has
+// a sequence, in that sequence only.
+type RoleBinding struct {
+	ID                 int32
+	ExternalID         uuid.UUID
+	UserID             string
+	Role               Role
+	SequenceID         *int32
+	SequenceExternalID *uuid.UUID
+	Created            time.Time
+}
@@ -1,12 +1,36 @@
 package models
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 )
 
+const (
+	SortByName      = "name"
+	SortByCreated   = "created"
+	SortByUpdated   = "updated"
+	SortByStepCount = "stepCount"
+)
+
+// Statuses of the sequence lifecycle, the allowed transitions between them are
+// enforced by the sequence service.
+const (
+	StatusDraft    = "draft"
+	StatusActive   = "active"
+	StatusPaused   = "paused"
+	StatusArchived = "archived"
+)
+
+// IsEditableStatus reports whether the content of a sequence with the status
+// can be changed, active sequences must be paused first and archived ones are
+// read-only.
+func IsEditableStatus(status string) bool {
+	return status == StatusDraft || status == StatusPaused
+}
+
 type SequenceWithSteps struct {
 	ID                   int32
 	ExternalID           uuid.UUID
@@ -15,5 +39,50 @@ type SequenceWithSteps struct {
 	ClickTrackingEnabled bool
 	Created              time.Time
 	Updated              *time.Time
+	Deleted              *time.Time
+	Variables            map[string]string
+	Status               string
+	Version              int32
+	SourceID             *uuid.UUID
 	Steps                []*dao.Step
+	// Variants, when not nil, are the variants the steps are left with on a
+	// replace, by the external id of their step.
+	Variants map[uuid.UUID][]*StepVariant
+}
+
+// SortValue formats the key the sequence is sorted by when listing with sortBy,
+// so it can be carried in a page cursor.
+func (s *SequenceWithSteps) SortValue(sortBy string) string {
+	switch sortBy {
+	case SortByName:
+		return s.Name
+	case SortByUpdated:
+		if s.Updated != nil {
+			return s.Updated.Format(time.RFC3339Nano)
+		}
+		return s.Created.Format(time.RFC3339Nano)
+	case SortByStepCount:
+		count := 0
+		for _, step := range s.Steps {
+			if step != nil {
+				count++
+			}
+		}
+		return strconv.Itoa(count)
+	default:
+		return s.Created.Format(time.RFC3339Nano)
+	}
+}
+
+type SequenceFilter struct {
+	Name                 *string
+	OpenTrackingEnabled  *bool
+	ClickTrackingEnabled *bool
+	CreatedAfter         *time.Time
+	CreatedBefore        *time.Time
+	UpdatedAfter         *time.Time
+	UpdatedBefore        *time.Time
+	Search               *string
+	SortBy               string
+	Descending           bool
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StepVariant is an alternative subject and content of a step, picked for a
+// share of the enrollments proportional to its weight.
+type StepVariant struct {
+	ID          int32
+	ExternalID  uuid.UUID
+	StepID      int32
+	MailSubject string
+	MailContent string
+	Weight      int32
+	Created     time.Time
+	Assignments int64
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace isolates the data of a tenant, every sequence belongs to one.
+type Workspace struct {
+	ID         int32
+	ExternalID uuid.UUID
+	Name       string
+	Created    time.Time
+}
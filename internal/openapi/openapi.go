@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec is the OpenAPI 3.1 document of the API, served at /openapi.json. It is
+// written by hand, so every change to the routes or to the request and
+// response bodies must be reflected here.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// Docs is the documentation page served at /docs, which renders Spec.
+//
+//go:embed docs.html
+var Docs []byte
+
+// Load parses and validates Spec.
+func Load(ctx context.Context) (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[*openapi3.Schema]bool)
+	for _, ref := range doc.Components.Schemas {
+		nullableTypes(ref.Value, seen)
+	}
+
+	if err := doc.Validate(ctx); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// nullableTypes rewrites the null types of OpenAPI 3.1, e.g. ["string",
+// "null"], as the nullable flag of OpenAPI 3.0, which is what kin-openapi
+// validates documents against.
+func nullableTypes(schema *openapi3.Schema, seen map[*openapi3.Schema]bool) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+
+	if schema.Type.Includes(openapi3.TypeNull) {
+		types := openapi3.Types(slices.DeleteFunc(slices.Clone(schema.Type.Slice()), func(t string) bool {
+			return t == openapi3.TypeNull
+		}))
+		schema.Type = &types
+		schema.Nullable = true
+	}
+
+	for _, ref := range schema.Properties {
+		nullableTypes(ref.Value, seen)
+	}
+
+	if schema.Items != nil {
+		nullableTypes(schema.Items.Value, seen)
+	}
+
+	if schema.AdditionalProperties.Schema != nil {
+		nullableTypes(schema.AdditionalProperties.Schema.Value, seen)
+	}
+
+	for _, refs := range []openapi3.SchemaRefs{schema.OneOf, schema.AnyOf, schema.AllOf} {
+		for _, ref := range refs {
+			nullableTypes(ref.Value, seen)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastUpdatedAt := "2025-01-02T10:00:00Z"
+	sequenceID := "5c1d2e3f-4a5b-6c7d-8e9f-0a1b2c3d4e5f"
+
+	step := &dto.StepResponse{
+		ExternalID:  "0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f",
+		StepNumber:  1,
+		MailSubject: "Hi {{contact.firstName}}",
+		MailContent: "<p>content</p>",
+		MailHTML:    "<p>content</p>",
+		MailText:    "content",
+		SendWindow:  &dto.SendWindow{Start: "09:00", End: "17:00"},
+		Variables:   []string{"contact.firstName"},
+		Version:     1,
+	}
+
+	responses := map[string]any{
+		"StepResponse": step,
+		"SequenceResponse": &dto.SequenceResponse{
+			ExternalID:    "5c1d2e3f-4a5b-6c7d-8e9f-0a1b2c3d4e5f",
+			Name:          "My Sequence",
+			Variables:     map[string]string{"product": "Sequences"},
+			Status:        "draft",
+			Version:       2,
+			Steps:         []*dto.StepResponse{step},
+			CreatedAt:     "2025-01-01T10:00:00Z",
+			LastUpdatedAt: &lastUpdatedAt,
+		},
+		"VariantResponse": &dto.VariantResponse{
+			ExternalID:  "0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f",
+			MailSubject: "Hi",
+			MailContent: "content",
+			Weight:      1,
+			Variables:   []string{},
+			CreatedAt:   "2025-01-01T10:00:00Z",
+		},
+		"ApiKeyResponse": &dto.ApiKeyResponse{
+			ExternalID: "0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f",
+			Name:       "ci",
+			Prefix:     "3f9a1c2b7d4e",
+			Scopes:     []string{"sequences:read"},
+			Key:        "sk_3f9a1c2b7d4e_secret",
+			CreatedAt:  "2025-01-01T10:00:00Z",
+		},
+		"RoleBindingResponse": &dto.RoleBindingResponse{
+			ExternalID: "0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f",
+			UserID:     "user-1",
+			Role:       "editor",
+			SequenceID: &sequenceID,
+			CreatedAt:  "2025-01-01T10:00:00Z",
+		},
+		"AuditEventResponse": &dto.AuditEventResponse{
+			ExternalID: "0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f",
+			Actor:      "api-key:1",
+			Action:     "update",
+			TargetType: "sequence",
+			TargetID:   sequenceID,
+			Before:     json.RawMessage(`{"name":"My Sequence"}`),
+			After:      json.RawMessage(`{"name":"Renamed"}`),
+			CreatedAt:  "2025-01-01T10:00:00Z",
+		},
+		"Problem": &dto.Problem{
+			Type:   "/problems/validation-error",
+			Title:  "Bad Request",
+			Status: 400,
+			Errors: []*dto.FieldError{{Pointer: "/name", Detail: "sequence name is required"}},
+		},
+	}
+
+	for name, response := range responses {
+		t.Run("should match the "+name+" schema", func(t *testing.T) {
+			assertMatchesSchema(t, doc, name, response)
+		})
+	}
+
+	stepNumber, subject, weight := 2, "Hi", 2
+	enrollmentID := uuid.MustParse("0f5bc0cb-8b3e-4a8c-9d4f-1a2b3c4d5e6f")
+	stepID := uuid.MustParse("5c1d2e3f-4a5b-6c7d-8e9f-0a1b2c3d4e5f")
+
+	createStep := &dto.CreateStepRequest{
+		StepNumber:  1,
+		MailSubject: "Hi {{contact.firstName}}",
+		MailHTML:    "<p>content</p>",
+		SendWindow:  &dto.SendWindow{Start: "09:00", End: "17:00"},
+	}
+
+	// the clients written in Go send the request bodies as they marshal, so
+	// they must match the document with and without their optional fields
+	requests := map[string][]any{
+		"CreateSequenceRequest": {
+			&dto.CreateSequenceRequest{Name: "My Sequence", Variables: map[string]string{"product": "Sequences"}, Steps: []*dto.CreateStepRequest{createStep}},
+			&dto.CreateSequenceRequest{Name: "My Sequence", Steps: []*dto.CreateStepRequest{{StepNumber: 1, MailSubject: "Hi"}}},
+		},
+		"ReplaceSequenceRequest": {
+			&dto.ReplaceSequenceRequest{Name: "My Sequence", Steps: []*dto.ReplaceStepRequest{{ExternalID: &stepID, CreateStepRequest: *createStep}}},
+			&dto.ReplaceSequenceRequest{Name: "My Sequence", Steps: []*dto.ReplaceStepRequest{{CreateStepRequest: dto.CreateStepRequest{StepNumber: 1, MailSubject: "Hi"}}}},
+		},
+		"UpdateSequenceRequest": {
+			&dto.UpdateSequenceRequest{Name: &subject, Variables: map[string]string{"product": "Sequences"}},
+			&dto.UpdateSequenceRequest{},
+		},
+		"CloneSequenceRequest": {
+			&dto.CloneSequenceRequest{Name: &subject},
+			&dto.CloneSequenceRequest{},
+		},
+		"CreateStepRequest": {createStep},
+		"UpdateStepRequest": {
+			&dto.UpdateStepRequest{StepNumber: &stepNumber, MailSubject: &subject, SendWindow: &dto.SendWindow{Start: "09:00", End: "17:00"}},
+			&dto.UpdateStepRequest{},
+		},
+		"ReorderStepsRequest": {&dto.ReorderStepsRequest{StepIDs: []uuid.UUID{stepID}}},
+		"PreviewStepRequest": {
+			&dto.PreviewStepRequest{Contact: map[string]string{"firstName": "Ana"}, Strict: true},
+			&dto.PreviewStepRequest{},
+		},
+		"CreateVariantRequest": {&dto.CreateVariantRequest{MailSubject: "Hi", MailContent: "content", Weight: 1}},
+		"UpdateVariantRequest": {
+			&dto.UpdateVariantRequest{Weight: &weight},
+			&dto.UpdateVariantRequest{},
+		},
+		"AssignVariantRequest": {&dto.AssignVariantRequest{EnrollmentID: enrollmentID}},
+		"CreateApiKeyRequest":  {&dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{"sequences:read"}}},
+		"CreateRoleBindingRequest": {
+			&dto.CreateRoleBindingRequest{UserID: "user-1", Role: "editor", SequenceID: &stepID},
+			&dto.CreateRoleBindingRequest{UserID: "user-1", Role: "editor"},
+		},
+	}
+
+	for name, bodies := range requests {
+		t.Run("should match the "+name+" schema", func(t *testing.T) {
+			for _, body := range bodies {
+				assertMatchesSchema(t, doc, name, body)
+			}
+		})
+	}
+}
+
+func assertMatchesSchema(t *testing.T, doc *openapi3.T, name string, body any) {
+	t.Helper()
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, doc.Components.Schemas[name].Value.VisitJSON(value), string(raw))
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, model *models.ApiKey) error
+	FindByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error)
+	FindAll(ctx context.Context) ([]*models.ApiKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Rotate(ctx context.Context, model *models.ApiKey) error
+	Touch(ctx context.Context, id int32, usedBefore time.Time) error
+}
+
+type apiKeyRepository struct {
+	queries *dao.Queries
+}
+
+var _ ApiKeyRepository = (*apiKeyRepository)(nil)
+
+func NewApiKeyRepository(db db.DB) *apiKeyRepository {
+	return &apiKeyRepository{queries: db.Queries()}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, model *models.ApiKey) error {
+	row, err := r.queries.CreateApiKey(ctx, dao.CreateApiKeyParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		KeyName:     model.Name,
+		KeyPrefix:   model.Prefix,
+		KeyHash:     model.Hash,
+		Scopes:      model.Scopes,
+	})
+	if err != nil {
+		return err
+	}
+
+	model.ID = row.ID
+	model.ExternalID = row.ExternalID
+	model.WorkspaceID = row.WorkspaceID
+	model.Created = row.Created.Time
+
+	return nil
+}
+
+// FindByPrefix returns the key that was not revoked with the prefix, in any
+// workspace, since it is how the workspace of the request is found.
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error) {
+	row, err := r.queries.GetApiKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	model := toApiKey(dao.ApiKey{
+		ID:          row.ID,
+		ExternalID:  row.ExternalID,
+		WorkspaceID: row.WorkspaceID,
+		KeyName:     row.KeyName,
+		KeyPrefix:   row.KeyPrefix,
+		KeyHash:     row.KeyHash,
+		Scopes:      row.Scopes,
+		Created:     row.Created,
+		LastUsedAt:  row.LastUsedAt,
+		RevokedAt:   row.RevokedAt,
+	})
+	model.WorkspaceExternalID = row.WorkspaceExternalID
+
+	return model, nil
+}
+
+func (r *apiKeyRepository) FindAll(ctx context.Context) ([]*models.ApiKey, error) {
+	rows, err := r.queries.GetApiKeys(ctx, tenancy.WorkspaceID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.ApiKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, toApiKey(row))
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	_, err := r.queries.RevokeApiKey(ctx, dao.RevokeApiKeyParams{
+		ExternalID:  id,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	return err
+}
+
+// Rotate replaces the prefix and hash of the key with the ones of model,
+// refreshing the rest of model with the stored key.
+func (r *apiKeyRepository) Rotate(ctx context.Context, model *models.ApiKey) error {
+	row, err := r.queries.RotateApiKey(ctx, dao.RotateApiKeyParams{
+		ExternalID:  model.ExternalID,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		KeyPrefix:   model.Prefix,
+		KeyHash:     model.Hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	*model = *toApiKey(row)
+
+	return nil
+}
+
+// Touch records that the key was used now, unless it was already used after
+// usedBefore, sparing a write on every request.
+func (r *apiKeyRepository) Touch(ctx context.Context, id int32, usedBefore time.Time) error {
+	return r.queries.TouchApiKey(ctx, dao.TouchApiKeyParams{
+		ID:         id,
+		LastUsedAt: timestamp(&usedBefore),
+	})
+}
+
+func toApiKey(row dao.ApiKey) *models.ApiKey {
+	model := &models.ApiKey{
+		ID:          row.ID,
+		ExternalID:  row.ExternalID,
+		WorkspaceID: row.WorkspaceID,
+		Name:        row.KeyName,
+		Prefix:      row.KeyPrefix,
+		Hash:        row.KeyHash,
+		Scopes:      row.Scopes,
+		Created:     row.Created.Time,
+	}
+
+	if row.LastUsedAt.Valid {
+		model.LastUsed = &row.LastUsedAt.Time
+	}
+
+	if row.RevokedAt.Valid {
+		model.Revoked = &row.RevokedAt.Time
+	}
+
+	return model
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+)
+
+type AuditRepository interface {
+	Append(ctx context.Context, model *models.AuditEvent) error
+	FindPage(ctx context.Context, filter models.AuditEventFilter, cursor *utils.Cursor, limit int) ([]*models.AuditEvent, error)
+}
+
+type auditRepository struct {
+	queries *dao.Queries
+}
+
+var _ AuditRepository = (*auditRepository)(nil)
+
+func NewAuditRepository(db db.DB) *auditRepository {
+	return &auditRepository{queries: db.Queries()}
+}
+
+// Append stores the event, in the transaction of ctx when there is one.
+func (r *auditRepository) Append(ctx context.Context, model *models.AuditEvent) error {
+	row, err := r.queries.CreateAuditEvent(ctx, dao.CreateAuditEventParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		Actor:       model.Actor,
+		Action:      model.Action,
+		TargetType:  model.TargetType,
+		TargetID:    model.TargetID,
+		Before:      model.Before,
+		After:       model.After,
+		RequestID:   model.RequestID,
+	})
+	if err != nil {
+		return err
+	}
+
+	model.ID = row.ID
+	model.ExternalID = row.ExternalID
+	model.Created = row.Created.Time
+
+	return nil
+}
+
+// FindPage returns up to limit events matching the filter, newest first,
+// starting right after the event of the cursor.
+func (r *auditRepository) FindPage(ctx context.Context, filter models.AuditEventFilter, cursor *utils.Cursor, limit int) ([]*models.AuditEvent, error) {
+	params := dao.GetAuditEventsPageParams{
+		WorkspaceID:   tenancy.WorkspaceID(ctx),
+		Actor:         filter.Actor,
+		Action:        filter.Action,
+		TargetType:    filter.TargetType,
+		TargetID:      filter.TargetID,
+		RequestID:     filter.RequestID,
+		CreatedAfter:  timestamp(filter.CreatedAfter),
+		CreatedBefore: timestamp(filter.CreatedBefore),
+		PageLimit:     int32(limit),
+	}
+
+	if cursor != nil {
+		params.CursorID = &cursor.ID
+	}
+
+	rows, err := r.queries.GetAuditEventsPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*models.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, &models.AuditEvent{
+			ID:         row.ID,
+			ExternalID: row.ExternalID,
+			Actor:      row.Actor,
+			Action:     row.Action,
+			TargetType: row.TargetType,
+			TargetID:   row.TargetID,
+			Before:     row.Before,
+			After:      row.After,
+			RequestID:  row.RequestID,
+			Created:    row.Created.Time,
+		})
+	}
+
+	return events, nil
+}
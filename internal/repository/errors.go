@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+var (
+	ErrStepNumberTaken   = errors.New("step number is already taken")
+	ErrStepOrderMismatch = errors.New("step order does not match the sequence steps")
+	ErrSequenceReadOnly  = errors.New("sequence status does not allow changes")
+	ErrVersionConflict   = errors.New("version does not match the stored one")
+)
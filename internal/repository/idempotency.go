@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, key string, requestHash string, expiredBefore time.Time) (bool, error)
+	FindOne(ctx context.Context, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, model *models.IdempotencyKey) error
+	Delete(ctx context.Context, key string) error
+	Purge(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	queries *dao.Queries
+}
+
+var _ IdempotencyRepository = (*idempotencyRepository)(nil)
+
+func NewIdempotencyRepository(db db.DB) *idempotencyRepository {
+	return &idempotencyRepository{queries: db.Queries()}
+}
+
+// Reserve takes the key for a new request, which also happens when the key was
+// created before expiredBefore. It returns false when the key is taken.
+func (r *idempotencyRepository) Reserve(ctx context.Context, key string, requestHash string, expiredBefore time.Time) (bool, error) {
+	_, err := r.queries.ReserveIdempotencyKey(ctx, dao.ReserveIdempotencyKeyParams{
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		WorkspaceID:    tenancy.WorkspaceID(ctx),
+		ExpiredBefore:  timestamp(&expiredBefore),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *idempotencyRepository) FindOne(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	row, err := r.queries.GetIdempotencyKey(ctx, dao.GetIdempotencyKeyParams{
+		IdempotencyKey: key,
+		WorkspaceID:    tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	model := &models.IdempotencyKey{
+		Key:         row.IdempotencyKey,
+		RequestHash: row.RequestHash,
+		Body:        row.ResponseBody,
+		Created:     row.Created.Time,
+	}
+
+	if row.StatusCode != nil {
+		model.StatusCode = *row.StatusCode
+	}
+
+	if row.ResponseHeaders != nil {
+		if err := json.Unmarshal(row.ResponseHeaders, &model.Header); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+// Complete stores the response of the request holding the key.
+func (r *idempotencyRepository) Complete(ctx context.Context, model *models.IdempotencyKey) error {
+	header, err := json.Marshal(model.Header)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.CompleteIdempotencyKey(ctx, dao.CompleteIdempotencyKeyParams{
+		IdempotencyKey:  model.Key,
+		StatusCode:      &model.StatusCode,
+		ResponseHeaders: header,
+		ResponseBody:    model.Body,
+		WorkspaceID:     tenancy.WorkspaceID(ctx),
+	})
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, key string) error {
+	return r.queries.DeleteIdempotencyKey(ctx, dao.DeleteIdempotencyKeyParams{
+		IdempotencyKey: key,
+		WorkspaceID:    tenancy.WorkspaceID(ctx),
+	})
+}
+
+func (r *idempotencyRepository) Purge(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return r.queries.PurgeIdempotencyKeys(ctx, dao.PurgeIdempotencyKeysParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		Created:     timestamp(&createdBefore),
+	})
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/api_key.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/api_key.go -destination=internal/repository/mocks/api_key.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	models "github.com/murilo-bracero/sequence-technical-test/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockApiKeyRepository is a mock of ApiKeyRepository interface.
+type MockApiKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockApiKeyRepositoryMockRecorder is the mock recorder for MockApiKeyRepository.
+type MockApiKeyRepositoryMockRecorder struct {
+	mock *MockApiKeyRepository
+}
+
+// NewMockApiKeyRepository creates a new mock instance.
+func NewMockApiKeyRepository(ctrl *gomock.Controller) *MockApiKeyRepository {
+	mock := &MockApiKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockApiKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyRepository) EXPECT() *MockApiKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockApiKeyRepository) Create(ctx context.Context, model *models.ApiKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockApiKeyRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApiKeyRepository)(nil).Create), ctx, model)
+}
+
+// FindAll mocks base method.
+func (m *MockApiKeyRepository) FindAll(ctx context.Context) ([]*models.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*models.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockApiKeyRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockApiKeyRepository)(nil).FindAll), ctx)
+}
+
+// FindByPrefix mocks base method.
+func (m *MockApiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(*models.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPrefix indicates an expected call of FindByPrefix.
+func (mr *MockApiKeyRepositoryMockRecorder) FindByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPrefix", reflect.TypeOf((*MockApiKeyRepository)(nil).FindByPrefix), ctx, prefix)
+}
+
+// Revoke mocks base method.
+func (m *MockApiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockApiKeyRepositoryMockRecorder) Revoke(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockApiKeyRepository)(nil).Revoke), ctx, id)
+}
+
+// Rotate mocks base method.
+func (m *MockApiKeyRepository) Rotate(ctx context.Context, model *models.ApiKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockApiKeyRepositoryMockRecorder) Rotate(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockApiKeyRepository)(nil).Rotate), ctx, model)
+}
+
+// Touch mocks base method.
+func (m *MockApiKeyRepository) Touch(ctx context.Context, id int32, usedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, id, usedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockApiKeyRepositoryMockRecorder) Touch(ctx, id, usedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockApiKeyRepository)(nil).Touch), ctx, id, usedBefore)
+}
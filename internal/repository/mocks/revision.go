@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/revision.go -destination=internal/repository/mocks/revision.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/murilo-bracero/sequence-technical-test/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRevisionRepository is a mock of RevisionRepository interface.
+type MockRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRevisionRepositoryMockRecorder is the mock recorder for MockRevisionRepository.
+type MockRevisionRepositoryMockRecorder struct {
+	mock *MockRevisionRepository
+}
+
+// NewMockRevisionRepository creates a new mock instance.
+func NewMockRevisionRepository(ctrl *gomock.Controller) *MockRevisionRepository {
+	mock := &MockRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionRepository) EXPECT() *MockRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockRevisionRepository) FindAll(ctx context.Context, sequenceID uuid.UUID, limit, offset int) ([]*models.SequenceRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, sequenceID, limit, offset)
+	ret0, _ := ret[0].([]*models.SequenceRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockRevisionRepositoryMockRecorder) FindAll(ctx, sequenceID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRevisionRepository)(nil).FindAll), ctx, sequenceID, limit, offset)
+}
+
+// FindOne mocks base method.
+func (m *MockRevisionRepository) FindOne(ctx context.Context, sequenceID uuid.UUID, revision int32) (*models.SequenceRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOne", ctx, sequenceID, revision)
+	ret0, _ := ret[0].(*models.SequenceRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne.
+func (mr *MockRevisionRepositoryMockRecorder) FindOne(ctx, sequenceID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockRevisionRepository)(nil).FindOne), ctx, sequenceID, revision)
+}
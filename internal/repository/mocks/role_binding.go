@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/role_binding.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/role_binding.go -destination=internal/repository/mocks/role_binding.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/murilo-bracero/sequence-technical-test/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleBindingRepository is a mock of RoleBindingRepository interface.
+type MockRoleBindingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleBindingRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleBindingRepositoryMockRecorder is the mock recorder for MockRoleBindingRepository.
+type MockRoleBindingRepositoryMockRecorder struct {
+	mock *MockRoleBindingRepository
+}
+
+// NewMockRoleBindingRepository creates a new mock instance.
+func NewMockRoleBindingRepository(ctrl *gomock.Controller) *MockRoleBindingRepository {
+	mock := &MockRoleBindingRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleBindingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleBindingRepository) EXPECT() *MockRoleBindingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockRoleBindingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoleBindingRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoleBindingRepository)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockRoleBindingRepository) FindAll(ctx context.Context) ([]*models.RoleBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]*models.RoleBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockRoleBindingRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRoleBindingRepository)(nil).FindAll), ctx)
+}
+
+// FindRoles mocks base method.
+func (m *MockRoleBindingRepository) FindRoles(ctx context.Context, userID string, sequenceID, stepID *uuid.UUID) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoles", ctx, userID, sequenceID, stepID)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoles indicates an expected call of FindRoles.
+func (mr *MockRoleBindingRepositoryMockRecorder) FindRoles(ctx, userID, sequenceID, stepID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoles", reflect.TypeOf((*MockRoleBindingRepository)(nil).FindRoles), ctx, userID, sequenceID, stepID)
+}
+
+// Save mocks base method.
+func (m *MockRoleBindingRepository) Save(ctx context.Context, model *models.RoleBinding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRoleBindingRepositoryMockRecorder) Save(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRoleBindingRepository)(nil).Save), ctx, model)
+}
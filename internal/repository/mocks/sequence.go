@@ -12,9 +12,11 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	models "github.com/murilo-bracero/sequence-technical-test/internal/models"
+	utils "github.com/murilo-bracero/sequence-technical-test/internal/utils"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +44,35 @@ func (m *MockSequenceRepository) EXPECT() *MockSequenceRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Clone mocks base method.
+func (m *MockSequenceRepository) Clone(ctx context.Context, model *models.SequenceWithSteps) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clone", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clone indicates an expected call of Clone.
+func (mr *MockSequenceRepositoryMockRecorder) Clone(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockSequenceRepository)(nil).Clone), ctx, model)
+}
+
+// Count mocks base method.
+func (m *MockSequenceRepository) Count(ctx context.Context, filter models.SequenceFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockSequenceRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockSequenceRepository)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockSequenceRepository) Create(ctx context.Context, model *models.SequenceWithSteps) error {
 	m.ctrl.T.Helper()
@@ -57,17 +88,17 @@ func (mr *MockSequenceRepositoryMockRecorder) Create(ctx, model any) *gomock.Cal
 }
 
 // Delete mocks base method.
-func (m *MockSequenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *MockSequenceRepository) Delete(ctx context.Context, id uuid.UUID, version int32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockSequenceRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockSequenceRepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSequenceRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSequenceRepository)(nil).Delete), ctx, id, version)
 }
 
 // FindAll mocks base method.
@@ -85,6 +116,21 @@ func (mr *MockSequenceRepositoryMockRecorder) FindAll(ctx, limit, offset any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockSequenceRepository)(nil).FindAll), ctx, limit, offset)
 }
 
+// FindAllDeleted mocks base method.
+func (m *MockSequenceRepository) FindAllDeleted(ctx context.Context, limit, offset int) ([]*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllDeleted", ctx, limit, offset)
+	ret0, _ := ret[0].([]*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllDeleted indicates an expected call of FindAllDeleted.
+func (mr *MockSequenceRepositoryMockRecorder) FindAllDeleted(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllDeleted", reflect.TypeOf((*MockSequenceRepository)(nil).FindAllDeleted), ctx, limit, offset)
+}
+
 // FindByExternalId mocks base method.
 func (m *MockSequenceRepository) FindByExternalId(ctx context.Context, id uuid.UUID) (*models.SequenceWithSteps, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +146,137 @@ func (mr *MockSequenceRepositoryMockRecorder) FindByExternalId(ctx, id any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByExternalId", reflect.TypeOf((*MockSequenceRepository)(nil).FindByExternalId), ctx, id)
 }
 
+// FindByExternalIds mocks base method.
+func (m *MockSequenceRepository) FindByExternalIds(ctx context.Context, ids []uuid.UUID) ([]*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByExternalIds", ctx, ids)
+	ret0, _ := ret[0].([]*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByExternalIds indicates an expected call of FindByExternalIds.
+func (mr *MockSequenceRepositoryMockRecorder) FindByExternalIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByExternalIds", reflect.TypeOf((*MockSequenceRepository)(nil).FindByExternalIds), ctx, ids)
+}
+
+// FindByNames mocks base method.
+func (m *MockSequenceRepository) FindByNames(ctx context.Context, names []string) ([]*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNames", ctx, names)
+	ret0, _ := ret[0].([]*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNames indicates an expected call of FindByNames.
+func (mr *MockSequenceRepositoryMockRecorder) FindByNames(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNames", reflect.TypeOf((*MockSequenceRepository)(nil).FindByNames), ctx, names)
+}
+
+// FindDeletedByExternalId mocks base method.
+func (m *MockSequenceRepository) FindDeletedByExternalId(ctx context.Context, id uuid.UUID) (*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByExternalId", ctx, id)
+	ret0, _ := ret[0].(*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByExternalId indicates an expected call of FindDeletedByExternalId.
+func (mr *MockSequenceRepositoryMockRecorder) FindDeletedByExternalId(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByExternalId", reflect.TypeOf((*MockSequenceRepository)(nil).FindDeletedByExternalId), ctx, id)
+}
+
+// FindPage mocks base method.
+func (m *MockSequenceRepository) FindPage(ctx context.Context, filter models.SequenceFilter, cursor *utils.Cursor, limit int) ([]*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, filter, cursor, limit)
+	ret0, _ := ret[0].([]*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockSequenceRepositoryMockRecorder) FindPage(ctx, filter, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockSequenceRepository)(nil).FindPage), ctx, filter, cursor, limit)
+}
+
+// Import mocks base method.
+func (m *MockSequenceRepository) Import(ctx context.Context, sequences []*models.SequenceWithSteps) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, sequences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockSequenceRepositoryMockRecorder) Import(ctx, sequences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockSequenceRepository)(nil).Import), ctx, sequences)
+}
+
+// Purge mocks base method.
+func (m *MockSequenceRepository) Purge(ctx context.Context, retention time.Duration) ([]*models.SequenceWithSteps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, retention)
+	ret0, _ := ret[0].([]*models.SequenceWithSteps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockSequenceRepositoryMockRecorder) Purge(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockSequenceRepository)(nil).Purge), ctx, retention)
+}
+
+// Reorder mocks base method.
+func (m *MockSequenceRepository) Reorder(ctx context.Context, id uuid.UUID, version int32, stepIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, id, version, stepIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockSequenceRepositoryMockRecorder) Reorder(ctx, id, version, stepIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockSequenceRepository)(nil).Reorder), ctx, id, version, stepIDs)
+}
+
+// Replace mocks base method.
+func (m *MockSequenceRepository) Replace(ctx context.Context, model *models.SequenceWithSteps) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockSequenceRepositoryMockRecorder) Replace(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockSequenceRepository)(nil).Replace), ctx, model)
+}
+
+// Restore mocks base method.
+func (m *MockSequenceRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSequenceRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSequenceRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockSequenceRepository) Update(ctx context.Context, model *models.SequenceWithSteps) error {
 	m.ctrl.T.Helper()
@@ -113,3 +290,17 @@ func (mr *MockSequenceRepositoryMockRecorder) Update(ctx, model any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSequenceRepository)(nil).Update), ctx, model)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockSequenceRepository) UpdateStatus(ctx context.Context, model *models.SequenceWithSteps, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, model, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockSequenceRepositoryMockRecorder) UpdateStatus(ctx, model, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockSequenceRepository)(nil).UpdateStatus), ctx, model, to)
+}
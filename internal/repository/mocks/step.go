@@ -57,17 +57,18 @@ func (mr *MockStepRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
 }
 
 // Delete mocks base method.
-func (m *MockStepRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *MockStepRepository) Delete(ctx context.Context, id uuid.UUID, version int32) (*dao.Step, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
+	ret0, _ := ret[0].(*dao.Step)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockStepRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockStepRepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStepRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStepRepository)(nil).Delete), ctx, id, version)
 }
 
 // FindOne mocks base method.
@@ -85,6 +86,21 @@ func (mr *MockStepRepositoryMockRecorder) FindOne(ctx, sequenceID, stepID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockStepRepository)(nil).FindOne), ctx, sequenceID, stepID)
 }
 
+// FindPage mocks base method.
+func (m *MockStepRepository) FindPage(ctx context.Context, sequenceID uuid.UUID, afterStepNumber int32, limit int) ([]*dao.Step, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, sequenceID, afterStepNumber, limit)
+	ret0, _ := ret[0].([]*dao.Step)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockStepRepositoryMockRecorder) FindPage(ctx, sequenceID, afterStepNumber, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockStepRepository)(nil).FindPage), ctx, sequenceID, afterStepNumber, limit)
+}
+
 // Update mocks base method.
 func (m *MockStepRepository) Update(ctx context.Context, model *dao.Step) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/variant.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/variant.go -destination=internal/repository/mocks/variant.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/murilo-bracero/sequence-technical-test/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVariantRepository is a mock of VariantRepository interface.
+type MockVariantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVariantRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockVariantRepositoryMockRecorder is the mock recorder for MockVariantRepository.
+type MockVariantRepositoryMockRecorder struct {
+	mock *MockVariantRepository
+}
+
+// NewMockVariantRepository creates a new mock instance.
+func NewMockVariantRepository(ctrl *gomock.Controller) *MockVariantRepository {
+	mock := &MockVariantRepository{ctrl: ctrl}
+	mock.recorder = &MockVariantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVariantRepository) EXPECT() *MockVariantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockVariantRepository) Assign(ctx context.Context, stepID int32, enrollmentID uuid.UUID, variantID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, stepID, enrollmentID, variantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockVariantRepositoryMockRecorder) Assign(ctx, stepID, enrollmentID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockVariantRepository)(nil).Assign), ctx, stepID, enrollmentID, variantID)
+}
+
+// Create mocks base method.
+func (m *MockVariantRepository) Create(ctx context.Context, sequenceID int32, model *models.StepVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, sequenceID, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVariantRepositoryMockRecorder) Create(ctx, sequenceID, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVariantRepository)(nil).Create), ctx, sequenceID, model)
+}
+
+// Delete mocks base method.
+func (m *MockVariantRepository) Delete(ctx context.Context, sequenceID, stepID int32, variantID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, sequenceID, stepID, variantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVariantRepositoryMockRecorder) Delete(ctx, sequenceID, stepID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVariantRepository)(nil).Delete), ctx, sequenceID, stepID, variantID)
+}
+
+// FindAll mocks base method.
+func (m *MockVariantRepository) FindAll(ctx context.Context, stepID int32) ([]*models.StepVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, stepID)
+	ret0, _ := ret[0].([]*models.StepVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockVariantRepositoryMockRecorder) FindAll(ctx, stepID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockVariantRepository)(nil).FindAll), ctx, stepID)
+}
+
+// FindAssignment mocks base method.
+func (m *MockVariantRepository) FindAssignment(ctx context.Context, stepID int32, enrollmentID uuid.UUID) (*models.StepVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAssignment", ctx, stepID, enrollmentID)
+	ret0, _ := ret[0].(*models.StepVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAssignment indicates an expected call of FindAssignment.
+func (mr *MockVariantRepositoryMockRecorder) FindAssignment(ctx, stepID, enrollmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAssignment", reflect.TypeOf((*MockVariantRepository)(nil).FindAssignment), ctx, stepID, enrollmentID)
+}
+
+// FindOne mocks base method.
+func (m *MockVariantRepository) FindOne(ctx context.Context, stepID int32, variantID uuid.UUID) (*models.StepVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOne", ctx, stepID, variantID)
+	ret0, _ := ret[0].(*models.StepVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne.
+func (mr *MockVariantRepositoryMockRecorder) FindOne(ctx, stepID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockVariantRepository)(nil).FindOne), ctx, stepID, variantID)
+}
+
+// Update mocks base method.
+func (m *MockVariantRepository) Update(ctx context.Context, sequenceID int32, model *models.StepVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, sequenceID, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockVariantRepositoryMockRecorder) Update(ctx, sequenceID, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVariantRepository)(nil).Update), ctx, sequenceID, model)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+)
+
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, burst int, rate float64, cost int) (float64, bool, error)
+	Purge(ctx context.Context, fillTime time.Duration) (int64, error)
+}
+
+type rateLimitRepository struct {
+	queries *dao.Queries
+}
+
+var _ RateLimitRepository = (*rateLimitRepository)(nil)
+
+func NewRateLimitRepository(db db.DB) *rateLimitRepository {
+	return &rateLimitRepository{queries: db.Queries()}
+}
+
+// Take refills the bucket of key at rate tokens per second, up to burst, and
+// takes cost tokens from it when it holds them. It returns the tokens left and
+// whether they were taken. Buckets are refilled by the clock of the database,
+// so every replica of the API sees the same buckets.
+func (r *rateLimitRepository) Take(ctx context.Context, key string, burst int, rate float64, cost int) (float64, bool, error) {
+	row, err := r.queries.TakeRateLimitTokens(ctx, dao.TakeRateLimitTokensParams{
+		BucketKey: key,
+		Burst:     float64(burst),
+		Cost:      float64(cost),
+		Rate:      rate,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return row.Tokens, row.Allowed, nil
+}
+
+// Purge removes the buckets not updated for fillTime, by the clock of the
+// database like Take, as they are full again.
+func (r *rateLimitRepository) Purge(ctx context.Context, fillTime time.Duration) (int64, error) {
+	return r.queries.PurgeRateLimitBuckets(ctx, fillTime.Seconds())
+}
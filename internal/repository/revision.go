@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type RevisionRepository interface {
+	FindAll(ctx context.Context, sequenceID uuid.UUID, limit int, offset int) ([]*models.SequenceRevision, error)
+	FindOne(ctx context.Context, sequenceID uuid.UUID, revision int32) (*models.SequenceRevision, error)
+}
+
+type revisionRepository struct {
+	queries *dao.Queries
+	db      db.DB
+}
+
+var _ RevisionRepository = (*revisionRepository)(nil)
+
+func NewRevisionRepository(db db.DB) *revisionRepository {
+	return &revisionRepository{queries: db.Queries(), db: db}
+}
+
+func (r *revisionRepository) FindAll(ctx context.Context, sequenceID uuid.UUID, limit int, offset int) ([]*models.SequenceRevision, error) {
+	rows, err := r.queries.GetSequenceRevisions(ctx, dao.GetSequenceRevisionsParams{
+		ExternalID:  sequenceID,
+		Limit:       int32(limit),
+		Offset:      int32(offset),
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*models.SequenceRevision, 0, len(rows))
+
+	for _, row := range rows {
+		revision, err := toSequenceRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (r *revisionRepository) FindOne(ctx context.Context, sequenceID uuid.UUID, revision int32) (*models.SequenceRevision, error) {
+	row, err := r.queries.GetSequenceRevision(ctx, dao.GetSequenceRevisionParams{
+		ExternalID:  sequenceID,
+		Revision:    revision,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSequenceRevision(row)
+}
+
+// createRevision stores a snapshot of the sequence as seen by the transaction
+// of qtx, it must run after the mutation and before the commit. The sequence
+// row is locked so concurrent mutations get sequential revision numbers, and
+// since every change of the content is recorded here it also fails with
+// ErrSequenceReadOnly when the status of the sequence does not allow changes.
+func createRevision(ctx context.Context, qtx *dao.Queries, sequenceID int32) error {
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	sequence, err := qtx.LockSequence(ctx, dao.LockSequenceParams{
+		ID:          sequenceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to lock sequence for revision", err.Error(), err)
+		return err
+	}
+
+	if !models.IsEditableStatus(sequence.Status) {
+		return ErrSequenceReadOnly
+	}
+
+	return writeRevision(ctx, qtx, sequence)
+}
+
+// writeRevision stores the sequence as it is, along with its steps that are not
+// in the trash, as a new revision. Unlike createRevision it does not check the
+// status, moving a sequence to the trash and back is not an edit of its content.
+func writeRevision(ctx context.Context, qtx *dao.Queries, sequence dao.Sequence) error {
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	steps, err := qtx.GetSequenceSteps(ctx, dao.GetSequenceStepsParams{
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get steps for revision", err.Error(), err)
+		return err
+	}
+
+	variants, err := qtx.GetSequenceVariants(ctx, dao.GetSequenceVariantsParams{
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get variants for revision", err.Error(), err)
+		return err
+	}
+
+	variantsByStep := make(map[int32][]*models.VariantSnapshot)
+	for _, variant := range variants {
+		variantsByStep[variant.StepID] = append(variantsByStep[variant.StepID], &models.VariantSnapshot{
+			ExternalID:  variant.ExternalID,
+			MailSubject: variant.MailSubject,
+			MailContent: variant.MailContent,
+			Weight:      variant.Weight,
+		})
+	}
+
+	snapshot := models.SequenceSnapshot{
+		Name:                 sequence.SequenceName,
+		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
+		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
+		Variables:            decodeVariables(sequence.Variables),
+		Deleted:              sequence.DeletedAt.Valid,
+		Steps:                make([]*models.StepSnapshot, 0, len(steps)),
+	}
+
+	for _, step := range steps {
+		snapshot.Steps = append(snapshot.Steps, &models.StepSnapshot{
+			ExternalID:       step.ExternalID,
+			StepNumber:       step.StepNumber,
+			MailSubject:      step.MailSubject,
+			MailContent:      step.MailContent,
+			DelayDays:        step.DelayDays,
+			DelayHours:       step.DelayHours,
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindowStart:  step.SendWindowStart,
+			SendWindowEnd:    step.SendWindowEnd,
+			MailText:         step.MailText,
+			Variants:         variantsByStep[step.ID],
+		})
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.CreateSequenceRevision(ctx, dao.CreateSequenceRevisionParams{
+		SequenceID:  sequence.ID,
+		Snapshot:    raw,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to create sequence revision", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+func toSequenceRevision(row dao.SequenceRevision) (*models.SequenceRevision, error) {
+	revision := &models.SequenceRevision{
+		Revision: row.Revision,
+		Created:  row.Created.Time,
+	}
+
+	if err := json.Unmarshal(row.Snapshot, &revision.Snapshot); err != nil {
+		slog.Error("failed to unmarshal revision snapshot", err.Error(), err)
+		return nil, err
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type RoleBindingRepository interface {
+	FindAll(ctx context.Context) ([]*models.RoleBinding, error)
+	Save(ctx context.Context, model *models.RoleBinding) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindRoles(ctx context.Context, userID string, sequenceID *uuid.UUID, stepID *uuid.UUID) ([]models.Role, error)
+}
+
+type roleBindingRepository struct {
+	queries *dao.Queries
+}
+
+var _ RoleBindingRepository = (*roleBindingRepository)(nil)
+
+func NewRoleBindingRepository(db db.DB) *roleBindingRepository {
+	return &roleBindingRepository{queries: db.Queries()}
+}
+
+func (r *roleBindingRepository) FindAll(ctx context.Context) ([]*models.RoleBinding, error) {
+	rows, err := r.queries.GetRoleBindings(ctx, tenancy.WorkspaceID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := make([]*models.RoleBinding, 0, len(rows))
+	for _, row := range rows {
+		bindings = append(bindings, &models.RoleBinding{
+			ID:                 row.ID,
+			ExternalID:         row.ExternalID,
+			UserID:             row.UserID,
+			Role:               models.Role(row.Role),
+			SequenceID:         row.SequenceID,
+			SequenceExternalID: row.SequenceExternalID,
+			Created:            row.Created.Time,
+		})
+	}
+
+	return bindings, nil
+}
+
+// Save binds the role to the user in the sequence of model, or in the whole
+// workspace without one, replacing the role the user had there.
+func (r *roleBindingRepository) Save(ctx context.Context, model *models.RoleBinding) error {
+	row, err := r.queries.SaveRoleBinding(ctx, dao.SaveRoleBindingParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		UserID:      model.UserID,
+		Role:        string(model.Role),
+		SequenceID:  model.SequenceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	model.ID = row.ID
+	model.ExternalID = row.ExternalID
+	model.Created = row.Created.Time
+
+	return nil
+}
+
+func (r *roleBindingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := r.queries.DeleteRoleBinding(ctx, dao.DeleteRoleBindingParams{
+		ExternalID:  id,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindRoles returns the roles of the user in the workspace and, when given,
+// in the sequence or in the sequence of the step.
+func (r *roleBindingRepository) FindRoles(ctx context.Context, userID string, sequenceID *uuid.UUID, stepID *uuid.UUID) ([]models.Role, error) {
+	rows, err := r.queries.GetUserRoles(ctx, dao.GetUserRolesParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		UserID:      userID,
+		SequenceID:  sequenceID,
+		StepID:      stepID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]models.Role, 0, len(rows))
+	for _, row := range rows {
+		roles = append(roles, models.Role(row))
+	}
+
+	return roles, nil
+}
@@ -3,20 +3,43 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/murilo-bracero/sequence-technical-test/internal/db"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
 )
 
 type SequenceRepository interface {
 	FindByExternalId(ctx context.Context, id uuid.UUID) (*models.SequenceWithSteps, error)
 	FindAll(ctx context.Context, limit int, offset int) ([]*models.SequenceWithSteps, error)
+	FindByExternalIds(ctx context.Context, ids []uuid.UUID) ([]*models.SequenceWithSteps, error)
+	FindByNames(ctx context.Context, names []string) ([]*models.SequenceWithSteps, error)
 	Create(ctx context.Context, model *models.SequenceWithSteps) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int32) error
 	Update(ctx context.Context, model *models.SequenceWithSteps) error
+	Replace(ctx context.Context, model *models.SequenceWithSteps) error
+	Reorder(ctx context.Context, id uuid.UUID, version int32, stepIDs []uuid.UUID) error
+	FindAllDeleted(ctx context.Context, limit int, offset int) ([]*models.SequenceWithSteps, error)
+	FindDeletedByExternalId(ctx context.Context, id uuid.UUID) (*models.SequenceWithSteps, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, retention time.Duration) ([]*models.SequenceWithSteps, error)
+	FindPage(ctx context.Context, filter models.SequenceFilter, cursor *utils.Cursor, limit int) ([]*models.SequenceWithSteps, error)
+	Count(ctx context.Context, filter models.SequenceFilter) (int64, error)
+	UpdateStatus(ctx context.Context, model *models.SequenceWithSteps, to string) error
+	Import(ctx context.Context, sequences []*models.SequenceWithSteps) error
+	Clone(ctx context.Context, model *models.SequenceWithSteps) error
 }
 
 type sequenceRepository struct {
@@ -31,38 +54,63 @@ func NewSequenceRepository(db db.DB) *sequenceRepository {
 }
 
 func (r *sequenceRepository) FindByExternalId(ctx context.Context, externalId uuid.UUID) (*models.SequenceWithSteps, error) {
-	row, err := r.queries.GetSequenceById(ctx, externalId)
+	row, err := r.queries.GetSequenceById(ctx, dao.GetSequenceByIdParams{
+		ExternalID:  externalId,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	steps := make([]*dao.Step, 0)
+	return toSequenceWithSteps(row), nil
+}
 
-	if err := json.Unmarshal(row.Steps, &steps); err != nil {
-		slog.Error("failed to unmarshal steps", err.Error(), err)
+// FindByExternalIds returns the sequences with the given ids that are not in
+// the trash, ids without a sequence are left out.
+func (r *sequenceRepository) FindByExternalIds(ctx context.Context, ids []uuid.UUID) ([]*models.SequenceWithSteps, error) {
+	rows, err := r.queries.GetSequencesByExternalIds(ctx, dao.GetSequencesByExternalIdsParams{
+		ExternalIds: ids,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	model := &models.SequenceWithSteps{
-		ID:                   row.ID,
-		ExternalID:           row.ExternalID,
-		Name:                 row.SequenceName,
-		OpenTrackingEnabled:  row.OpenTrackingEnabled,
-		ClickTrackingEnabled: row.ClickTrackingEnabled,
-		Created:              row.Created.Time,
-		Steps:                steps,
+	sequences := make([]*models.SequenceWithSteps, 0, len(rows))
+
+	for _, row := range rows {
+		sequences = append(sequences, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
 	}
 
-	if row.Updated.Valid {
-		model.Updated = &row.Updated.Time
+	return sequences, nil
+}
+
+// FindByNames returns the sequences named after any of the given names that
+// are not in the trash, oldest first. Names are not unique, so a name may
+// match several sequences.
+func (r *sequenceRepository) FindByNames(ctx context.Context, names []string) ([]*models.SequenceWithSteps, error) {
+	rows, err := r.queries.GetSequencesByNames(ctx, dao.GetSequencesByNamesParams{
+		Names:       names,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sequences := make([]*models.SequenceWithSteps, 0, len(rows))
+
+	for _, row := range rows {
+		sequences = append(sequences, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
 	}
 
-	return model, nil
+	return sequences, nil
 }
 
 func (r *sequenceRepository) FindAll(ctx context.Context, limit int, offset int) ([]*models.SequenceWithSteps, error) {
 	rows, err := r.queries.GetSequences(ctx, dao.GetSequencesParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		Limit:       int32(limit),
+		Offset:      int32(offset),
 	})
 	if err != nil {
 		return nil, err
@@ -71,32 +119,120 @@ func (r *sequenceRepository) FindAll(ctx context.Context, limit int, offset int)
 	sequences := make([]*models.SequenceWithSteps, 0, len(rows))
 
 	for _, row := range rows {
-		steps := make([]*dao.Step, 0)
+		sequences = append(sequences, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
+	}
 
-		if err := json.Unmarshal(row.Steps, &steps); err != nil {
-			slog.Error("failed to unmarshal steps", err.Error(), err)
-		}
+	return sequences, nil
+}
 
-		model := &models.SequenceWithSteps{
-			ID:                   row.ID,
-			ExternalID:           row.ExternalID,
-			Name:                 row.SequenceName,
-			OpenTrackingEnabled:  row.OpenTrackingEnabled,
-			ClickTrackingEnabled: row.ClickTrackingEnabled,
-			Created:              row.Created.Time,
-			Steps:                steps,
-		}
+// FindPage returns up to limit sequences matching the filter, in the filter's
+// sort order, starting right after the cursor or right before it when the
+// cursor points backwards.
+func (r *sequenceRepository) FindPage(ctx context.Context, filter models.SequenceFilter, cursor *utils.Cursor, limit int) ([]*models.SequenceWithSteps, error) {
+	params := dao.GetSequencesPageParams{
+		WorkspaceID:          tenancy.WorkspaceID(ctx),
+		Name:                 likePattern(filter.Name),
+		OpenTrackingEnabled:  filter.OpenTrackingEnabled,
+		ClickTrackingEnabled: filter.ClickTrackingEnabled,
+		CreatedAfter:         timestamp(filter.CreatedAfter),
+		CreatedBefore:        timestamp(filter.CreatedBefore),
+		UpdatedAfter:         timestamp(filter.UpdatedAfter),
+		UpdatedBefore:        timestamp(filter.UpdatedBefore),
+		Search:               filter.Search,
+		SortBy:               filter.SortBy,
+		After:                !filter.Descending,
+		PageLimit:            int32(limit),
+	}
+
+	if cursor != nil {
+		params.CursorID = &cursor.ID
+		params.After = filter.Descending == cursor.Backward
 
-		if row.Updated.Valid {
-			model.Updated = &row.Updated.Time
+		switch filter.SortBy {
+		case models.SortByName:
+			params.CursorName = &cursor.Value
+		case models.SortByStepCount:
+			count, err := strconv.ParseInt(cursor.Value, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			cursorCount := int32(count)
+			params.CursorCount = &cursorCount
+		default:
+			t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return nil, err
+			}
+			params.CursorTime = timestamp(&t)
 		}
+	}
+
+	rows, err := r.queries.GetSequencesPage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	sequences := make([]*models.SequenceWithSteps, 0, len(rows))
+
+	for _, row := range rows {
+		sequences = append(sequences, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
+	}
+
+	// backward pages are read in reverse order, so they are flipped back here
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(sequences)
+	}
+
+	return sequences, nil
+}
+
+func (r *sequenceRepository) Count(ctx context.Context, filter models.SequenceFilter) (int64, error) {
+	return r.queries.CountSequences(ctx, dao.CountSequencesParams{
+		WorkspaceID:          tenancy.WorkspaceID(ctx),
+		Name:                 likePattern(filter.Name),
+		OpenTrackingEnabled:  filter.OpenTrackingEnabled,
+		ClickTrackingEnabled: filter.ClickTrackingEnabled,
+		CreatedAfter:         timestamp(filter.CreatedAfter),
+		CreatedBefore:        timestamp(filter.CreatedBefore),
+		UpdatedAfter:         timestamp(filter.UpdatedAfter),
+		UpdatedBefore:        timestamp(filter.UpdatedBefore),
+		Search:               filter.Search,
+	})
+}
+
+func (r *sequenceRepository) FindAllDeleted(ctx context.Context, limit int, offset int) ([]*models.SequenceWithSteps, error) {
+	rows, err := r.queries.GetDeletedSequences(ctx, dao.GetDeletedSequencesParams{
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+		Limit:       int32(limit),
+		Offset:      int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sequences := make([]*models.SequenceWithSteps, 0, len(rows))
 
-		sequences = append(sequences, model)
+	for _, row := range rows {
+		sequences = append(sequences, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
 	}
 
 	return sequences, nil
 }
 
+// FindDeletedByExternalId returns the sequence in the trash as the trash lists
+// it, failing with pgx.ErrNoRows when it is not in the trash.
+func (r *sequenceRepository) FindDeletedByExternalId(ctx context.Context, externalId uuid.UUID) (*models.SequenceWithSteps, error) {
+	row, err := r.queries.GetDeletedSequenceById(ctx, dao.GetDeletedSequenceByIdParams{
+		ExternalID:  externalId,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSequenceWithSteps(dao.GetSequenceByIdRow(row)), nil
+}
+
 func (r *sequenceRepository) Create(ctx context.Context, model *models.SequenceWithSteps) error {
 	tx, err := r.db.Tx(ctx)
 	if err != nil {
@@ -106,16 +242,32 @@ func (r *sequenceRepository) Create(ctx context.Context, model *models.SequenceW
 
 	defer tx.Rollback(ctx)
 
-	qtx := r.queries.WithTx(tx)
+	if err := create(ctx, r.queries.WithTx(tx), model); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
 
+	return nil
+}
+
+// create stores the sequence and its steps within the transaction of qtx.
+func create(ctx context.Context, qtx *dao.Queries, model *models.SequenceWithSteps) error {
 	sequence, err := qtx.CreateSequence(ctx, dao.CreateSequenceParams{
 		SequenceName:         model.Name,
 		OpenTrackingEnabled:  model.OpenTrackingEnabled,
 		ClickTrackingEnabled: model.ClickTrackingEnabled,
+		Variables:            encodeVariables(model.Variables),
+		WorkspaceID:          tenancy.WorkspaceID(ctx),
 	})
 
 	model.ID = sequence.ID
 	model.ExternalID = sequence.ExternalID
+	model.Status = sequence.Status
+	model.Version = sequence.Version
 	model.Created = sequence.Created.Time
 
 	if sequence.Updated.Valid {
@@ -133,10 +285,18 @@ func (r *sequenceRepository) Create(ctx context.Context, model *models.SequenceW
 		step.SequenceID = sequence.ID
 
 		createStepParams = append(createStepParams, dao.CreateStepsParams{
-			ExternalID:  step.ExternalID,
-			MailSubject: step.MailSubject,
-			MailContent: step.MailContent,
-			SequenceID:  step.SequenceID,
+			ExternalID:       step.ExternalID,
+			StepNumber:       step.StepNumber,
+			MailSubject:      step.MailSubject,
+			MailContent:      step.MailContent,
+			SequenceID:       step.SequenceID,
+			DelayDays:        step.DelayDays,
+			DelayHours:       step.DelayHours,
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindowStart:  step.SendWindowStart,
+			SendWindowEnd:    step.SendWindowEnd,
+			MailText:         step.MailText,
+			WorkspaceID:      tenancy.WorkspaceID(ctx),
 		})
 	}
 
@@ -146,6 +306,167 @@ func (r *sequenceRepository) Create(ctx context.Context, model *models.SequenceW
 		return err
 	}
 
+	return createRevision(ctx, qtx, sequence.ID)
+}
+
+// Import stores the sequences in a single transaction, so either all of them
+// are stored or none is. Sequences without an external id are created and the
+// others replace the stored sequence, following the rules of Replace.
+func (r *sequenceRepository) Import(ctx context.Context, sequences []*models.SequenceWithSteps) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	for _, model := range sequences {
+		if model.ExternalID == uuid.Nil {
+			err = create(ctx, qtx, model)
+		} else {
+			err = replace(ctx, qtx, model)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Clone copies the sequence referenced by the SourceID of the model, with its
+// steps and their variants, in a single transaction, failing with
+// pgx.ErrNoRows when the source is not found. The copy takes the name and the
+// tracking settings of the model, new ids and starts as a draft, the model is
+// filled with the stored copy.
+func (r *sequenceRepository) Clone(ctx context.Context, model *models.SequenceWithSteps) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	// locks the source so its steps cannot change while they are copied
+	source, err := qtx.GetSequenceForUpdate(ctx, dao.GetSequenceForUpdateParams{
+		ExternalID:  *model.SourceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	sequence, err := qtx.CloneSequence(ctx, dao.CloneSequenceParams{
+		SequenceName:         model.Name,
+		OpenTrackingEnabled:  model.OpenTrackingEnabled,
+		ClickTrackingEnabled: model.ClickTrackingEnabled,
+		SourceID:             source.ID,
+		WorkspaceID:          workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to clone sequence", err.Error(), err)
+		return err
+	}
+
+	if _, err := qtx.CloneSteps(ctx, dao.CloneStepsParams{
+		TargetID:    sequence.ID,
+		SourceID:    source.ID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to clone steps", err.Error(), err)
+		return err
+	}
+
+	if _, err := qtx.CloneStepVariants(ctx, dao.CloneStepVariantsParams{
+		SourceID:    source.ID,
+		TargetID:    sequence.ID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to clone step variants", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, sequence.ID); err != nil {
+		return err
+	}
+
+	row, err := qtx.GetSequenceById(ctx, dao.GetSequenceByIdParams{
+		ExternalID:  sequence.ExternalID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get cloned sequence", err.Error(), err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	*model = *toSequenceWithSteps(row)
+
+	return nil
+}
+
+// Delete moves the sequence and its steps to the trash, they are only removed
+// from the database by Purge once the retention period is over. Active
+// sequences fail with ErrSequenceReadOnly, they must be paused or archived
+// before they are deleted. A version other than 0 must match the stored one.
+func (r *sequenceRepository) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	sequence, err := qtx.DeleteSequence(ctx, dao.DeleteSequenceParams{
+		ExternalID:  id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if sequence.Status == models.StatusActive {
+		return ErrSequenceReadOnly
+	}
+
+	if version != 0 && version != sequence.Version {
+		return ErrVersionConflict
+	}
+
+	// written before the steps go to the trash so the revision keeps them
+	if err := writeRevision(ctx, qtx, sequence); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteSequenceSteps(ctx, dao.DeleteSequenceStepsParams{
+		SequenceID:  sequence.ID,
+		DeletedAt:   sequence.DeletedAt,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to delete sequence steps", err.Error(), err)
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		slog.Error("failed to commit transaction", err.Error(), err)
 		return err
@@ -154,18 +475,606 @@ func (r *sequenceRepository) Create(ctx context.Context, model *models.SequenceW
 	return nil
 }
 
-func (r *sequenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.queries.DeleteSequence(ctx, id)
+func (r *sequenceRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	sequence, err := qtx.RestoreSequence(ctx, dao.RestoreSequenceParams{
+		ExternalID:  id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.RestoreSequenceSteps(ctx, dao.RestoreSequenceStepsParams{
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to restore sequence steps", err.Error(), err)
+		return err
+	}
+
+	if err := writeRevision(ctx, qtx, sequence); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
 }
 
+// Purge permanently removes the sequences that have been in the trash for
+// longer than retention, returning them as they were in the trash. The cutoff
+// is computed by the database, which wrote deleted_at, so it does not depend
+// on the time zone of either side. Sequences restored since they were read
+// are left alone.
+func (r *sequenceRepository) Purge(ctx context.Context, retention time.Duration) ([]*models.SequenceWithSteps, error) {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	rows, err := qtx.GetExpiredSequences(ctx, dao.GetExpiredSequencesParams{
+		WorkspaceID:      workspaceID,
+		RetentionSeconds: retention.Seconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ExternalID)
+	}
+
+	purgedIDs, err := qtx.PurgeDeletedSequences(ctx, dao.PurgeDeletedSequencesParams{
+		WorkspaceID: workspaceID,
+		ExternalIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return nil, err
+	}
+
+	purged := make([]*models.SequenceWithSteps, 0, len(purgedIDs))
+	for _, row := range rows {
+		if slices.Contains(purgedIDs, row.ExternalID) {
+			purged = append(purged, toSequenceWithSteps(dao.GetSequenceByIdRow(row)))
+		}
+	}
+
+	return purged, nil
+}
+
+// UpdateStatus moves the sequence from the status of the model to the given
+// one, failing with pgx.ErrNoRows when the sequence does not exist or is no
+// longer in the status of the model, so concurrent transitions cannot both
+// succeed.
+func (r *sequenceRepository) UpdateStatus(ctx context.Context, model *models.SequenceWithSteps, to string) error {
+	updated, err := r.queries.UpdateSequenceStatus(ctx, dao.UpdateSequenceStatusParams{
+		ToStatus:    to,
+		ExternalID:  model.ExternalID,
+		FromStatus:  model.Status,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	model.Status = updated.Status
+	model.Version = updated.Version
+	model.Updated = &updated.Updated.Time
+
+	return nil
+}
+
+// Update fails with ErrVersionConflict when the sequence changed since the
+// version of the model was read.
 func (r *sequenceRepository) Update(ctx context.Context, model *models.SequenceWithSteps) error {
-	updated, err := r.queries.UpdateSequence(ctx, dao.UpdateSequenceParams{
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	updated, err := qtx.UpdateSequence(ctx, dao.UpdateSequenceParams{
 		ID:                   model.ID,
+		SequenceName:         model.Name,
+		OpenTrackingEnabled:  model.OpenTrackingEnabled,
+		ClickTrackingEnabled: model.ClickTrackingEnabled,
+		Variables:            encodeVariables(model.Variables),
+		Version:              model.Version,
+		WorkspaceID:          tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrVersionConflict
+		}
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, model.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	model.Version = updated.Version
+	model.Updated = &updated.Updated.Time
+
+	return nil
+}
+
+// Replace overwrites the sequence and its steps with the given model in a
+// single transaction. Steps of the model are matched with the stored ones by
+// external id: matching steps are updated, steps without an id or with an id
+// that is no longer stored are created and stored steps missing from the model
+// are deleted. The variants of the steps are replaced as well when the model
+// has them, otherwise the steps kept keep their variants. A version other than
+// 0 in the model must match the stored one.
+func (r *sequenceRepository) Replace(ctx context.Context, model *models.SequenceWithSteps) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := replace(ctx, r.queries.WithTx(tx), model); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// replace overwrites the sequence within the transaction of qtx, as described
+// by Replace.
+func replace(ctx context.Context, qtx *dao.Queries, model *models.SequenceWithSteps) error {
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	// locks the sequence so concurrent replacements are applied one after the other
+	sequence, err := qtx.GetSequenceForUpdate(ctx, dao.GetSequenceForUpdateParams{
+		ExternalID:  model.ExternalID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if model.Version != 0 && model.Version != sequence.Version {
+		return ErrVersionConflict
+	}
+
+	// steps swap numbers while they are updated, so uniqueness is only checked on commit
+	if err := qtx.DeferStepNumbers(ctx); err != nil {
+		return err
+	}
+
+	updated, err := qtx.UpdateSequence(ctx, dao.UpdateSequenceParams{
+		ID:                   sequence.ID,
+		SequenceName:         model.Name,
 		OpenTrackingEnabled:  model.OpenTrackingEnabled,
 		ClickTrackingEnabled: model.ClickTrackingEnabled,
+		Variables:            encodeVariables(model.Variables),
+		Version:              sequence.Version,
+		WorkspaceID:          workspaceID,
 	})
+	if err != nil {
+		slog.Error("failed to update sequence", err.Error(), err)
+		return err
+	}
+
+	existing, err := qtx.GetSequenceSteps(ctx, dao.GetSequenceStepsParams{
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get sequence steps", err.Error(), err)
+		return err
+	}
+
+	stored := make(map[uuid.UUID]dao.Step, len(existing))
+	for _, step := range existing {
+		stored[step.ExternalID] = step
+	}
+
+	createStepParams := make([]dao.CreateStepsParams, 0, len(model.Steps))
+	for _, step := range model.Steps {
+		step.SequenceID = sequence.ID
+
+		current, ok := stored[step.ExternalID]
+		if !ok {
+			if step.ExternalID == uuid.Nil {
+				step.ExternalID = uuid.New()
+			}
 
+			createStepParams = append(createStepParams, dao.CreateStepsParams{
+				ExternalID:       step.ExternalID,
+				StepNumber:       step.StepNumber,
+				MailSubject:      step.MailSubject,
+				MailContent:      step.MailContent,
+				SequenceID:       step.SequenceID,
+				DelayDays:        step.DelayDays,
+				DelayHours:       step.DelayHours,
+				BusinessDaysOnly: step.BusinessDaysOnly,
+				SendWindowStart:  step.SendWindowStart,
+				SendWindowEnd:    step.SendWindowEnd,
+				MailText:         step.MailText,
+				WorkspaceID:      tenancy.WorkspaceID(ctx),
+			})
+			continue
+		}
+
+		delete(stored, step.ExternalID)
+		step.ID = current.ID
+
+		if sameStep(&current, step) {
+			continue
+		}
+
+		if _, err := qtx.UpdateStep(ctx, dao.UpdateStepParams{
+			ExternalID:       step.ExternalID,
+			MailSubject:      step.MailSubject,
+			MailContent:      step.MailContent,
+			StepNumber:       step.StepNumber,
+			DelayDays:        step.DelayDays,
+			DelayHours:       step.DelayHours,
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindowStart:  step.SendWindowStart,
+			SendWindowEnd:    step.SendWindowEnd,
+			MailText:         step.MailText,
+			Version:          current.Version,
+			WorkspaceID:      workspaceID,
+		}); err != nil {
+			slog.Error("failed to update step", err.Error(), err)
+			return err
+		}
+	}
+
+	for id := range stored {
+		if _, err := qtx.DeleteStep(ctx, dao.DeleteStepParams{
+			ExternalID:  id,
+			WorkspaceID: workspaceID,
+		}); err != nil {
+			slog.Error("failed to delete step", err.Error(), err)
+			return err
+		}
+	}
+
+	if len(createStepParams) > 0 {
+		if _, err := qtx.CreateSteps(ctx, createStepParams); err != nil {
+			slog.Error("failed to create steps", err.Error(), err)
+			return err
+		}
+	}
+
+	if model.Variants != nil {
+		if err := replaceVariants(ctx, qtx, sequence.ID, model.Variants); err != nil {
+			return err
+		}
+	}
+
+	if err := createRevision(ctx, qtx, sequence.ID); err != nil {
+		return err
+	}
+
+	model.ID = sequence.ID
+	model.Status = sequence.Status
+	model.Version = updated.Version
+	model.Created = sequence.Created.Time
 	model.Updated = &updated.Updated.Time
 
-	return err
+	return nil
+}
+
+// replaceVariants leaves the steps of the sequence with the given variants, by
+// the external id of their step. Variants keep their ids, the missing ones are
+// created again with the ids they had and the others are deleted.
+func replaceVariants(ctx context.Context, qtx *dao.Queries, sequenceID int32, variants map[uuid.UUID][]*models.StepVariant) error {
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	steps, err := qtx.GetSequenceSteps(ctx, dao.GetSequenceStepsParams{
+		SequenceID:  sequenceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get sequence steps", err.Error(), err)
+		return err
+	}
+
+	existing, err := qtx.GetSequenceVariants(ctx, dao.GetSequenceVariantsParams{
+		SequenceID:  sequenceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get sequence variants", err.Error(), err)
+		return err
+	}
+
+	wanted := make(map[uuid.UUID]int32)
+	for _, step := range steps {
+		for _, variant := range variants[step.ExternalID] {
+			wanted[variant.ExternalID] = step.ID
+		}
+	}
+
+	stored := make(map[uuid.UUID]dao.StepVariant, len(existing))
+	for _, variant := range existing {
+		if stepID, ok := wanted[variant.ExternalID]; ok && stepID == variant.StepID {
+			stored[variant.ExternalID] = variant
+			continue
+		}
+
+		if _, err := qtx.DeleteStepVariant(ctx, dao.DeleteStepVariantParams{
+			StepID:      variant.StepID,
+			ExternalID:  variant.ExternalID,
+			WorkspaceID: workspaceID,
+		}); err != nil {
+			slog.Error("failed to delete variant", err.Error(), err)
+			return err
+		}
+	}
+
+	for _, step := range steps {
+		for _, variant := range variants[step.ExternalID] {
+			current, ok := stored[variant.ExternalID]
+			if !ok {
+				if _, err := qtx.RestoreStepVariant(ctx, dao.RestoreStepVariantParams{
+					ExternalID:  variant.ExternalID,
+					StepID:      step.ID,
+					MailSubject: variant.MailSubject,
+					MailContent: variant.MailContent,
+					Weight:      variant.Weight,
+					WorkspaceID: workspaceID,
+				}); err != nil {
+					slog.Error("failed to restore variant", err.Error(), err)
+					return err
+				}
+				continue
+			}
+
+			if current.MailSubject == variant.MailSubject && current.MailContent == variant.MailContent && current.Weight == variant.Weight {
+				continue
+			}
+
+			if _, err := qtx.UpdateStepVariant(ctx, dao.UpdateStepVariantParams{
+				StepID:      step.ID,
+				ExternalID:  variant.ExternalID,
+				MailSubject: variant.MailSubject,
+				MailContent: variant.MailContent,
+				Weight:      variant.Weight,
+				WorkspaceID: workspaceID,
+			}); err != nil {
+				slog.Error("failed to update variant", err.Error(), err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Reorder renumbers the steps of the sequence following the order of stepIDs,
+// which must list every step of the sequence exactly once. A version other
+// than 0 must match the stored one.
+func (r *sequenceRepository) Reorder(ctx context.Context, id uuid.UUID, version int32, stepIDs []uuid.UUID) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	sequence, err := qtx.GetSequenceForUpdate(ctx, dao.GetSequenceForUpdateParams{
+		ExternalID:  id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if version != 0 && version != sequence.Version {
+		return ErrVersionConflict
+	}
+
+	steps, err := qtx.GetSequenceSteps(ctx, dao.GetSequenceStepsParams{
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get sequence steps", err.Error(), err)
+		return err
+	}
+
+	if len(steps) != len(stepIDs) {
+		return ErrStepOrderMismatch
+	}
+
+	if err := qtx.DeferStepNumbers(ctx); err != nil {
+		return err
+	}
+
+	reordered, err := qtx.ReorderSteps(ctx, dao.ReorderStepsParams{
+		StepIds:     stepIDs,
+		SequenceID:  sequence.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to reorder steps", err.Error(), err)
+		return err
+	}
+
+	// ids from other sequences or repeated ids leave steps without a new number
+	if reordered != int64(len(stepIDs)) {
+		return ErrStepOrderMismatch
+	}
+
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          sequence.ID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, sequence.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return ErrStepOrderMismatch
+		}
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+func toSequenceWithSteps(row dao.GetSequenceByIdRow) *models.SequenceWithSteps {
+	steps := make([]*dao.Step, 0)
+
+	if err := json.Unmarshal(row.Steps, &steps); err != nil {
+		slog.Error("failed to unmarshal steps", err.Error(), err)
+	}
+
+	model := &models.SequenceWithSteps{
+		ID:                   row.ID,
+		ExternalID:           row.ExternalID,
+		Name:                 row.SequenceName,
+		OpenTrackingEnabled:  row.OpenTrackingEnabled,
+		ClickTrackingEnabled: row.ClickTrackingEnabled,
+		Created:              row.Created.Time,
+		Variables:            decodeVariables(row.Variables),
+		Status:               row.Status,
+		Version:              row.Version,
+		SourceID:             row.SourceSequenceID,
+		Steps:                steps,
+	}
+
+	if row.Updated.Valid {
+		model.Updated = &row.Updated.Time
+	}
+
+	if row.DeletedAt.Valid {
+		model.Deleted = &row.DeletedAt.Time
+	}
+
+	return model
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern escapes the LIKE wildcards so the value is matched literally.
+func likePattern(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	escaped := likeEscaper.Replace(*value)
+	return &escaped
+}
+
+// timestamp converts t to a timestamp without time zone, in UTC like the ones
+// written by the database.
+func timestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
+
+// uniqueViolation is the postgres error code raised when a unique constraint fails
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err was caused by a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// encodeVariables stores the sequence variables as a JSON object, never null.
+func encodeVariables(variables map[string]string) []byte {
+	if variables == nil {
+		return []byte("{}")
+	}
+
+	raw, _ := json.Marshal(variables)
+	return raw
+}
+
+func decodeVariables(raw []byte) map[string]string {
+	variables := make(map[string]string)
+
+	if err := json.Unmarshal(raw, &variables); err != nil {
+		slog.Error("failed to unmarshal sequence variables", err.Error(), err)
+	}
+
+	return variables
+}
+
+// sameStep reports whether the stored step already holds the content of the
+// model, so replacing the sequence can skip updating it.
+func sameStep(stored, model *dao.Step) bool {
+	return stored.StepNumber == model.StepNumber &&
+		stored.MailSubject == model.MailSubject &&
+		stored.MailContent == model.MailContent &&
+		stored.MailText == model.MailText &&
+		stored.DelayDays == model.DelayDays &&
+		stored.DelayHours == model.DelayHours &&
+		stored.BusinessDaysOnly == model.BusinessDaysOnly &&
+		sameMinutes(stored.SendWindowStart, model.SendWindowStart) &&
+		sameMinutes(stored.SendWindowEnd, model.SendWindowEnd)
+}
+
+func sameMinutes(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
@@ -2,16 +2,20 @@ package repository
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/murilo-bracero/sequence-technical-test/internal/db"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
 )
 
 type StepRepository interface {
 	FindOne(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) (*dao.Step, error)
+	FindPage(ctx context.Context, sequenceID uuid.UUID, afterStepNumber int32, limit int) ([]*dao.Step, error)
 	Create(ctx context.Context, model *dao.Step) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int32) (*dao.Step, error)
 	Update(ctx context.Context, model *dao.Step) error
 }
 
@@ -27,7 +31,11 @@ func NewStepRepository(db db.DB) *stepRepository {
 }
 
 func (r *stepRepository) FindOne(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) (*dao.Step, error) {
-	step, err := r.queries.GetStepById(ctx, dao.GetStepByIdParams{ExternalID: stepID, ExternalID_2: sequenceID})
+	step, err := r.queries.GetStepById(ctx, dao.GetStepByIdParams{
+		ExternalID:   stepID,
+		ExternalID_2: sequenceID,
+		WorkspaceID:  tenancy.WorkspaceID(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -35,32 +43,241 @@ func (r *stepRepository) FindOne(ctx context.Context, sequenceID uuid.UUID, step
 	return &step, nil
 }
 
+// FindPage returns up to limit steps of the sequence ordered by step number,
+// starting after the given step number.
+func (r *stepRepository) FindPage(ctx context.Context, sequenceID uuid.UUID, afterStepNumber int32, limit int) ([]*dao.Step, error) {
+	steps, err := r.queries.GetSequenceStepsPage(ctx, dao.GetSequenceStepsPageParams{
+		ExternalID:  sequenceID,
+		StepNumber:  afterStepNumber,
+		Limit:       int32(limit),
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dao.Step, 0, len(steps))
+	for i := range steps {
+		result = append(result, &steps[i])
+	}
+
+	return result, nil
+}
+
+// Create inserts the step at the position of its step number, shifting the
+// steps from that position onwards.
 func (r *stepRepository) Create(ctx context.Context, model *dao.Step) error {
-	step, err := r.queries.CreateStep(ctx, dao.CreateStepParams{
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	if _, err := qtx.LockSequence(ctx, dao.LockSequenceParams{
+		ID:          model.SequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		return err
+	}
+
+	steps, err := qtx.GetSequenceSteps(ctx, dao.GetSequenceStepsParams{
+		SequenceID:  model.SequenceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		slog.Error("failed to get sequence steps", err.Error(), err)
+		return err
+	}
+
+	// positions past the end are appended, so step numbers never have gaps
+	model.StepNumber = min(model.StepNumber, int32(len(steps))+1)
+
+	if err := qtx.ShiftSteps(ctx, dao.ShiftStepsParams{
 		SequenceID:  model.SequenceID,
-		MailSubject: model.MailSubject,
-		MailContent: model.MailContent,
+		StepNumber:  model.StepNumber,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to shift steps", err.Error(), err)
+		return err
+	}
+
+	step, err := qtx.CreateStep(ctx, dao.CreateStepParams{
+		SequenceID:       model.SequenceID,
+		StepNumber:       model.StepNumber,
+		MailSubject:      model.MailSubject,
+		MailContent:      model.MailContent,
+		DelayDays:        model.DelayDays,
+		DelayHours:       model.DelayHours,
+		BusinessDaysOnly: model.BusinessDaysOnly,
+		SendWindowStart:  model.SendWindowStart,
+		SendWindowEnd:    model.SendWindowEnd,
+		MailText:         model.MailText,
+		WorkspaceID:      workspaceID,
 	})
 	if err != nil {
 		return err
 	}
 
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          model.SequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, model.SequenceID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
 	model.ID = step.ID
 	model.ExternalID = step.ExternalID
+	model.Version = step.Version
 
 	return nil
 }
 
-func (r *stepRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.queries.DeleteStep(ctx, id)
+// Delete removes the step and renumbers the steps after it to close the gap,
+// returning the removed step. Deleting a step that does not exist is a no-op
+// that returns no step. A version other than 0 must match the stored one.
+func (r *stepRepository) Delete(ctx context.Context, id uuid.UUID, version int32) (*dao.Step, error) {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	sequenceID, err := qtx.LockStepSequence(ctx, dao.LockStepSequenceParams{
+		ExternalID:  id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	step, err := qtx.DeleteStep(ctx, dao.DeleteStepParams{
+		ExternalID:  id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != step.Version {
+		return nil, ErrVersionConflict
+	}
+
+	if err := qtx.CloseStepGap(ctx, dao.CloseStepGapParams{
+		SequenceID:  sequenceID,
+		StepNumber:  step.StepNumber,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to renumber steps", err.Error(), err)
+		return nil, err
+	}
+
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          sequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return nil, err
+	}
+
+	if err := createRevision(ctx, qtx, sequenceID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return nil, err
+	}
+
+	return &step, nil
 }
 
+// Update fails with ErrStepNumberTaken when another step of the sequence
+// already has the step number, and with ErrVersionConflict when the step
+// changed since the version of the model was read.
 func (r *stepRepository) Update(ctx context.Context, model *dao.Step) error {
-	_, err := r.queries.UpdateStep(ctx, dao.UpdateStepParams{
-		ExternalID:  model.ExternalID,
-		MailSubject: model.MailSubject,
-		MailContent: model.MailContent,
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	if _, err := qtx.LockSequence(ctx, dao.LockSequenceParams{
+		ID:          model.SequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		return err
+	}
+
+	updated, err := qtx.UpdateStep(ctx, dao.UpdateStepParams{
+		ExternalID:       model.ExternalID,
+		MailSubject:      model.MailSubject,
+		MailContent:      model.MailContent,
+		StepNumber:       model.StepNumber,
+		DelayDays:        model.DelayDays,
+		DelayHours:       model.DelayHours,
+		BusinessDaysOnly: model.BusinessDaysOnly,
+		SendWindowStart:  model.SendWindowStart,
+		SendWindowEnd:    model.SendWindowEnd,
+		MailText:         model.MailText,
+		Version:          model.Version,
+		WorkspaceID:      workspaceID,
 	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrStepNumberTaken
+		}
+		if err == pgx.ErrNoRows {
+			return ErrVersionConflict
+		}
+		return err
+	}
 
-	return err
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          model.SequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, model.SequenceID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	model.Version = updated.Version
+
+	return nil
 }
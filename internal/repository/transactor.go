@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+)
+
+// Transactor runs the calls of several repositories in a single transaction.
+type Transactor interface {
+	// InTx runs fn in a transaction committed when fn succeeds. The repositories
+	// called with the context given to fn join that transaction, and the error of
+	// fn is returned as is.
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db db.DB
+}
+
+var _ Transactor = (*transactor)(nil)
+
+func NewTransactor(db db.DB) *transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.db.InTx(ctx, fn)
+}
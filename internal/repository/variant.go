@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tenancy"
+)
+
+type VariantRepository interface {
+	FindAll(ctx context.Context, stepID int32) ([]*models.StepVariant, error)
+	FindOne(ctx context.Context, stepID int32, variantID uuid.UUID) (*models.StepVariant, error)
+	Create(ctx context.Context, sequenceID int32, model *models.StepVariant) error
+	Update(ctx context.Context, sequenceID int32, model *models.StepVariant) error
+	Delete(ctx context.Context, sequenceID int32, stepID int32, variantID uuid.UUID) error
+	FindAssignment(ctx context.Context, stepID int32, enrollmentID uuid.UUID) (*models.StepVariant, error)
+	Assign(ctx context.Context, stepID int32, enrollmentID uuid.UUID, variantID int32) error
+}
+
+type variantRepository struct {
+	queries *dao.Queries
+	db      db.DB
+}
+
+var _ VariantRepository = (*variantRepository)(nil)
+
+func NewVariantRepository(db db.DB) *variantRepository {
+	return &variantRepository{queries: db.Queries(), db: db}
+}
+
+// FindAll returns the variants of the step in creation order, along with how
+// many enrollments each one was assigned to.
+func (r *variantRepository) FindAll(ctx context.Context, stepID int32) ([]*models.StepVariant, error) {
+	rows, err := r.queries.GetStepVariants(ctx, dao.GetStepVariantsParams{
+		StepID:      stepID,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]*models.StepVariant, 0, len(rows))
+	for _, row := range rows {
+		variants = append(variants, &models.StepVariant{
+			ID:          row.ID,
+			ExternalID:  row.ExternalID,
+			StepID:      row.StepID,
+			MailSubject: row.MailSubject,
+			MailContent: row.MailContent,
+			Weight:      row.Weight,
+			Created:     row.Created.Time,
+			Assignments: row.Assignments,
+		})
+	}
+
+	return variants, nil
+}
+
+func (r *variantRepository) FindOne(ctx context.Context, stepID int32, variantID uuid.UUID) (*models.StepVariant, error) {
+	row, err := r.queries.GetStepVariant(ctx, dao.GetStepVariantParams{
+		StepID:      stepID,
+		ExternalID:  variantID,
+		WorkspaceID: tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StepVariant{
+		ID:          row.ID,
+		ExternalID:  row.ExternalID,
+		StepID:      row.StepID,
+		MailSubject: row.MailSubject,
+		MailContent: row.MailContent,
+		Weight:      row.Weight,
+		Created:     row.Created.Time,
+		Assignments: row.Assignments,
+	}, nil
+}
+
+// Create stores the variant of a step of the sequence, which gets a new
+// version and revision like it does when its steps change.
+func (r *variantRepository) Create(ctx context.Context, sequenceID int32, model *models.StepVariant) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	variant, err := qtx.CreateStepVariant(ctx, dao.CreateStepVariantParams{
+		StepID:      model.StepID,
+		MailSubject: model.MailSubject,
+		MailContent: model.MailContent,
+		Weight:      model.Weight,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          sequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, sequenceID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	model.ID = variant.ID
+	model.ExternalID = variant.ExternalID
+	model.Created = variant.Created.Time
+
+	return nil
+}
+
+func (r *variantRepository) Update(ctx context.Context, sequenceID int32, model *models.StepVariant) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	if _, err := qtx.UpdateStepVariant(ctx, dao.UpdateStepVariantParams{
+		StepID:      model.StepID,
+		ExternalID:  model.ExternalID,
+		MailSubject: model.MailSubject,
+		MailContent: model.MailContent,
+		Weight:      model.Weight,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          sequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, sequenceID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the variant and its assignments, returning pgx.ErrNoRows when
+// the step has no such variant.
+func (r *variantRepository) Delete(ctx context.Context, sequenceID int32, stepID int32, variantID uuid.UUID) error {
+	tx, err := r.db.Tx(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", err.Error(), err)
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	workspaceID := tenancy.WorkspaceID(ctx)
+
+	deleted, err := qtx.DeleteStepVariant(ctx, dao.DeleteStepVariantParams{
+		StepID:      stepID,
+		ExternalID:  variantID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return pgx.ErrNoRows
+	}
+
+	if _, err := qtx.IncrementSequenceVersion(ctx, dao.IncrementSequenceVersionParams{
+		ID:          sequenceID,
+		WorkspaceID: workspaceID,
+	}); err != nil {
+		slog.Error("failed to increment sequence version", err.Error(), err)
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, sequenceID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// FindAssignment returns the variant recorded for the enrollment, or
+// pgx.ErrNoRows when it was not assigned yet.
+func (r *variantRepository) FindAssignment(ctx context.Context, stepID int32, enrollmentID uuid.UUID) (*models.StepVariant, error) {
+	variant, err := r.queries.GetVariantAssignment(ctx, dao.GetVariantAssignmentParams{
+		StepID:       stepID,
+		EnrollmentID: enrollmentID,
+		WorkspaceID:  tenancy.WorkspaceID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StepVariant{
+		ID:          variant.ID,
+		ExternalID:  variant.ExternalID,
+		StepID:      variant.StepID,
+		MailSubject: variant.MailSubject,
+		MailContent: variant.MailContent,
+		Weight:      variant.Weight,
+		Created:     variant.Created.Time,
+	}, nil
+}
+
+// Assign records the variant of the enrollment, keeping the one recorded first
+// when the enrollment was already assigned.
+func (r *variantRepository) Assign(ctx context.Context, stepID int32, enrollmentID uuid.UUID, variantID int32) error {
+	return r.queries.CreateVariantAssignment(ctx, dao.CreateVariantAssignmentParams{
+		EnrollmentID: enrollmentID,
+		StepID:       stepID,
+		VariantID:    variantID,
+		WorkspaceID:  tenancy.WorkspaceID(ctx),
+	})
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/db"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+)
+
+type WorkspaceRepository interface {
+	FindByExternalId(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	FindAll(ctx context.Context) ([]*models.Workspace, error)
+}
+
+type workspaceRepository struct {
+	queries *dao.Queries
+}
+
+var _ WorkspaceRepository = (*workspaceRepository)(nil)
+
+func NewWorkspaceRepository(db db.DB) *workspaceRepository {
+	return &workspaceRepository{queries: db.Queries()}
+}
+
+func (r *workspaceRepository) FindByExternalId(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	row, err := r.queries.GetWorkspaceById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWorkspace(row), nil
+}
+
+func (r *workspaceRepository) FindAll(ctx context.Context) ([]*models.Workspace, error) {
+	rows, err := r.queries.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]*models.Workspace, 0, len(rows))
+	for _, row := range rows {
+		workspaces = append(workspaces, toWorkspace(row))
+	}
+
+	return workspaces, nil
+}
+
+func toWorkspace(row dao.Workspace) *models.Workspace {
+	return &models.Workspace{
+		ID:         row.ID,
+		ExternalID: row.ExternalID,
+		Name:       row.WorkspaceName,
+		Created:    row.Created.Time,
+	}
+}
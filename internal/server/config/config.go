@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cmp"
 	"log/slog"
 	"os"
 
@@ -23,6 +24,36 @@ type Config struct {
 
 	MaxCacheMemory  int
 	CacheLifeWindow int
+
+	TrashRetentionDays int
+	TrashPurgeInterval int
+
+	TrackingBaseURL string
+
+	RequireIfMatch bool
+
+	IdempotencyKeyTTL int
+
+	ValidateRequests  bool
+	ValidateResponses bool
+
+	DefaultWorkspaceID string
+
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval int
+	JWTIssuer           string
+	JWTAudience         string
+	JWTWorkspaceClaim   string
+	JWTRolesClaim       string
+
+	RateLimitEnabled    bool
+	RateLimitPerMinute  int
+	RateLimitBurst      int
+	RateLimitCosts      string
+	RateLimitStore      string
+	RateLimitTrustProxy bool
+	RateLimitMaxBuckets int
 }
 
 func New() *Config {
@@ -45,5 +76,35 @@ func New() *Config {
 
 		CacheLifeWindow: utils.SafeAtoi(os.Getenv("CACHE_LIFE_WINDOW"), 30),
 		MaxCacheMemory:  utils.SafeAtoi(os.Getenv("MAX_CACHE_MEMORY"), 10),
+
+		TrashRetentionDays: utils.SafeAtoi(os.Getenv("TRASH_RETENTION_DAYS"), 30),
+		TrashPurgeInterval: utils.SafeAtoi(os.Getenv("TRASH_PURGE_INTERVAL"), 60),
+
+		TrackingBaseURL: os.Getenv("TRACKING_BASE_URL"),
+
+		RequireIfMatch: os.Getenv("REQUIRE_IF_MATCH") == "true",
+
+		IdempotencyKeyTTL: utils.SafeAtoi(os.Getenv("IDEMPOTENCY_KEY_TTL"), 24),
+
+		ValidateRequests:  os.Getenv("VALIDATE_REQUESTS") != "false",
+		ValidateResponses: os.Getenv("VALIDATE_RESPONSES") == "true",
+
+		DefaultWorkspaceID: cmp.Or(os.Getenv("DEFAULT_WORKSPACE_ID"), "00000000-0000-0000-0000-000000000001"),
+
+		JWKSFile:            os.Getenv("JWT_JWKS_FILE"),
+		JWKSURL:             os.Getenv("JWT_JWKS_URL"),
+		JWKSRefreshInterval: utils.SafeAtoi(os.Getenv("JWT_JWKS_REFRESH_INTERVAL"), 60),
+		JWTIssuer:           os.Getenv("JWT_ISSUER"),
+		JWTAudience:         os.Getenv("JWT_AUDIENCE"),
+		JWTWorkspaceClaim:   cmp.Or(os.Getenv("JWT_WORKSPACE_CLAIM"), "workspace_id"),
+		JWTRolesClaim:       cmp.Or(os.Getenv("JWT_ROLES_CLAIM"), "roles"),
+
+		RateLimitEnabled:    os.Getenv("RATE_LIMIT_ENABLED") != "false",
+		RateLimitPerMinute:  utils.SafeAtoi(os.Getenv("RATE_LIMIT_PER_MINUTE"), 300),
+		RateLimitBurst:      utils.SafeAtoi(os.Getenv("RATE_LIMIT_BURST"), 60),
+		RateLimitCosts:      cmp.Or(os.Getenv("RATE_LIMIT_COSTS"), "GET /sequences=5,GET /sequences/trash=5,GET /sequences/export=10,POST /sequences/import=20"),
+		RateLimitStore:      cmp.Or(os.Getenv("RATE_LIMIT_STORE"), "memory"),
+		RateLimitTrustProxy: os.Getenv("RATE_LIMIT_TRUST_PROXY") == "true",
+		RateLimitMaxBuckets: utils.SafeAtoi(os.Getenv("RATE_LIMIT_MAX_BUCKETS"), 100000),
 	}
 }
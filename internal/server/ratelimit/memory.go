@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+// memoryLimiter keeps the buckets in the memory of the replica, so each
+// replica of the API has quotas of its own. It keeps up to maxBuckets buckets,
+// dropping the least recently used one to make room for a new client, so
+// requests from ever changing clients cannot exhaust the memory.
+type memoryLimiter struct {
+	limits     Limits
+	maxBuckets int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// recent holds the buckets from the most to the least recently used
+	recent *list.List
+}
+
+var _ Limiter = (*memoryLimiter)(nil)
+
+func NewMemoryLimiter(limits Limits, maxBuckets int) *memoryLimiter {
+	return &memoryLimiter{
+		limits:     limits,
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+func (l *memoryLimiter) Take(ctx context.Context, key string, cost int) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	element, ok := l.buckets[key]
+	if ok {
+		l.recent.MoveToFront(element)
+	} else {
+		// a dropped bucket is as good as a full one, so at worst the client
+		// of the least recently used bucket gets its tokens back early
+		if l.recent.Len() >= l.maxBuckets {
+			oldest := l.recent.Back()
+			l.recent.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+
+		element = l.recent.PushFront(&bucket{key: key, tokens: float64(l.limits.Burst), updated: now})
+		l.buckets[key] = element
+	}
+
+	b := element.Value.(*bucket)
+
+	b.tokens = l.limits.refill(b.tokens, now.Sub(b.updated))
+	b.updated = now
+
+	allowed := b.tokens >= float64(cost)
+	if allowed {
+		b.tokens -= float64(cost)
+	}
+
+	return l.limits.result(b.tokens, allowed, cost), nil
+}
+
+func (l *memoryLimiter) Purge(ctx context.Context) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	var purged int64
+	for key, element := range l.buckets {
+		b := element.Value.(*bucket)
+		if l.limits.refill(b.tokens, now.Sub(b.updated)) >= float64(l.limits.Burst) {
+			l.recent.Remove(element)
+			delete(l.buckets, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
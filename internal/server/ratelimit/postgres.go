@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+// postgresLimiter keeps the buckets in the database, so the replicas of the
+// API share the quotas of the clients.
+type postgresLimiter struct {
+	limits              Limits
+	rateLimitRepository repository.RateLimitRepository
+}
+
+var _ Limiter = (*postgresLimiter)(nil)
+
+func NewPostgresLimiter(limits Limits, rateLimitRepository repository.RateLimitRepository) *postgresLimiter {
+	return &postgresLimiter{limits: limits, rateLimitRepository: rateLimitRepository}
+}
+
+func (l *postgresLimiter) Take(ctx context.Context, key string, cost int) (*Result, error) {
+	tokens, allowed, err := l.rateLimitRepository.Take(ctx, key, l.limits.Burst, l.limits.Rate, cost)
+	if err != nil {
+		slog.Error("failed to take rate limit tokens", err.Error(), err)
+		return nil, err
+	}
+
+	return l.limits.result(tokens, allowed, cost), nil
+}
+
+// Purge removes the buckets left alone long enough to be full again.
+func (l *postgresLimiter) Purge(ctx context.Context) (int64, error) {
+	purged, err := l.rateLimitRepository.Purge(ctx, l.limits.fillTime())
+	if err != nil {
+		slog.Error("failed to purge rate limit buckets", err.Error(), err)
+		return 0, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+)
+
+// the stores of the buckets, RATE_LIMIT_STORE
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Limits of the token buckets of the clients: a bucket holds up to Burst
+// tokens and is refilled at Rate tokens per second, each request taking the
+// tokens of its cost.
+type Limits struct {
+	Burst int
+	Rate  float64
+}
+
+// Result of taking tokens from a bucket, for the RateLimit-* headers.
+type Result struct {
+	Allowed bool
+	// Limit is the capacity of the bucket.
+	Limit int
+	// Remaining is how many whole tokens the bucket holds.
+	Remaining int
+	// Reset is how long the bucket takes to refill entirely.
+	Reset time.Duration
+	// RetryAfter is how long a request that was not allowed must wait for
+	// the bucket to hold its cost.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	// Take takes cost tokens from the bucket of key, when it holds them.
+	Take(ctx context.Context, key string, cost int) (*Result, error)
+	// Purge forgets the buckets that are full, which are the same as the
+	// buckets that do not exist yet.
+	Purge(ctx context.Context) (int64, error)
+}
+
+// New returns the limiter of the RATE_LIMIT_STORE store, the buckets of the
+// postgres one being shared by every replica of the API.
+func New(cfg *config.Config, rateLimitRepository repository.RateLimitRepository) (Limiter, error) {
+	limits := Limits{Burst: cfg.RateLimitBurst, Rate: float64(cfg.RateLimitPerMinute) / 60}
+
+	if limits.Burst <= 0 || limits.Rate <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_BURST and RATE_LIMIT_PER_MINUTE must be positive")
+	}
+
+	switch cfg.RateLimitStore {
+	case StoreMemory:
+		if cfg.RateLimitMaxBuckets <= 0 {
+			return nil, fmt.Errorf("RATE_LIMIT_MAX_BUCKETS must be positive")
+		}
+		return NewMemoryLimiter(limits, cfg.RateLimitMaxBuckets), nil
+	case StorePostgres:
+		return NewPostgresLimiter(limits, rateLimitRepository), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q", cfg.RateLimitStore)
+	}
+}
+
+// refill returns the tokens of a bucket that held tokens elapsed ago.
+func (l Limits) refill(tokens float64, elapsed time.Duration) float64 {
+	return min(float64(l.Burst), tokens+elapsed.Seconds()*l.Rate)
+}
+
+// fillTime is how long an empty bucket takes to refill entirely.
+func (l Limits) fillTime() time.Duration {
+	return l.duration(float64(l.Burst))
+}
+
+func (l Limits) result(tokens float64, allowed bool, cost int) *Result {
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     l.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     l.duration(float64(l.Burst) - tokens),
+	}
+
+	if !allowed {
+		result.RetryAfter = l.duration(float64(cost) - tokens)
+	}
+
+	return result
+}
+
+// duration is how long the bucket takes to refill tokens.
+func (l Limits) duration(tokens float64) time.Duration {
+	return time.Duration(max(tokens, 0) / l.Rate * float64(time.Second))
+}
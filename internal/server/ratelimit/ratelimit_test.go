@@ -0,0 +1,196 @@
+package ratelimit_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/config"
+	"github.com/murilo-bracero/sequence-technical-test/internal/server/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// an hour to refill a single token, so the buckets do not refill during the tests
+const slowRate = 1.0 / 3600
+
+const maxBuckets = 100
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		limiter, err := ratelimit.New(&config.Config{RateLimitPerMinute: 60, RateLimitBurst: 10, RateLimitStore: "memory", RateLimitMaxBuckets: maxBuckets}, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, limiter)
+	})
+
+	t.Run("should return error when the store is unknown", func(t *testing.T) {
+		_, err := ratelimit.New(&config.Config{RateLimitPerMinute: 60, RateLimitBurst: 10, RateLimitStore: "redis"}, nil)
+		assert.EqualError(t, err, `unknown RATE_LIMIT_STORE "redis"`)
+	})
+
+	t.Run("should return error when the memory store has no room for buckets", func(t *testing.T) {
+		_, err := ratelimit.New(&config.Config{RateLimitPerMinute: 60, RateLimitBurst: 10, RateLimitStore: "memory"}, nil)
+		assert.EqualError(t, err, "RATE_LIMIT_MAX_BUCKETS must be positive")
+	})
+
+	t.Run("should return error when the limits are not positive", func(t *testing.T) {
+		_, err := ratelimit.New(&config.Config{RateLimitBurst: 10, RateLimitStore: "memory"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryLimiter_Take(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: slowRate}, maxBuckets)
+
+		result, err := limiter.Take(context.Background(), "ip:127.0.0.1", 3)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 5, result.Limit)
+		assert.Equal(t, 2, result.Remaining)
+		assert.InDelta(t, 3*time.Hour, result.Reset, float64(time.Second))
+		assert.Zero(t, result.RetryAfter)
+	})
+
+	t.Run("should refuse the cost the bucket does not hold", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: slowRate}, maxBuckets)
+
+		_, err := limiter.Take(context.Background(), "ip:127.0.0.1", 3)
+		assert.NoError(t, err)
+
+		result, err := limiter.Take(context.Background(), "ip:127.0.0.1", 3)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+		assert.InDelta(t, time.Hour, result.RetryAfter, float64(time.Second))
+
+		// the refused request takes nothing
+		result, err = limiter.Take(context.Background(), "ip:127.0.0.1", 2)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+	})
+
+	t.Run("should keep a bucket per key", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: slowRate}, maxBuckets)
+
+		_, err := limiter.Take(context.Background(), "api-key:1", 5)
+		assert.NoError(t, err)
+
+		result, err := limiter.Take(context.Background(), "api-key:2", 5)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("should drop the least recently used bucket past the maximum", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: slowRate}, 2)
+
+		_, err := limiter.Take(context.Background(), "api-key:1", 5)
+		assert.NoError(t, err)
+
+		_, err = limiter.Take(context.Background(), "api-key:2", 5)
+		assert.NoError(t, err)
+
+		// api-key:1 is used again, so api-key:2 is the one dropped
+		_, err = limiter.Take(context.Background(), "api-key:1", 0)
+		assert.NoError(t, err)
+
+		_, err = limiter.Take(context.Background(), "api-key:3", 5)
+		assert.NoError(t, err)
+
+		result, err := limiter.Take(context.Background(), "api-key:1", 1)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+
+		result, err = limiter.Take(context.Background(), "api-key:2", 1)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("should refill the bucket over time", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: 1000}, maxBuckets)
+
+		_, err := limiter.Take(context.Background(), "ip:127.0.0.1", 5)
+		assert.NoError(t, err)
+
+		time.Sleep(10 * time.Millisecond)
+
+		result, err := limiter.Take(context.Background(), "ip:127.0.0.1", 5)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestMemoryLimiter_Purge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should only purge the full buckets", func(t *testing.T) {
+		limiter := ratelimit.NewMemoryLimiter(ratelimit.Limits{Burst: 5, Rate: slowRate}, maxBuckets)
+
+		_, err := limiter.Take(context.Background(), "ip:127.0.0.1", 0)
+		assert.NoError(t, err)
+
+		_, err = limiter.Take(context.Background(), "ip:127.0.0.2", 1)
+		assert.NoError(t, err)
+
+		purged, err := limiter.Purge(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		// the bucket that was not full is still there
+		result, err := limiter.Take(context.Background(), "ip:127.0.0.2", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.Remaining)
+	})
+}
+
+func TestPostgresLimiter_Take(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		rateLimitRepository := mocks.NewMockRateLimitRepository(ctrl)
+		limiter := ratelimit.NewPostgresLimiter(ratelimit.Limits{Burst: 10, Rate: 1}, rateLimitRepository)
+
+		rateLimitRepository.EXPECT().Take(gomock.Any(), "api-key:1", 10, 1.0, 5).Return(2.5, false, nil)
+
+		result, err := limiter.Take(context.Background(), "api-key:1", 5)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 10, result.Limit)
+		assert.Equal(t, 2, result.Remaining)
+		assert.Equal(t, 7500*time.Millisecond, result.Reset)
+		assert.Equal(t, 2500*time.Millisecond, result.RetryAfter)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		rateLimitRepository := mocks.NewMockRateLimitRepository(ctrl)
+		limiter := ratelimit.NewPostgresLimiter(ratelimit.Limits{Burst: 10, Rate: 1}, rateLimitRepository)
+
+		rateLimitRepository.EXPECT().Take(gomock.Any(), "api-key:1", 10, 1.0, 1).Return(0.0, false, sql.ErrConnDone)
+
+		_, err := limiter.Take(context.Background(), "api-key:1", 1)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestPostgresLimiter_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("should purge the buckets older than the time to refill them", func(t *testing.T) {
+		rateLimitRepository := mocks.NewMockRateLimitRepository(ctrl)
+		limiter := ratelimit.NewPostgresLimiter(ratelimit.Limits{Burst: 60, Rate: 1}, rateLimitRepository)
+
+		rateLimitRepository.EXPECT().Purge(gomock.Any(), time.Minute).Return(int64(3), nil)
+
+		purged, err := limiter.Purge(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func ApiKeyRouter(apiKeyHandler handlers.ApiKeyHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /api-keys", authHandler.Require(auth.ScopeApiKeysManage, apiKeyHandler.GetApiKeys))
+	r.HandleFunc("POST /api-keys", authHandler.Require(auth.ScopeApiKeysManage, apiKeyHandler.CreateApiKey))
+	r.HandleFunc("DELETE /api-keys/{id}", authHandler.Require(auth.ScopeApiKeysManage, apiKeyHandler.RevokeApiKey))
+	r.HandleFunc("POST /api-keys/{id}/rotate", authHandler.Require(auth.ScopeApiKeysManage, apiKeyHandler.RotateApiKey))
+}
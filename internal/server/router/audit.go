@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func AuditRouter(auditHandler handlers.AuditHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /audit-events", authHandler.Require(auth.ScopeAuditRead, auditHandler.GetAuditEvents))
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func BundleRouter(bundleHandler handlers.BundleHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /sequences/export", authHandler.Require(auth.ScopeSequencesRead, bundleHandler.ExportSequences))
+	r.HandleFunc("GET /sequences/{id}/export", authHandler.Require(auth.ScopeSequencesRead, bundleHandler.ExportSequence))
+	r.HandleFunc("POST /sequences/import", authHandler.Require(auth.ScopeSequencesWrite, bundleHandler.ImportSequences))
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func OpenAPIRouter(openAPIHandler handlers.OpenAPIHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /openapi.json", openAPIHandler.GetSpec)
+	r.HandleFunc("GET /docs", openAPIHandler.GetDocs)
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func PreviewRouter(previewHandler handlers.PreviewHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("POST /sequences/{sequence_id}/steps/{step_id}/preview", authHandler.Require(auth.ScopeSequencesRead, previewHandler.PreviewStep))
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func RevisionRouter(revisionHandler handlers.RevisionHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /sequences/{id}/revisions", authHandler.Require(auth.ScopeSequencesRead, revisionHandler.GetRevisions))
+	r.HandleFunc("GET /sequences/{id}/revisions/diff", authHandler.Require(auth.ScopeSequencesRead, revisionHandler.DiffRevisions))
+	r.HandleFunc("GET /sequences/{id}/revisions/{revision}", authHandler.Require(auth.ScopeSequencesRead, revisionHandler.GetRevision))
+	r.HandleFunc("POST /sequences/{id}/revisions/{revision}/rollback", authHandler.Require(auth.ScopeSequencesWrite, revisionHandler.RollbackRevision))
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func RoleBindingRouter(roleBindingHandler handlers.RoleBindingHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /role-bindings", authHandler.Require(auth.ScopeRolesManage, roleBindingHandler.GetRoleBindings))
+	r.HandleFunc("POST /role-bindings", authHandler.Require(auth.ScopeRolesManage, roleBindingHandler.CreateRoleBinding))
+	r.HandleFunc("DELETE /role-bindings/{id}", authHandler.Require(auth.ScopeRolesManage, roleBindingHandler.DeleteRoleBinding))
+}
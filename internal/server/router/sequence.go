@@ -3,12 +3,20 @@ package router
 import (
 	"net/http"
 
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
 	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
 )
 
-func SequenceRouter(sequenceHandler handlers.SequenceHandler, r *http.ServeMux) {
-	r.HandleFunc("GET /sequences", sequenceHandler.GetSequences)
-	r.HandleFunc("GET /sequences/{id}", sequenceHandler.GetSequence)
-	r.HandleFunc("PATCH /sequences/{id}", sequenceHandler.UpdateSequence)
-	r.HandleFunc("POST /sequences", sequenceHandler.CreateSequence)
+func SequenceRouter(sequenceHandler handlers.SequenceHandler, idempotencyHandler handlers.IdempotencyHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /sequences", authHandler.Require(auth.ScopeSequencesRead, sequenceHandler.GetSequences))
+	r.HandleFunc("GET /sequences/trash", authHandler.Require(auth.ScopeSequencesRead, sequenceHandler.GetDeletedSequences))
+	r.HandleFunc("GET /sequences/{id}", authHandler.Require(auth.ScopeSequencesRead, sequenceHandler.GetSequence))
+	r.HandleFunc("PATCH /sequences/{id}", authHandler.Require(auth.ScopeSequencesWrite, sequenceHandler.UpdateSequence))
+	r.HandleFunc("PUT /sequences/{id}", authHandler.Require(auth.ScopeSequencesWrite, sequenceHandler.ReplaceSequence))
+	r.HandleFunc("DELETE /sequences/{id}", authHandler.Require(auth.ScopeSequencesWrite, sequenceHandler.DeleteSequence))
+	r.HandleFunc("POST /sequences/{id}/restore", authHandler.Require(auth.ScopeSequencesWrite, sequenceHandler.RestoreSequence))
+	r.HandleFunc("POST /sequences/{id}/clone", authHandler.Require(auth.ScopeSequencesWrite, idempotencyHandler.Idempotent(sequenceHandler.CloneSequence)))
+	// lifecycle actions such as /sequences/{id}:activate
+	r.HandleFunc("POST /sequences/{id}", authHandler.Require(auth.ScopeSequencesWrite, sequenceHandler.TransitionSequence))
+	r.HandleFunc("POST /sequences", authHandler.Require(auth.ScopeSequencesWrite, idempotencyHandler.Idempotent(sequenceHandler.CreateSequence)))
 }
@@ -3,11 +3,15 @@ package router
 import (
 	"net/http"
 
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
 	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
 )
 
-func StepRouter(stepHandler handlers.StepHandler, r *http.ServeMux) {
-	r.HandleFunc("POST /sequences/{sequence_id}/steps", stepHandler.CreateStep)
-	r.HandleFunc("PATCH /sequences/{sequence_id}/steps/{step_id}", stepHandler.UpdateStep)
-	r.HandleFunc("DELETE /sequences/{sequence_id}/steps/{step_id}", stepHandler.DeleteStep)
+func StepRouter(stepHandler handlers.StepHandler, idempotencyHandler handlers.IdempotencyHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /sequences/{sequence_id}/steps", authHandler.Require(auth.ScopeSequencesRead, stepHandler.GetSteps))
+	r.HandleFunc("GET /sequences/{sequence_id}/steps/{step_id}", authHandler.Require(auth.ScopeSequencesRead, stepHandler.GetStep))
+	r.HandleFunc("POST /sequences/{sequence_id}/steps", authHandler.Require(auth.ScopeStepsWrite, idempotencyHandler.Idempotent(stepHandler.CreateStep)))
+	r.HandleFunc("PUT /sequences/{sequence_id}/steps/order", authHandler.Require(auth.ScopeStepsWrite, stepHandler.ReorderSteps))
+	r.HandleFunc("PATCH /sequences/{sequence_id}/steps/{step_id}", authHandler.Require(auth.ScopeStepsWrite, stepHandler.UpdateStep))
+	r.HandleFunc("DELETE /sequences/{sequence_id}/steps/{step_id}", authHandler.Require(auth.ScopeStepsWrite, stepHandler.DeleteStep))
 }
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/handlers"
+)
+
+func VariantRouter(variantHandler handlers.VariantHandler, authHandler handlers.AuthHandler, r *http.ServeMux) {
+	r.HandleFunc("GET /sequences/{sequence_id}/steps/{step_id}/variants", authHandler.Require(auth.ScopeSequencesRead, variantHandler.GetVariants))
+	r.HandleFunc("POST /sequences/{sequence_id}/steps/{step_id}/variants", authHandler.Require(auth.ScopeStepsWrite, variantHandler.CreateVariant))
+	r.HandleFunc("POST /sequences/{sequence_id}/steps/{step_id}/variants/assignments", authHandler.Require(auth.ScopeStepsWrite, variantHandler.AssignVariant))
+	r.HandleFunc("GET /sequences/{sequence_id}/steps/{step_id}/variants/{variant_id}", authHandler.Require(auth.ScopeSequencesRead, variantHandler.GetVariant))
+	r.HandleFunc("PATCH /sequences/{sequence_id}/steps/{step_id}/variants/{variant_id}", authHandler.Require(auth.ScopeStepsWrite, variantHandler.UpdateVariant))
+	r.HandleFunc("DELETE /sequences/{sequence_id}/steps/{step_id}/variants/{variant_id}", authHandler.Require(auth.ScopeStepsWrite, variantHandler.DeleteVariant))
+}
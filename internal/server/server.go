@@ -13,11 +13,19 @@ import (
 	"github.com/murilo-bracero/sequence-technical-test/internal/server/router"
 )
 
-func Start(cfg *config.Config, db db.DB, sequenceHandler handlers.SequenceHandler, stepHandler handlers.StepHandler) error {
+func Start(cfg *config.Config, db db.DB, sequenceHandler handlers.SequenceHandler, stepHandler handlers.StepHandler, revisionHandler handlers.RevisionHandler, previewHandler handlers.PreviewHandler, variantHandler handlers.VariantHandler, bundleHandler handlers.BundleHandler, idempotencyHandler handlers.IdempotencyHandler, openAPIHandler handlers.OpenAPIHandler, validationHandler handlers.ValidationHandler, workspaceHandler handlers.WorkspaceHandler, apiKeyHandler handlers.ApiKeyHandler, roleBindingHandler handlers.RoleBindingHandler, auditHandler handlers.AuditHandler, authHandler handlers.AuthHandler, rateLimitHandler handlers.RateLimitHandler, requestIDHandler handlers.RequestIDHandler) error {
 	r := http.NewServeMux()
 
-	router.SequenceRouter(sequenceHandler, r)
-	router.StepRouter(stepHandler, r)
+	router.SequenceRouter(sequenceHandler, idempotencyHandler, authHandler, r)
+	router.StepRouter(stepHandler, idempotencyHandler, authHandler, r)
+	router.RevisionRouter(revisionHandler, authHandler, r)
+	router.PreviewRouter(previewHandler, authHandler, r)
+	router.VariantRouter(variantHandler, authHandler, r)
+	router.BundleRouter(bundleHandler, authHandler, r)
+	router.ApiKeyRouter(apiKeyHandler, authHandler, r)
+	router.RoleBindingRouter(roleBindingHandler, authHandler, r)
+	router.AuditRouter(auditHandler, authHandler, r)
+	router.OpenAPIRouter(openAPIHandler, r)
 
 	r.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		res := make(map[string]string)
@@ -49,7 +57,7 @@ func Start(cfg *config.Config, db db.DB, sequenceHandler handlers.SequenceHandle
 	}
 
 	slog.Info("Starting server", "port", port)
-	if err := http.ListenAndServe(port, r); err != nil {
+	if err := http.ListenAndServe(port, requestIDHandler.Identify(rateLimitHandler.Limit(authHandler.Authenticate(validationHandler.Validate(workspaceHandler.Resolve(r)))))); err != nil {
 		slog.Error("failed to start server", err.Error(), err)
 		return err
 	}
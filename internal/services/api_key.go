@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+// apiKeyTouchInterval is how stale the last use of a key may get, so a key in
+// constant use is not written on every request.
+const apiKeyTouchInterval = time.Minute
+
+type ApiKeyService interface {
+	CreateApiKey(ctx context.Context, req dto.CreateApiKeyRequest) (*dto.ApiKeyResponse, error)
+	GetApiKeys(ctx context.Context) ([]*dto.ApiKeyResponse, error)
+	RevokeApiKey(ctx context.Context, id uuid.UUID) error
+	RotateApiKey(ctx context.Context, id uuid.UUID) (*dto.ApiKeyResponse, error)
+	Authenticate(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepository repository.ApiKeyRepository
+}
+
+func NewApiKeyService(apiKeyRepository repository.ApiKeyRepository) ApiKeyService {
+	return &apiKeyService{apiKeyRepository: apiKeyRepository}
+}
+
+// CreateApiKey creates a key of the workspace of the request. An authenticated
+// caller can only grant the scopes it has itself.
+func (s *apiKeyService) CreateApiKey(ctx context.Context, req dto.CreateApiKeyRequest) (*dto.ApiKeyResponse, error) {
+	if principal := auth.PrincipalFrom(ctx); principal != nil {
+		for _, scope := range req.Scopes {
+			if !principal.HasScope(scope) {
+				return nil, ErrorScopeNotGranted
+			}
+		}
+	}
+
+	generated, err := auth.NewApiKey()
+	if err != nil {
+		slog.Error("failed to generate api key", err.Error(), err)
+		return nil, err
+	}
+
+	key := &models.ApiKey{
+		Name:   req.Name,
+		Prefix: generated.Prefix,
+		Hash:   generated.Hash,
+		Scopes: req.Scopes,
+	}
+
+	if err := s.apiKeyRepository.Create(ctx, key); err != nil {
+		slog.Error("failed to create api key", err.Error(), err)
+		return nil, err
+	}
+
+	response := toApiKeyResponse(key)
+	response.Key = generated.Key
+
+	return response, nil
+}
+
+func (s *apiKeyService) GetApiKeys(ctx context.Context) ([]*dto.ApiKeyResponse, error) {
+	keys, err := s.apiKeyRepository.FindAll(ctx)
+	if err != nil {
+		slog.Error("failed to get api keys", err.Error(), err)
+		return nil, err
+	}
+
+	response := make([]*dto.ApiKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toApiKeyResponse(key))
+	}
+
+	return response, nil
+}
+
+func (s *apiKeyService) RevokeApiKey(ctx context.Context, id uuid.UUID) error {
+	if err := s.apiKeyRepository.Revoke(ctx, id); err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrorApiKeyNotFound
+		}
+		slog.Error("failed to revoke api key", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// RotateApiKey replaces the secret of the key, keeping its name and scopes.
+// The previous secret stops working right away.
+func (s *apiKeyService) RotateApiKey(ctx context.Context, id uuid.UUID) (*dto.ApiKeyResponse, error) {
+	generated, err := auth.NewApiKey()
+	if err != nil {
+		slog.Error("failed to generate api key", err.Error(), err)
+		return nil, err
+	}
+
+	key := &models.ApiKey{
+		ExternalID: id,
+		Prefix:     generated.Prefix,
+		Hash:       generated.Hash,
+	}
+
+	if err := s.apiKeyRepository.Rotate(ctx, key); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorApiKeyNotFound
+		}
+		slog.Error("failed to rotate api key", err.Error(), err)
+		return nil, err
+	}
+
+	response := toApiKeyResponse(key)
+	response.Key = generated.Key
+
+	return response, nil
+}
+
+// Authenticate returns the principal of key, recording that the key was used.
+// Unknown, revoked and mismatching keys are all ErrorInvalidApiKey, so the
+// callers cannot tell them apart.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	prefix, secret, ok := auth.ParseApiKey(key)
+	if !ok {
+		return nil, ErrorInvalidApiKey
+	}
+
+	apiKey, err := s.apiKeyRepository.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorInvalidApiKey
+		}
+		slog.Error("failed to get api key", err.Error(), err)
+		return nil, err
+	}
+
+	if !auth.VerifySecret(apiKey.Hash, secret) {
+		return nil, ErrorInvalidApiKey
+	}
+
+	// failing to record the use is no reason to fail the request
+	if err := s.apiKeyRepository.Touch(ctx, apiKey.ID, time.Now().UTC().Add(-apiKeyTouchInterval)); err != nil {
+		slog.Error("failed to record api key use", err.Error(), err)
+	}
+
+	return &auth.Principal{
+		Subject:     "api-key:" + apiKey.ExternalID.String(),
+		WorkspaceID: apiKey.WorkspaceExternalID,
+		Scopes:      apiKey.Scopes,
+	}, nil
+}
+
+func toApiKeyResponse(key *models.ApiKey) *dto.ApiKeyResponse {
+	response := &dto.ApiKeyResponse{
+		ExternalID: key.ExternalID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.Created.Format(time.RFC3339),
+	}
+
+	if key.LastUsed != nil {
+		lastUsed := key.LastUsed.Format(time.RFC3339)
+		response.LastUsedAt = &lastUsed
+	}
+
+	if key.Revoked != nil {
+		revoked := key.Revoked.Format(time.RFC3339)
+		response.RevokedAt = &revoked
+	}
+
+	return response
+}
@@ -0,0 +1,258 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApiKeyService_CreateApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		var stored *models.ApiKey
+		apiKeyRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *models.ApiKey) error {
+			key.ExternalID = uuid.New()
+			key.Created = time.Now()
+			stored = key
+			return nil
+		})
+
+		res, err := apiKeyService.CreateApiKey(context.Background(), dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{auth.ScopeSequencesRead}})
+		assert.NoError(t, err)
+		assert.Equal(t, "ci", res.Name)
+		assert.Equal(t, []string{auth.ScopeSequencesRead}, res.Scopes)
+		assert.True(t, strings.HasPrefix(res.Key, "sk_"+res.Prefix+"_"))
+
+		_, secret, _ := auth.ParseApiKey(res.Key)
+		assert.True(t, auth.VerifySecret(stored.Hash, secret))
+		assert.NotContains(t, stored.Hash, secret)
+	})
+
+	t.Run("return services.ErrorScopeNotGranted when caller lacks a requested scope", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Scopes: []string{auth.ScopeApiKeysManage, auth.ScopeSequencesRead}})
+
+		res, err := apiKeyService.CreateApiKey(ctx, dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{auth.ScopeSequencesWrite}})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorScopeNotGranted.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		res, err := apiKeyService.CreateApiKey(context.Background(), dto.CreateApiKeyRequest{Name: "ci", Scopes: []string{auth.ScopeSequencesRead}})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestApiKeyService_GetApiKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		lastUsed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		apiKeyRepository.EXPECT().FindAll(gomock.Any()).Return([]*models.ApiKey{
+			{ExternalID: uuid.New(), Name: "ci", Prefix: "abc", Hash: "hash", Scopes: []string{auth.ScopeSequencesRead}, LastUsed: &lastUsed},
+		}, nil)
+
+		res, err := apiKeyService.GetApiKeys(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "abc", res[0].Prefix)
+		assert.Empty(t, res[0].Key)
+		assert.Equal(t, "2025-01-02T03:04:05Z", *res[0].LastUsedAt)
+		assert.Nil(t, res[0].RevokedAt)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindAll(gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		res, err := apiKeyService.GetApiKeys(context.Background())
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestApiKeyService_RevokeApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		id := uuid.New()
+		apiKeyRepository.EXPECT().Revoke(gomock.Any(), id).Return(nil)
+
+		assert.NoError(t, apiKeyService.RevokeApiKey(context.Background(), id))
+	})
+
+	t.Run("return services.ErrorApiKeyNotFound when key does not exist", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().Revoke(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+		err := apiKeyService.RevokeApiKey(context.Background(), uuid.New())
+		assert.EqualError(t, err, services.ErrorApiKeyNotFound.Error())
+	})
+}
+
+func TestApiKeyService_RotateApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		id := uuid.New()
+		var stored *models.ApiKey
+		apiKeyRepository.EXPECT().Rotate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *models.ApiKey) error {
+			assert.Equal(t, id, key.ExternalID)
+			key.Name = "ci"
+			key.Scopes = []string{auth.ScopeSequencesRead}
+			stored = key
+			return nil
+		})
+
+		res, err := apiKeyService.RotateApiKey(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, "ci", res.Name)
+
+		_, secret, ok := auth.ParseApiKey(res.Key)
+		assert.True(t, ok)
+		assert.True(t, auth.VerifySecret(stored.Hash, secret))
+	})
+
+	t.Run("return services.ErrorApiKeyNotFound when key does not exist", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().Rotate(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+		res, err := apiKeyService.RotateApiKey(context.Background(), uuid.New())
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorApiKeyNotFound.Error())
+	})
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	generated, err := auth.NewApiKey()
+	assert.NoError(t, err)
+
+	stored := &models.ApiKey{
+		ID:                  7,
+		ExternalID:          uuid.New(),
+		WorkspaceExternalID: uuid.New(),
+		Prefix:              generated.Prefix,
+		Hash:                generated.Hash,
+		Scopes:              []string{auth.ScopeSequencesRead},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindByPrefix(gomock.Any(), generated.Prefix).Return(stored, nil)
+		apiKeyRepository.EXPECT().Touch(gomock.Any(), int32(7), gomock.Any()).DoAndReturn(func(_ context.Context, _ int32, usedBefore time.Time) error {
+			assert.WithinDuration(t, time.Now().Add(-time.Minute), usedBefore, time.Second)
+			return nil
+		})
+
+		principal, err := apiKeyService.Authenticate(context.Background(), generated.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, "api-key:"+stored.ExternalID.String(), principal.Subject)
+		assert.Equal(t, stored.WorkspaceExternalID, principal.WorkspaceID)
+		assert.True(t, principal.HasScope(auth.ScopeSequencesRead))
+	})
+
+	t.Run("should authenticate even when the use cannot be recorded", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindByPrefix(gomock.Any(), generated.Prefix).Return(stored, nil)
+		apiKeyRepository.EXPECT().Touch(gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		principal, err := apiKeyService.Authenticate(context.Background(), generated.Key)
+		assert.NoError(t, err)
+		assert.NotNil(t, principal)
+	})
+
+	t.Run("return services.ErrorInvalidApiKey when key is malformed", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		principal, err := apiKeyService.Authenticate(context.Background(), "not-a-key")
+
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, services.ErrorInvalidApiKey.Error())
+	})
+
+	t.Run("return services.ErrorInvalidApiKey when key does not exist", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindByPrefix(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+		principal, err := apiKeyService.Authenticate(context.Background(), generated.Key)
+
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, services.ErrorInvalidApiKey.Error())
+	})
+
+	t.Run("return services.ErrorInvalidApiKey when secret does not match", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindByPrefix(gomock.Any(), generated.Prefix).Return(stored, nil)
+
+		principal, err := apiKeyService.Authenticate(context.Background(), "sk_"+generated.Prefix+"_wrong")
+
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, services.ErrorInvalidApiKey.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		apiKeyRepository := mocks.NewMockApiKeyRepository(ctrl)
+		apiKeyService := services.NewApiKeyService(apiKeyRepository)
+
+		apiKeyRepository.EXPECT().FindByPrefix(gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		principal, err := apiKeyService.Authenticate(context.Background(), generated.Key)
+
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
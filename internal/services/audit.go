@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+)
+
+type AuditService interface {
+	GetAuditEvents(ctx context.Context, req dto.AuditEventPageRequest) (*dto.AuditEventPageResponse, error)
+}
+
+const auditCursorSort = "id"
+
+// auditService reads the audit log of the workspace, which only its admins
+// may do. The events are written by the services making the changes, see
+// auditor.
+type auditService struct {
+	auditRepository repository.AuditRepository
+	authorizer      authorizer
+}
+
+func NewAuditService(auditRepository repository.AuditRepository, roleBindingRepository repository.RoleBindingRepository) AuditService {
+	return &auditService{auditRepository: auditRepository, authorizer: authorizer{roleBindingRepository: roleBindingRepository}}
+}
+
+// GetAuditEvents returns a page of the events matching the request, newest
+// first.
+func (s *auditService) GetAuditEvents(ctx context.Context, req dto.AuditEventPageRequest) (*dto.AuditEventPageResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	filter := models.AuditEventFilter{
+		Actor:         req.Actor,
+		Action:        req.Action,
+		TargetType:    req.TargetType,
+		TargetID:      req.TargetID,
+		RequestID:     req.RequestID,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	}
+
+	var cursor *utils.Cursor
+
+	if req.Cursor != "" {
+		c, err := utils.DecodeCursor(req.Cursor)
+		if err != nil || c.Sort != auditCursorSort || c.Backward {
+			return nil, ErrorInvalidCursor
+		}
+		cursor = c
+	}
+
+	// fetches one extra event to know if there is another page after this one
+	events, err := s.auditRepository.FindPage(ctx, filter, cursor, req.Limit+1)
+	if err != nil {
+		slog.Error("failed to get audit events page", err.Error(), err)
+		return nil, err
+	}
+
+	hasMore := len(events) > req.Limit
+	if hasMore {
+		events = events[:req.Limit]
+	}
+
+	response := &dto.AuditEventPageResponse{
+		Items: make([]*dto.AuditEventResponse, 0, len(events)),
+	}
+
+	for _, event := range events {
+		response.Items = append(response.Items, toAuditEventResponse(event))
+	}
+
+	if hasMore {
+		last := events[len(events)-1]
+		next := utils.EncodeCursor(utils.Cursor{Sort: auditCursorSort, Value: strconv.Itoa(int(last.ID)), ID: last.ID})
+		response.NextCursor = &next
+	}
+
+	return response, nil
+}
+
+func toAuditEventResponse(event *models.AuditEvent) *dto.AuditEventResponse {
+	return &dto.AuditEventResponse{
+		ExternalID: event.ExternalID.String(),
+		Actor:      event.Actor,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID.String(),
+		Before:     event.Before,
+		After:      event.After,
+		RequestID:  event.RequestID,
+		CreatedAt:  event.Created.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditService_GetAuditEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		auditService := services.NewAuditService(auditRepository, mocks.NewMockRoleBindingRepository(ctrl))
+
+		targetID := uuid.New()
+		action := "update"
+
+		auditRepository.EXPECT().FindPage(gomock.Any(), models.AuditEventFilter{Action: &action}, nil, 3).Return([]*models.AuditEvent{
+			{ID: 3, ExternalID: uuid.New(), Actor: "api-key:1", Action: "update", TargetType: "sequence", TargetID: targetID, Before: json.RawMessage(`{"name":"old"}`), After: json.RawMessage(`{"name":"new"}`), Created: time.Now()},
+			{ID: 2, ExternalID: uuid.New(), Actor: "api-key:1", Action: "update", TargetType: "sequence", TargetID: targetID, Created: time.Now()},
+			{ID: 1, ExternalID: uuid.New(), Actor: "api-key:1", Action: "update", TargetType: "sequence", TargetID: targetID, Created: time.Now()},
+		}, nil)
+
+		res, err := auditService.GetAuditEvents(context.Background(), dto.AuditEventPageRequest{Action: &action, Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 2)
+		assert.Equal(t, targetID.String(), res.Items[0].TargetID)
+		assert.JSONEq(t, `{"name":"old"}`, string(res.Items[0].Before))
+		assert.JSONEq(t, `{"name":"new"}`, string(res.Items[0].After))
+
+		cursor, err := utils.DecodeCursor(*res.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), cursor.ID)
+	})
+
+	t.Run("should not return a next cursor on the last page", func(t *testing.T) {
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		auditService := services.NewAuditService(auditRepository, mocks.NewMockRoleBindingRepository(ctrl))
+
+		cursor := utils.Cursor{Sort: "id", Value: "5", ID: 5}
+
+		auditRepository.EXPECT().FindPage(gomock.Any(), models.AuditEventFilter{}, &cursor, 3).Return([]*models.AuditEvent{
+			{ID: 4, ExternalID: uuid.New(), TargetID: uuid.New(), Created: time.Now()},
+		}, nil)
+
+		res, err := auditService.GetAuditEvents(context.Background(), dto.AuditEventPageRequest{Cursor: utils.EncodeCursor(cursor), Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 1)
+		assert.Nil(t, res.NextCursor)
+	})
+
+	t.Run("return services.ErrorInvalidCursor when the cursor is not an audit cursor", func(t *testing.T) {
+		auditService := services.NewAuditService(mocks.NewMockAuditRepository(ctrl), mocks.NewMockRoleBindingRepository(ctrl))
+
+		res, err := auditService.GetAuditEvents(context.Background(), dto.AuditEventPageRequest{
+			Cursor: utils.EncodeCursor(utils.Cursor{Sort: "stepNumber", Value: "1", ID: 1}),
+			Limit:  2,
+		})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorInvalidCursor.Error())
+	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user is not an admin", func(t *testing.T) {
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		auditService := services.NewAuditService(mocks.NewMockAuditRepository(ctrl), roleBindingRepository)
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"editor"}})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", nil, nil).Return([]models.Role{models.RoleEditor}, nil)
+
+		res, err := auditService.GetAuditEvents(ctx, dto.AuditEventPageRequest{Limit: 2})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		auditService := services.NewAuditService(auditRepository, mocks.NewMockRoleBindingRepository(ctrl))
+
+		auditRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), nil, 3).Return(nil, sql.ErrConnDone)
+
+		res, err := auditService.GetAuditEvents(context.Background(), dto.AuditEventPageRequest{Limit: 2})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tracing"
+)
+
+// the targets of the audit events
+const (
+	auditTargetSequence = "sequence"
+	auditTargetStep     = "step"
+	auditTargetVariant  = "variant"
+)
+
+// the actions of the audit events, sequence transitions are recorded under the
+// name of their action
+const (
+	auditActionCreate   = "create"
+	auditActionUpdate   = "update"
+	auditActionReplace  = "replace"
+	auditActionDelete   = "delete"
+	auditActionRestore  = "restore"
+	auditActionClone    = "clone"
+	auditActionReorder  = "reorder"
+	auditActionRollback = "rollback"
+	auditActionImport   = "import"
+	auditActionPurge    = "purge"
+)
+
+// auditActor is the actor of the changes made without a principal, such as
+// the ones of the jobs.
+const auditActor = "system"
+
+// auditor records who changed what in the audit log. A change and its event
+// are written in a single transaction, so there is never a change without an
+// event nor an event without its change.
+type auditor struct {
+	transactor      repository.Transactor
+	auditRepository repository.AuditRepository
+}
+
+// inTx runs change in a transaction, the repositories called with the context
+// given to it, record included, join that transaction.
+func (a auditor) inTx(ctx context.Context, change func(ctx context.Context) error) error {
+	return a.transactor.InTx(ctx, change)
+}
+
+// record appends the event of action on the target, before and after being the
+// target as returned by the API around the change, nil when it did not exist.
+func (a auditor) record(ctx context.Context, action string, targetType string, targetID uuid.UUID, before any, after any) error {
+	actor := auditActor
+	if principal := auth.PrincipalFrom(ctx); principal != nil {
+		actor = principal.Subject
+	}
+
+	return a.recordAs(ctx, actor, action, targetType, targetID, before, after)
+}
+
+// recordSystem appends the event of a change made by the API itself, such as
+// the ones of the jobs, whatever the principal of ctx.
+func (a auditor) recordSystem(ctx context.Context, action string, targetType string, targetID uuid.UUID, before any, after any) error {
+	return a.recordAs(ctx, auditActor, action, targetType, targetID, before, after)
+}
+
+func (a auditor) recordAs(ctx context.Context, actor string, action string, targetType string, targetID uuid.UUID, before any, after any) error {
+	event := &models.AuditEvent{
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+
+	if requestID := tracing.RequestID(ctx); requestID != "" {
+		event.RequestID = &requestID
+	}
+
+	var err error
+
+	if event.Before, err = auditState(before); err != nil {
+		slog.Error("failed to encode audit event", err.Error(), err)
+		return err
+	}
+
+	if event.After, err = auditState(after); err != nil {
+		slog.Error("failed to encode audit event", err.Error(), err)
+		return err
+	}
+
+	if err := a.auditRepository.Append(ctx, event); err != nil {
+		slog.Error("failed to append audit event", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// auditState encodes the state of a target, leaving out the missing ones.
+func auditState(state any) (json.RawMessage, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	return raw, nil
+}
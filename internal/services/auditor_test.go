@@ -0,0 +1,302 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newTransactor returns a transactor running the changes straight away, as the
+// repositories are mocked there is no transaction to begin.
+func newTransactor(ctrl *gomock.Controller) *mocks.MockTransactor {
+	transactor := mocks.NewMockTransactor(ctrl)
+	transactor.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return transactor
+}
+
+// newAuditRepository returns an audit repository accepting every event, for
+// the tests that do not check the audit log.
+func newAuditRepository(ctrl *gomock.Controller) *mocks.MockAuditRepository {
+	auditRepository := mocks.NewMockAuditRepository(ctrl)
+	auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return auditRepository
+}
+
+func TestAuditor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("should record the actor, the request and the state after a creation", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "api-key:1"})
+		ctx = tracing.WithRequestID(ctx, "request-1")
+
+		sequenceRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.SequenceWithSteps) error {
+			model.ExternalID = sequenceID
+			return nil
+		})
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := sequenceService.CreateSequence(ctx, dto.CreateSequenceRequest{Name: "name"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "api-key:1", event.Actor)
+		assert.Equal(t, "create", event.Action)
+		assert.Equal(t, "sequence", event.TargetType)
+		assert.Equal(t, sequenceID, event.TargetID)
+		assert.Equal(t, "request-1", *event.RequestID)
+		assert.Nil(t, event.Before)
+
+		var after dto.SequenceResponse
+		assert.NoError(t, json.Unmarshal(event.After, &after))
+		assert.Equal(t, "name", after.Name)
+	})
+
+	t.Run("should record the changes without a principal as made by the system", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := sequenceService.CreateSequence(context.Background(), dto.CreateSequenceRequest{Name: "name"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "system", event.Actor)
+		assert.Nil(t, event.RequestID)
+	})
+
+	t.Run("should record the state before a deletion", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name"}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "delete", event.Action)
+		assert.Nil(t, event.After)
+
+		var before dto.SequenceResponse
+		assert.NoError(t, json.Unmarshal(event.Before, &before))
+		assert.Equal(t, "name", before.Name)
+	})
+
+	t.Run("should record the state before and after a step update", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		stepService := services.NewStepService(mocks.NewMockSequenceRepository(ctrl), stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID, stepID := uuid.New(), uuid.New()
+		subject := "new subject"
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID, StepNumber: 1, MailSubject: "old subject"}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, dto.UpdateStepRequest{MailSubject: &subject})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "update", event.Action)
+		assert.Equal(t, "step", event.TargetType)
+		assert.Equal(t, stepID, event.TargetID)
+
+		var before, after dto.StepResponse
+		assert.NoError(t, json.Unmarshal(event.Before, &before))
+		assert.NoError(t, json.Unmarshal(event.After, &after))
+		assert.Equal(t, "old subject", before.MailSubject)
+		assert.Equal(t, "new subject", after.MailSubject)
+	})
+
+	t.Run("should record the trashed state before a restore", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID := uuid.New()
+		deletedAt := time.Now()
+
+		sequenceRepository.EXPECT().FindDeletedByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name", Deleted: &deletedAt}, nil)
+		sequenceRepository.EXPECT().Restore(gomock.Any(), sequenceID).Return(nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name"}, nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := sequenceService.RestoreSequence(context.Background(), sequenceID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "restore", event.Action)
+
+		var before, after dto.SequenceResponse
+		assert.NoError(t, json.Unmarshal(event.Before, &before))
+		assert.NoError(t, json.Unmarshal(event.After, &after))
+		assert.NotNil(t, before.DeletedAt)
+		assert.Nil(t, after.DeletedAt)
+	})
+
+	t.Run("should record the purged sequences as removed by the system", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "api-key:1"})
+
+		sequenceRepository.EXPECT().Purge(gomock.Any(), time.Hour).Return([]*models.SequenceWithSteps{{ExternalID: sequenceID, Name: "name"}}, nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := sequenceService.PurgeDeletedSequences(ctx, time.Hour)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "system", event.Actor)
+		assert.Equal(t, "purge", event.Action)
+		assert.Equal(t, sequenceID, event.TargetID)
+		assert.NotNil(t, event.Before)
+		assert.Nil(t, event.After)
+	})
+
+	t.Run("should record the state before and after a rollback", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{Revision: 1, Snapshot: models.SequenceSnapshot{Name: "old name"}}, nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ExternalID: sequenceID, Name: "new name"}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Return(nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := revisionService.RollbackRevision(context.Background(), sequenceID, 1)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "rollback", event.Action)
+		assert.Equal(t, "sequence", event.TargetType)
+
+		var before, after dto.SequenceResponse
+		assert.NoError(t, json.Unmarshal(event.Before, &before))
+		assert.NoError(t, json.Unmarshal(event.After, &after))
+		assert.Equal(t, "new name", before.Name)
+		assert.Equal(t, "old name", after.Name)
+	})
+
+	t.Run("should record the state before and after a variant update", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		variantID := uuid.New()
+		subject := "new subject"
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1, SequenceID: 7}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), variantID).Return(&models.StepVariant{ID: 1, ExternalID: variantID, StepID: 1, MailSubject: "old subject"}, nil)
+		variantRepository.EXPECT().Update(gomock.Any(), int32(7), gomock.Any()).Return(nil)
+
+		var event *models.AuditEvent
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.AuditEvent) error {
+			event = model
+			return nil
+		})
+
+		_, err := variantService.UpdateVariant(context.Background(), uuid.New(), uuid.New(), variantID, dto.UpdateVariantRequest{MailSubject: &subject})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "update", event.Action)
+		assert.Equal(t, "variant", event.TargetType)
+		assert.Equal(t, variantID, event.TargetID)
+
+		var before, after dto.VariantResponse
+		assert.NoError(t, json.Unmarshal(event.Before, &before))
+		assert.NoError(t, json.Unmarshal(event.After, &after))
+		assert.Equal(t, "old subject", before.MailSubject)
+		assert.Equal(t, "new subject", after.MailSubject)
+	})
+
+	t.Run("should not record the deletion of a step that does not exist", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(mocks.NewMockSequenceRepository(ctrl), stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), mocks.NewMockAuditRepository(ctrl))
+
+		stepID := uuid.New()
+
+		stepRepository.EXPECT().Delete(gomock.Any(), stepID, int32(0)).Return(nil, nil)
+
+		err := stepService.DeleteStep(context.Background(), stepID, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return general error when the event cannot be appended", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		auditRepository := mocks.NewMockAuditRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), auditRepository)
+
+		sequenceRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		auditRepository.EXPECT().Append(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := sequenceService.CreateSequence(context.Background(), dto.CreateSequenceRequest{Name: "name"})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+// authorizer checks the role of the users before the services act for them.
+// The role of a user is the highest of the roles of their token and of their
+// role bindings in the workspace and in the sequence at hand. API keys are
+// limited by their scopes only, and calls without a principal, such as the
+// jobs, are not limited at all.
+type authorizer struct {
+	roleBindingRepository repository.RoleBindingRepository
+}
+
+func (a authorizer) authorizeWorkspace(ctx context.Context, required models.Role) error {
+	return a.authorize(ctx, required, nil, nil)
+}
+
+func (a authorizer) authorizeSequence(ctx context.Context, required models.Role, sequenceID uuid.UUID) error {
+	return a.authorize(ctx, required, &sequenceID, nil)
+}
+
+func (a authorizer) authorizeStep(ctx context.Context, required models.Role, stepID uuid.UUID) error {
+	return a.authorize(ctx, required, nil, &stepID)
+}
+
+func (a authorizer) authorize(ctx context.Context, required models.Role, sequenceID *uuid.UUID, stepID *uuid.UUID) error {
+	principal := auth.PrincipalFrom(ctx)
+	if principal == nil || principal.UserID == "" {
+		return nil
+	}
+
+	for _, role := range principal.Roles {
+		if models.Role(role).Includes(required) {
+			return nil
+		}
+	}
+
+	roles, err := a.roleBindingRepository.FindRoles(ctx, principal.UserID, sequenceID, stepID)
+	if err != nil {
+		slog.Error("failed to get user roles", err.Error(), err)
+		return err
+	}
+
+	for _, role := range roles {
+		if role.Includes(required) {
+			return nil
+		}
+	}
+
+	return ErrorRoleNotGranted
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+// renameCandidates is how many free names are looked up at once when renaming
+// an imported sequence.
+const renameCandidates = 10
+
+type BundleService interface {
+	ExportSequences(ctx context.Context, ids []uuid.UUID) (*dto.SequenceBundle, error)
+	ImportSequences(ctx context.Context, bundle dto.SequenceBundle, opts dto.ImportOptions) (*dto.ImportResponse, error)
+}
+
+type bundleService struct {
+	sequenceRepository repository.SequenceRepository
+	authorizer         authorizer
+	auditor            auditor
+}
+
+func NewBundleService(sequenceRepository repository.SequenceRepository, roleBindingRepository repository.RoleBindingRepository, transactor repository.Transactor, auditRepository repository.AuditRepository) BundleService {
+	return &bundleService{
+		sequenceRepository: sequenceRepository,
+		authorizer:         authorizer{roleBindingRepository: roleBindingRepository},
+		auditor:            auditor{transactor: transactor, auditRepository: auditRepository},
+	}
+}
+
+// ExportSequences bundles the sequences in the order of ids, failing with
+// ErrorSequenceNotFound when any of them is not found. The variants of the
+// steps are left out of the bundle. Every sequence is authorized before any
+// is looked up, so the sequences the caller may not read are not told apart
+// from the missing ones.
+func (s *bundleService) ExportSequences(ctx context.Context, ids []uuid.UUID) (*dto.SequenceBundle, error) {
+	for _, id := range ids {
+		if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, id); err != nil {
+			return nil, err
+		}
+	}
+
+	sequences, err := s.sequenceRepository.FindByExternalIds(ctx, ids)
+	if err != nil {
+		slog.Error("failed to get sequences during exportSequences", err.Error(), err)
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.SequenceWithSteps, len(sequences))
+	for _, sequence := range sequences {
+		byID[sequence.ExternalID] = sequence
+	}
+
+	bundle := &dto.SequenceBundle{
+		Version:    dto.BundleVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Sequences:  make([]*dto.BundleSequence, 0, len(ids)),
+	}
+
+	exported := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		sequence, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrorSequenceNotFound, id)
+		}
+
+		if exported[id] {
+			continue
+		}
+		exported[id] = true
+
+		bundle.Sequences = append(bundle.Sequences, toBundleSequence(sequence))
+	}
+
+	return bundle, nil
+}
+
+// ImportSequences creates the sequences of the bundle, the ones named after an
+// existing sequence follow the conflict policy of opts: they are skipped,
+// created under a free name, e.g. "Onboarding (2)", or overwrite the oldest
+// sequence with the name. Overwritten steps keep their id when the step
+// number matches. Every sequence is stored in the same transaction and a dry
+// run stores none, reporting what the import would do instead.
+func (s *bundleService) ImportSequences(ctx context.Context, bundle dto.SequenceBundle, opts dto.ImportOptions) (*dto.ImportResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(bundle.Sequences))
+	for _, sequence := range bundle.Sequences {
+		names = append(names, sequence.Name)
+	}
+
+	existing, err := s.sequenceRepository.FindByNames(ctx, names)
+	if err != nil {
+		slog.Error("failed to get sequences during importSequences", err.Error(), err)
+		return nil, err
+	}
+
+	// the sequences are sorted by age, so the oldest one with a name is kept
+	byName := make(map[string]*models.SequenceWithSteps, len(existing))
+	for _, sequence := range existing {
+		if _, ok := byName[sequence.Name]; !ok {
+			byName[sequence.Name] = sequence
+		}
+	}
+
+	// the names taken by the import itself, so renamed sequences do not clash
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		taken[name] = true
+	}
+
+	response := &dto.ImportResponse{
+		DryRun:    opts.DryRun,
+		Sequences: make([]*dto.ImportedSequence, 0, len(bundle.Sequences)),
+	}
+
+	imported := make([]*models.SequenceWithSteps, 0, len(bundle.Sequences))
+	results := make(map[*models.SequenceWithSteps]*dto.ImportedSequence, len(bundle.Sequences))
+	// the sequences overwritten by the imported ones, as they were before
+	overwritten := make(map[*models.SequenceWithSteps]*models.SequenceWithSteps)
+
+	for _, sequence := range bundle.Sequences {
+		model := toImportedSequence(sequence)
+		result := &dto.ImportedSequence{Name: sequence.Name, Action: dto.ImportCreated}
+
+		response.Sequences = append(response.Sequences, result)
+
+		current, ok := byName[sequence.Name]
+		if ok {
+			switch opts.OnConflict {
+			case dto.ConflictSkip:
+				result.Action = dto.ImportSkipped
+				result.ID = idString(current.ExternalID)
+				continue
+
+			case dto.ConflictRename:
+				name, err := s.freeName(ctx, sequence.Name, taken)
+				if err != nil {
+					return nil, err
+				}
+				taken[name] = true
+
+				model.Name = name
+				result.Action = dto.ImportRenamed
+				result.ImportedAs = name
+
+			case dto.ConflictOverwrite:
+				if !models.IsEditableStatus(current.Status) {
+					return nil, fmt.Errorf("%w: sequence %q is %s", ErrorSequenceNotEditable, current.Name, current.Status)
+				}
+
+				overwrite(model, current)
+				overwritten[model] = current
+
+				result.Action = dto.ImportOverwritten
+				result.ID = idString(current.ExternalID)
+				result.Changes = diffSnapshots(toSnapshot(current), toSnapshot(model))
+			}
+		}
+
+		imported = append(imported, model)
+		results[model] = result
+	}
+
+	if opts.DryRun || len(imported) == 0 {
+		return response, nil
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.Import(ctx, imported); err != nil {
+			return err
+		}
+
+		for _, model := range imported {
+			var before *dto.SequenceResponse
+			if current, ok := overwritten[model]; ok {
+				before = toSequenceResponse(current)
+			}
+
+			if err := s.auditor.record(ctx, auditActionImport, auditTargetSequence, model.ExternalID, before, toSequenceResponse(model)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the sequence was deleted since it was read above
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		// the sequence changed since it was read above
+		if err == repository.ErrVersionConflict {
+			return nil, ErrorVersionMismatch
+		}
+		slog.Error("failed to import sequences", err.Error(), err)
+		return nil, err
+	}
+
+	for model, result := range results {
+		result.ID = idString(model.ExternalID)
+	}
+
+	return response, nil
+}
+
+// freeName returns the first name following the pattern "name (n)", from 2 on,
+// that is neither taken by a sequence nor by the import.
+func (s *bundleService) freeName(ctx context.Context, name string, taken map[string]bool) (string, error) {
+	for first := 2; ; first += renameCandidates {
+		candidates := make([]string, 0, renameCandidates)
+		for n := first; n < first+renameCandidates; n++ {
+			candidates = append(candidates, fmt.Sprintf("%s (%d)", name, n))
+		}
+
+		existing, err := s.sequenceRepository.FindByNames(ctx, candidates)
+		if err != nil {
+			slog.Error("failed to get sequences during freeName", err.Error(), err)
+			return "", err
+		}
+
+		used := make(map[string]bool, len(existing))
+		for _, sequence := range existing {
+			used[sequence.Name] = true
+		}
+
+		for _, candidate := range candidates {
+			if !used[candidate] && !taken[candidate] {
+				return candidate, nil
+			}
+		}
+	}
+}
+
+// overwrite turns the imported model into a replacement of current: steps
+// with the number of a current step take its id and the other ones get a new
+// id, so a dry run can already tell them apart.
+func overwrite(model *models.SequenceWithSteps, current *models.SequenceWithSteps) {
+	model.ExternalID = current.ExternalID
+	model.Version = current.Version
+
+	stepIDs := make(map[int32]uuid.UUID, len(current.Steps))
+	for _, step := range current.Steps {
+		if step != nil {
+			stepIDs[step.StepNumber] = step.ExternalID
+		}
+	}
+
+	for _, step := range model.Steps {
+		id, ok := stepIDs[step.StepNumber]
+		if !ok {
+			id = uuid.New()
+		}
+		step.ExternalID = id
+	}
+}
+
+func toImportedSequence(sequence *dto.BundleSequence) *models.SequenceWithSteps {
+	model := &models.SequenceWithSteps{
+		Name:                 sequence.Name,
+		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
+		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
+		Variables:            sequence.Variables,
+		Steps:                make([]*dao.Step, 0, len(sequence.Steps)),
+	}
+
+	for _, step := range sequence.Steps {
+		model.Steps = append(model.Steps, toStep(step.Request()))
+	}
+
+	return model
+}
+
+func toBundleSequence(sequence *models.SequenceWithSteps) *dto.BundleSequence {
+	bundled := &dto.BundleSequence{
+		Name:                 sequence.Name,
+		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
+		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
+		Variables:            sequence.Variables,
+		Steps:                make([]*dto.BundleStep, 0, len(sequence.Steps)),
+	}
+
+	if bundled.Variables == nil {
+		bundled.Variables = make(map[string]string)
+	}
+
+	for _, step := range sortedSteps(sequence.Steps) {
+		bundled.Steps = append(bundled.Steps, &dto.BundleStep{
+			StepNumber:       int(step.StepNumber),
+			MailSubject:      step.MailSubject,
+			MailHTML:         step.MailContent,
+			MailText:         step.MailText,
+			DelayDays:        int(step.DelayDays),
+			DelayHours:       int(step.DelayHours),
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindow:       dto.NewSendWindow(step.SendWindowStart, step.SendWindowEnd),
+		})
+	}
+
+	return bundled
+}
+
+// toSnapshot returns the content of the sequence the way revisions record it,
+// so it can be compared with diffSnapshots.
+func toSnapshot(sequence *models.SequenceWithSteps) models.SequenceSnapshot {
+	snapshot := models.SequenceSnapshot{
+		Name:                 sequence.Name,
+		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
+		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
+		Variables:            sequence.Variables,
+		Steps:                make([]*models.StepSnapshot, 0, len(sequence.Steps)),
+	}
+
+	for _, step := range sortedSteps(sequence.Steps) {
+		snapshot.Steps = append(snapshot.Steps, &models.StepSnapshot{
+			ExternalID:       step.ExternalID,
+			StepNumber:       step.StepNumber,
+			MailSubject:      step.MailSubject,
+			MailContent:      step.MailContent,
+			MailText:         step.MailText,
+			DelayDays:        step.DelayDays,
+			DelayHours:       step.DelayHours,
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindowStart:  step.SendWindowStart,
+			SendWindowEnd:    step.SendWindowEnd,
+		})
+	}
+
+	return snapshot
+}
+
+// sortedSteps returns the steps by step number, leaving out the null step of
+// the sequences without steps.
+func sortedSteps(steps []*dao.Step) []*dao.Step {
+	sorted := slices.DeleteFunc(slices.Clone(steps), func(step *dao.Step) bool {
+		return step == nil
+	})
+
+	slices.SortFunc(sorted, func(a, b *dao.Step) int {
+		return cmp.Compare(a.StepNumber, b.StepNumber)
+	})
+
+	return sorted
+}
+
+func idString(id uuid.UUID) *string {
+	s := id.String()
+	return &s
+}
@@ -0,0 +1,276 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBundleService_ExportSequences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		first, second := uuid.New(), uuid.New()
+		start, end := int32(540), int32(1020)
+
+		sequenceRepository.EXPECT().FindByExternalIds(gomock.Any(), []uuid.UUID{second, first}).Return([]*models.SequenceWithSteps{
+			{ExternalID: first, Name: "first", Steps: []*dao.Step{nil}},
+			{ExternalID: second, Name: "second", Variables: map[string]string{"product": "Sequences"}, Steps: []*dao.Step{
+				{ExternalID: uuid.New(), StepNumber: 2, MailSubject: "second subject", MailContent: "<p>second</p>"},
+				{ExternalID: uuid.New(), StepNumber: 1, MailSubject: "first subject", MailContent: "<p>first</p>", MailText: "first", SendWindowStart: &start, SendWindowEnd: &end},
+			}},
+		}, nil)
+
+		res, err := bundleService.ExportSequences(context.Background(), []uuid.UUID{second, first})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.BundleVersion, res.Version)
+		assert.Len(t, res.Sequences, 2)
+
+		assert.Equal(t, "second", res.Sequences[0].Name)
+		assert.Equal(t, map[string]string{"product": "Sequences"}, res.Sequences[0].Variables)
+		assert.Equal(t, []*dto.BundleStep{
+			{StepNumber: 1, MailSubject: "first subject", MailHTML: "<p>first</p>", MailText: "first", SendWindow: &dto.SendWindow{Start: "09:00", End: "17:00"}},
+			{StepNumber: 2, MailSubject: "second subject", MailHTML: "<p>second</p>"},
+		}, res.Sequences[0].Steps)
+
+		assert.Equal(t, "first", res.Sequences[1].Name)
+		assert.Equal(t, map[string]string{}, res.Sequences[1].Variables)
+		assert.Empty(t, res.Sequences[1].Steps)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when any sequence is not found", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		found, missing := uuid.New(), uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalIds(gomock.Any(), []uuid.UUID{found, missing}).Return([]*models.SequenceWithSteps{
+			{ExternalID: found, Name: "found"},
+		}, nil)
+
+		_, err := bundleService.ExportSequences(context.Background(), []uuid.UUID{found, missing})
+
+		assert.ErrorIs(t, err, services.ErrorSequenceNotFound)
+		assert.ErrorContains(t, err, missing.String())
+	})
+
+	t.Run("return services.ErrorRoleNotGranted before looking the sequences up", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1"})
+		missing := uuid.New()
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", &missing, nil).Return(nil, nil)
+		sequenceRepository.EXPECT().FindByExternalIds(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := bundleService.ExportSequences(ctx, []uuid.UUID{missing})
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalIds(gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		_, err := bundleService.ExportSequences(context.Background(), []uuid.UUID{uuid.New()})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestBundleService_ImportSequences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	bundle := func(names ...string) dto.SequenceBundle {
+		b := dto.SequenceBundle{Version: dto.BundleVersion}
+		for _, name := range names {
+			b.Sequences = append(b.Sequences, &dto.BundleSequence{
+				Name: name,
+				Steps: []*dto.BundleStep{
+					{StepNumber: 1, MailSubject: "new subject", MailHTML: "<p>content</p>"},
+					{StepNumber: 2, MailSubject: "follow up", MailHTML: "<p>follow up</p>"},
+				},
+			})
+		}
+		return b
+	}
+
+	existing := func(name string, status string) *models.SequenceWithSteps {
+		return &models.SequenceWithSteps{
+			ExternalID: uuid.New(),
+			Name:       name,
+			Status:     status,
+			Version:    3,
+			Steps: []*dao.Step{
+				{ExternalID: uuid.New(), StepNumber: 1, MailSubject: "old subject", MailContent: "<p>content</p>"},
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), []string{"Onboarding"}).Return(nil, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, sequences []*models.SequenceWithSteps) error {
+			assert.Len(t, sequences, 1)
+			assert.Equal(t, "Onboarding", sequences[0].Name)
+			assert.Equal(t, uuid.Nil, sequences[0].ExternalID)
+			assert.Len(t, sequences[0].Steps, 2)
+
+			sequences[0].ExternalID = uuid.New()
+			return nil
+		})
+
+		res, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictSkip})
+		assert.NoError(t, err)
+		assert.False(t, res.DryRun)
+		assert.Len(t, res.Sequences, 1)
+		assert.Equal(t, dto.ImportCreated, res.Sequences[0].Action)
+		assert.NotNil(t, res.Sequences[0].ID)
+	})
+
+	t.Run("should skip the sequences named after an existing one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		current := existing("Onboarding", models.StatusActive)
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), []string{"Onboarding"}).Return([]*models.SequenceWithSteps{current}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).Times(0)
+
+		res, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ImportSkipped, res.Sequences[0].Action)
+		assert.Equal(t, current.ExternalID.String(), *res.Sequences[0].ID)
+	})
+
+	t.Run("should rename the sequences named after an existing one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), []string{"Onboarding", "Onboarding (3)"}).Return([]*models.SequenceWithSteps{
+			existing("Onboarding", models.StatusDraft),
+		}, nil)
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]*models.SequenceWithSteps{
+			existing("Onboarding (2)", models.StatusDraft),
+		}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, sequences []*models.SequenceWithSteps) error {
+			assert.Equal(t, "Onboarding (4)", sequences[0].Name)
+			assert.Equal(t, "Onboarding (3)", sequences[1].Name)
+
+			for _, sequence := range sequences {
+				sequence.ExternalID = uuid.New()
+			}
+			return nil
+		})
+
+		res, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding", "Onboarding (3)"), dto.ImportOptions{OnConflict: dto.ConflictRename})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ImportRenamed, res.Sequences[0].Action)
+		assert.Equal(t, "Onboarding (4)", res.Sequences[0].ImportedAs)
+		assert.Equal(t, dto.ImportCreated, res.Sequences[1].Action)
+	})
+
+	t.Run("should overwrite the sequences named after an existing one keeping the ids of the steps", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		current := existing("Onboarding", models.StatusPaused)
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), []string{"Onboarding"}).Return([]*models.SequenceWithSteps{current}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, sequences []*models.SequenceWithSteps) error {
+			assert.Equal(t, current.ExternalID, sequences[0].ExternalID)
+			assert.Equal(t, current.Version, sequences[0].Version)
+			assert.Equal(t, current.Steps[0].ExternalID, sequences[0].Steps[0].ExternalID)
+			assert.NotEqual(t, uuid.Nil, sequences[0].Steps[1].ExternalID)
+			return nil
+		})
+
+		res, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictOverwrite})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ImportOverwritten, res.Sequences[0].Action)
+		assert.Equal(t, current.ExternalID.String(), *res.Sequences[0].ID)
+		assert.Len(t, res.Sequences[0].Changes.AddedSteps, 1)
+		assert.Equal(t, []*dto.StepDiff{{
+			ExternalID: current.Steps[0].ExternalID.String(),
+			Changes:    []*dto.FieldChange{{Field: "mailSubject", From: "old subject", To: "new subject"}},
+		}}, res.Sequences[0].Changes.ChangedSteps)
+	})
+
+	t.Run("should not store anything on a dry run", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]*models.SequenceWithSteps{
+			existing("Onboarding", models.StatusDraft),
+		}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).Times(0)
+
+		res, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding", "Winback"), dto.ImportOptions{OnConflict: dto.ConflictOverwrite, DryRun: true})
+		assert.NoError(t, err)
+		assert.True(t, res.DryRun)
+		assert.Equal(t, dto.ImportOverwritten, res.Sequences[0].Action)
+		assert.NotNil(t, res.Sequences[0].Changes)
+		assert.Equal(t, dto.ImportCreated, res.Sequences[1].Action)
+		assert.Nil(t, res.Sequences[1].ID)
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when overwriting an active sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]*models.SequenceWithSteps{
+			existing("Onboarding", models.StatusActive),
+		}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictOverwrite, DryRun: true})
+
+		assert.ErrorIs(t, err, services.ErrorSequenceNotEditable)
+	})
+
+	t.Run("return services.ErrorVersionMismatch when the sequence changes during the import", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return([]*models.SequenceWithSteps{
+			existing("Onboarding", models.StatusDraft),
+		}, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+
+		_, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictOverwrite})
+
+		assert.ErrorIs(t, err, services.ErrorVersionMismatch)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		bundleService := services.NewBundleService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByNames(gomock.Any(), gomock.Any()).Return(nil, nil)
+		sequenceRepository.EXPECT().Import(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := bundleService.ImportSequences(context.Background(), bundle("Onboarding"), dto.ImportOptions{OnConflict: dto.ConflictSkip})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
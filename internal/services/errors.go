@@ -1,8 +1,59 @@
 package services
 
-import "errors"
+// ErrorKind groups the service errors by how the caller should handle them,
+// e.g. the status code of the response.
+type ErrorKind int
+
+const (
+	KindNotFound ErrorKind = iota + 1
+	KindInvalid
+	KindConflict
+	KindPreconditionFailed
+	KindUnprocessable
+	KindForbidden
+	KindUnauthorized
+)
+
+// Error is a known failure of the services. Errors are compared by identity
+// and may be wrapped with the details of the failure, Code identifies them to
+// the clients.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	message string
+}
+
+func newError(kind ErrorKind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
 
 var (
-	ErrorSequenceNotFound = errors.New("sequence not found")
-	ErrorStepNotFound     = errors.New("step not found")
+	ErrorSequenceNotFound         = newError(KindNotFound, "sequence-not-found", "sequence not found")
+	ErrorStepNotFound             = newError(KindNotFound, "step-not-found", "step not found")
+	ErrorInvalidCursor            = newError(KindInvalid, "invalid-cursor", "invalid cursor")
+	ErrorStepNotInSequence        = newError(KindInvalid, "step-not-in-sequence", "step does not belong to the sequence")
+	ErrorRevisionNotFound         = newError(KindNotFound, "revision-not-found", "revision not found")
+	ErrorStepNumberTaken          = newError(KindConflict, "step-number-taken", "step number is already taken by another step")
+	ErrorInvalidStepOrder         = newError(KindInvalid, "invalid-step-order", "step order must list every step of the sequence exactly once")
+	ErrorInvalidTemplate          = newError(KindUnprocessable, "invalid-template", "step template is invalid")
+	ErrorUnresolvedVariables      = newError(KindUnprocessable, "unresolved-variables", "template variables are not resolved")
+	ErrorVariantNotFound          = newError(KindNotFound, "variant-not-found", "variant not found")
+	ErrorStepHasNoVariants        = newError(KindConflict, "step-has-no-variants", "step has no variants to assign")
+	ErrorSequenceNotEditable      = newError(KindConflict, "sequence-not-editable", "active and archived sequences cannot be changed")
+	ErrorInvalidTransition        = newError(KindConflict, "invalid-transition", "sequence status does not allow the transition")
+	ErrorUnknownTransition        = newError(KindNotFound, "unknown-transition", "unknown sequence transition")
+	ErrorSequenceHasNoSteps       = newError(KindUnprocessable, "sequence-has-no-steps", "sequence must have at least one step to be active")
+	ErrorVersionMismatch          = newError(KindPreconditionFailed, "version-mismatch", "resource was changed since the given version, fetch it again")
+	ErrorIdempotencyKeyReused     = newError(KindConflict, "idempotency-key-reused", "idempotency key was already used with a different request")
+	ErrorIdempotencyKeyInProgress = newError(KindConflict, "idempotency-key-in-progress", "a request with the same idempotency key is still being processed")
+	ErrorWorkspaceNotFound        = newError(KindForbidden, "workspace-not-found", "workspace of the caller does not exist")
+	ErrorApiKeyNotFound           = newError(KindNotFound, "api-key-not-found", "api key not found")
+	ErrorInvalidApiKey            = newError(KindUnauthorized, "invalid-api-key", "api key is invalid or was revoked")
+	ErrorScopeNotGranted          = newError(KindForbidden, "scope-not-granted", "api keys cannot be granted scopes the caller does not have")
+	ErrorRoleNotGranted           = newError(KindForbidden, "role-not-granted", "the role of the caller does not allow the action")
+	ErrorRoleBindingNotFound      = newError(KindNotFound, "role-binding-not-found", "role binding not found")
 )
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, requestHash string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, response *models.IdempotencyKey) error
+	Release(ctx context.Context, key string) error
+	PurgeExpiredKeys(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	ttl                   time.Duration
+	idempotencyRepository repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(ttl time.Duration, idempotencyRepository repository.IdempotencyRepository) IdempotencyService {
+	return &idempotencyService{ttl: ttl, idempotencyRepository: idempotencyRepository}
+}
+
+// Begin takes the key for the request, in which case it returns nil and the
+// caller must either Complete or Release the key. When the same request was
+// already sent with the key, it returns the stored response to be replayed.
+func (s *idempotencyService) Begin(ctx context.Context, key string, requestHash string) (*models.IdempotencyKey, error) {
+	reserved, err := s.idempotencyRepository.Reserve(ctx, key, requestHash, time.Now().UTC().Add(-s.ttl))
+	if err != nil {
+		slog.Error("failed to reserve idempotency key", err.Error(), err)
+		return nil, err
+	}
+
+	if reserved {
+		return nil, nil
+	}
+
+	stored, err := s.idempotencyRepository.FindOne(ctx, key)
+	if err != nil {
+		// the request holding the key failed and released it since it was reserved
+		if err == pgx.ErrNoRows {
+			return nil, ErrorIdempotencyKeyInProgress
+		}
+		slog.Error("failed to get idempotency key", err.Error(), err)
+		return nil, err
+	}
+
+	if stored.RequestHash != requestHash {
+		return nil, ErrorIdempotencyKeyReused
+	}
+
+	if !stored.Completed() {
+		return nil, ErrorIdempotencyKeyInProgress
+	}
+
+	return stored, nil
+}
+
+// Complete stores the response of the request that took the key.
+func (s *idempotencyService) Complete(ctx context.Context, response *models.IdempotencyKey) error {
+	if err := s.idempotencyRepository.Complete(ctx, response); err != nil {
+		slog.Error("failed to complete idempotency key", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Release frees the key without storing a response, so the request can be
+// retried with it, e.g. after a server error.
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	if err := s.idempotencyRepository.Delete(ctx, key); err != nil {
+		slog.Error("failed to release idempotency key", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// PurgeExpiredKeys removes the keys older than the ttl, returning how many
+// were removed.
+func (s *idempotencyService) PurgeExpiredKeys(ctx context.Context) (int64, error) {
+	purged, err := s.idempotencyRepository.Purge(ctx, time.Now().UTC().Add(-s.ttl))
+	if err != nil {
+		slog.Error("failed to purge idempotency keys", err.Error(), err)
+		return 0, err
+	}
+
+	return purged, nil
+}
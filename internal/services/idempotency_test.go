@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success when key is new", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _ string, expiredBefore time.Time) (bool, error) {
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), expiredBefore, time.Minute)
+			return true, nil
+		})
+		idempotencyRepository.EXPECT().FindOne(gomock.Any(), gomock.Any()).Times(0)
+
+		stored, err := idempotencyService.Begin(context.Background(), "key", "hash")
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+	})
+
+	t.Run("success replaying the stored response", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).Return(false, nil)
+		idempotencyRepository.EXPECT().FindOne(gomock.Any(), "key").Return(&models.IdempotencyKey{
+			Key:         "key",
+			RequestHash: "hash",
+			StatusCode:  201,
+			Body:        []byte(`{"name":"name"}`),
+		}, nil)
+
+		stored, err := idempotencyService.Begin(context.Background(), "key", "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(201), stored.StatusCode)
+		assert.Equal(t, `{"name":"name"}`, string(stored.Body))
+	})
+
+	t.Run("return services.ErrorIdempotencyKeyReused when key was used with another request", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).Return(false, nil)
+		idempotencyRepository.EXPECT().FindOne(gomock.Any(), "key").Return(&models.IdempotencyKey{Key: "key", RequestHash: "other", StatusCode: 201}, nil)
+
+		stored, err := idempotencyService.Begin(context.Background(), "key", "hash")
+
+		assert.Nil(t, stored)
+		assert.EqualError(t, err, services.ErrorIdempotencyKeyReused.Error())
+	})
+
+	t.Run("return services.ErrorIdempotencyKeyInProgress when first request did not finish", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).Return(false, nil)
+		idempotencyRepository.EXPECT().FindOne(gomock.Any(), "key").Return(&models.IdempotencyKey{Key: "key", RequestHash: "hash"}, nil)
+
+		stored, err := idempotencyService.Begin(context.Background(), "key", "hash")
+
+		assert.Nil(t, stored)
+		assert.EqualError(t, err, services.ErrorIdempotencyKeyInProgress.Error())
+	})
+
+	t.Run("return services.ErrorIdempotencyKeyInProgress when key is released concurrently", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).Return(false, nil)
+		idempotencyRepository.EXPECT().FindOne(gomock.Any(), "key").Return(nil, pgx.ErrNoRows)
+
+		_, err := idempotencyService.Begin(context.Background(), "key", "hash")
+
+		assert.EqualError(t, err, services.ErrorIdempotencyKeyInProgress.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Reserve(gomock.Any(), "key", "hash", gomock.Any()).Return(false, sql.ErrConnDone)
+
+		_, err := idempotencyService.Begin(context.Background(), "key", "hash")
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestIdempotencyService_PurgeExpiredKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(24*time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Purge(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, createdBefore time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-24*time.Hour), createdBefore, time.Minute)
+			return 3, nil
+		})
+
+		purged, err := idempotencyService.PurgeExpiredKeys(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		idempotencyRepository := mocks.NewMockIdempotencyRepository(ctrl)
+		idempotencyService := services.NewIdempotencyService(24*time.Hour, idempotencyRepository)
+
+		idempotencyRepository.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(int64(0), sql.ErrConnDone)
+
+		_, err := idempotencyService.PurgeExpiredKeys(context.Background())
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
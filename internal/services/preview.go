@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+)
+
+type PreviewService interface {
+	PreviewStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.PreviewStepRequest) (*dto.StepPreviewResponse, error)
+}
+
+type previewService struct {
+	sequenceRepository repository.SequenceRepository
+	tracker            *mail.Tracker
+	authorizer         authorizer
+}
+
+func NewPreviewService(sequenceRepository repository.SequenceRepository, tracker *mail.Tracker, roleBindingRepository repository.RoleBindingRepository) PreviewService {
+	return &previewService{sequenceRepository: sequenceRepository, tracker: tracker, authorizer: authorizer{roleBindingRepository: roleBindingRepository}}
+}
+
+// PreviewStep renders the step for the contact with the variables of its
+// sequence, tracking the links and the opening like the sequence would.
+func (s *previewService) PreviewStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.PreviewStepRequest) (*dto.StepPreviewResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	sequence, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to get sequence", err.Error(), err)
+		return nil, err
+	}
+
+	var step *dao.Step
+	for _, candidate := range sequence.Steps {
+		if candidate != nil && candidate.ExternalID == stepID {
+			step = candidate
+			break
+		}
+	}
+
+	if step == nil {
+		return nil, ErrorStepNotFound
+	}
+
+	subjectTemplate, err := templating.Parse(step.MailSubject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mail subject: %s", ErrorInvalidTemplate, err)
+	}
+
+	contentTemplate, err := templating.Parse(step.MailContent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mail content: %s", ErrorInvalidTemplate, err)
+	}
+
+	textTemplate, err := templating.Parse(step.MailText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mail text: %s", ErrorInvalidTemplate, err)
+	}
+
+	data := templating.Data{
+		templating.NamespaceContact:  req.Contact,
+		templating.NamespaceSequence: sequence.Variables,
+	}
+
+	unresolved := make([]string, 0)
+	for _, tmpl := range []*templating.Template{subjectTemplate, contentTemplate, textTemplate} {
+		for _, variable := range tmpl.Unresolved(data) {
+			if !slices.Contains(unresolved, variable) {
+				unresolved = append(unresolved, variable)
+			}
+		}
+	}
+
+	if req.Strict && len(unresolved) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrorUnresolvedVariables, strings.Join(unresolved, ", "))
+	}
+
+	// unresolved variables are reported as warnings, so rendering cannot fail
+	subject, _ := subjectTemplate.Render(data, false)
+	body, _ := contentTemplate.RenderHTML(data, false)
+	text, _ := textTemplate.Render(data, false)
+
+	// the variables come from the contacts, so links such as javascript: urls
+	// are only removed once they are rendered
+	body = mail.SanitizeRenderedHTML(body)
+
+	response := &dto.StepPreviewResponse{
+		Subject:      subject,
+		Warnings:     make([]string, 0, len(unresolved)),
+		TrackedLinks: make([]*dto.TrackedLinkResponse, 0),
+	}
+
+	for _, variable := range unresolved {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("variable %s is not resolved", variable))
+	}
+
+	if sequence.ClickTrackingEnabled {
+		var links []mail.TrackedLink
+		body, links = s.tracker.TrackLinks(body, stepID)
+
+		for _, link := range links {
+			response.TrackedLinks = append(response.TrackedLinks, &dto.TrackedLinkResponse{URL: link.URL, TrackedURL: link.TrackedURL})
+		}
+	}
+
+	// the derived plain text body carries the tracked links but not the pixel,
+	// a plain text body of the step's own is kept as written
+	response.Text = text
+	if step.MailText == "" {
+		response.Text = mail.PlainText(body)
+	}
+
+	if sequence.OpenTrackingEnabled {
+		body = s.tracker.AddOpenPixel(body, stepID)
+		openURL := s.tracker.OpenURL(stepID)
+		response.OpenTrackingURL = &openURL
+	}
+
+	response.HTML = body
+
+	return response, nil
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreviewService_PreviewStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tracker := mail.NewTracker("https://track.example.com")
+
+	sequenceID := uuid.New()
+	stepID := uuid.New()
+
+	newSequence := func(clickTracking, openTracking bool) *models.SequenceWithSteps {
+		return &models.SequenceWithSteps{
+			ID:                   1,
+			ClickTrackingEnabled: clickTracking,
+			OpenTrackingEnabled:  openTracking,
+			Variables:            map[string]string{"product": "Mailbox"},
+			Steps: []*dao.Step{
+				{ID: 1, ExternalID: uuid.New(), MailSubject: "Other", MailContent: "Other"},
+				{
+					ID:          2,
+					ExternalID:  stepID,
+					MailSubject: "Hi {{contact.firstName | capitalize}}",
+					MailContent: `<p>{{contact.company}} meets <a href="https://example.com/{{sequence.product | lower}}">{{sequence.product}}</a></p>`,
+				},
+			},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(newSequence(false, false), nil)
+
+		res, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{
+			Contact: map[string]string{"firstName": "ana"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi Ana", res.Subject)
+		assert.Equal(t, `<p> meets <a href="https://example.com/mailbox">Mailbox</a></p>`, res.HTML)
+		assert.Equal(t, "meets Mailbox (https://example.com/mailbox)", res.Text)
+		assert.Equal(t, []string{"variable contact.company is not resolved"}, res.Warnings)
+		assert.Empty(t, res.TrackedLinks)
+		assert.Nil(t, res.OpenTrackingURL)
+	})
+
+	t.Run("success tracking clicks and opens", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(newSequence(true, true), nil)
+
+		res, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{
+			Contact: map[string]string{"firstName": "ana", "company": "Acme"},
+		})
+		assert.NoError(t, err)
+
+		clickURL := tracker.ClickURL(stepID, "https://example.com/mailbox")
+		openURL := tracker.OpenURL(stepID)
+
+		assert.Len(t, res.TrackedLinks, 1)
+		assert.Equal(t, "https://example.com/mailbox", res.TrackedLinks[0].URL)
+		assert.Equal(t, clickURL, res.TrackedLinks[0].TrackedURL)
+		assert.Contains(t, res.HTML, "track/click?step=")
+		assert.NotContains(t, res.HTML, `href="https://example.com/mailbox"`)
+		assert.Contains(t, res.HTML, `<img src="`+openURL+`"`)
+		assert.Equal(t, "Acme meets Mailbox ("+clickURL+")", res.Text)
+		assert.Empty(t, res.Warnings)
+		assert.Equal(t, &openURL, res.OpenTrackingURL)
+	})
+
+	t.Run("success escaping the values of the contact", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequence := newSequence(false, false)
+		sequence.Steps[1].MailContent = `<p>Hi {{contact.firstName}}</p><a href="{{contact.url}}">Link</a><a href="{{contact.site}}">Site</a>`
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequence, nil)
+
+		res, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{
+			Contact: map[string]string{"firstName": "<script>alert(1)</script>", "url": "javascript:alert(1)", "site": `" onclick="alert(1)`},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>Hi &lt;script&gt;alert(1)&lt;/script&gt;</p>LinkSite", res.HTML)
+	})
+
+	t.Run("success rendering the plain text body of the step", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequence := newSequence(true, false)
+		sequence.Steps[1].MailText = "{{contact.firstName}}, meet {{sequence.product}} at https://example.com"
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequence, nil)
+
+		res, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{
+			Contact: map[string]string{"company": "Acme"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, ", meet Mailbox at https://example.com", res.Text)
+		assert.Equal(t, []string{"variable contact.firstName is not resolved"}, res.Warnings)
+	})
+
+	t.Run("return services.ErrorUnresolvedVariables when variables are missing in strict mode", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(newSequence(false, false), nil)
+
+		_, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{Strict: true})
+
+		assert.ErrorIs(t, err, services.ErrorUnresolvedVariables)
+		assert.EqualError(t, err, services.ErrorUnresolvedVariables.Error()+": contact.firstName, contact.company")
+	})
+
+	t.Run("return services.ErrorStepNotFound when step belongs to another sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(newSequence(false, false), nil)
+
+		_, err := previewService.PreviewStep(context.Background(), sequenceID, uuid.New(), dto.PreviewStepRequest{})
+
+		assert.EqualError(t, err, services.ErrorStepNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		_, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		previewService := services.NewPreviewService(sequenceRepository, tracker, mocks.NewMockRoleBindingRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, sql.ErrConnDone)
+
+		_, err := previewService.PreviewStep(context.Background(), sequenceID, stepID, dto.PreviewStepRequest{})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+type RevisionService interface {
+	GetRevisions(ctx context.Context, sequenceID uuid.UUID, size int, page int) ([]*dto.RevisionResponse, error)
+	GetRevision(ctx context.Context, sequenceID uuid.UUID, revision int) (*dto.RevisionResponse, error)
+	DiffRevisions(ctx context.Context, sequenceID uuid.UUID, from int, to int) (*dto.RevisionDiffResponse, error)
+	RollbackRevision(ctx context.Context, sequenceID uuid.UUID, revision int) (*dto.SequenceResponse, error)
+}
+
+type revisionService struct {
+	sequenceRepository repository.SequenceRepository
+	revisionRepository repository.RevisionRepository
+	authorizer         authorizer
+	auditor            auditor
+}
+
+func NewRevisionService(sequenceRepository repository.SequenceRepository, revisionRepository repository.RevisionRepository, roleBindingRepository repository.RoleBindingRepository, transactor repository.Transactor, auditRepository repository.AuditRepository) RevisionService {
+	return &revisionService{
+		sequenceRepository: sequenceRepository,
+		revisionRepository: revisionRepository,
+		authorizer:         authorizer{roleBindingRepository: roleBindingRepository},
+		auditor:            auditor{transactor: transactor, auditRepository: auditRepository},
+	}
+}
+
+func (s *revisionService) GetRevisions(ctx context.Context, sequenceID uuid.UUID, size int, page int) ([]*dto.RevisionResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to get sequence", err.Error(), err)
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepository.FindAll(ctx, sequenceID, size, size*page)
+	if err != nil {
+		slog.Error("failed to get revisions", err.Error(), err)
+		return nil, err
+	}
+
+	response := make([]*dto.RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		response = append(response, toRevisionResponse(revision))
+	}
+
+	return response, nil
+}
+
+func (s *revisionService) GetRevision(ctx context.Context, sequenceID uuid.UUID, revision int) (*dto.RevisionResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	found, err := s.findRevision(ctx, sequenceID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRevisionResponse(found), nil
+}
+
+func (s *revisionService) DiffRevisions(ctx context.Context, sequenceID uuid.UUID, from int, to int) (*dto.RevisionDiffResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.findRevision(ctx, sequenceID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.findRevision(ctx, sequenceID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RevisionDiffResponse{
+		From:         from,
+		To:           to,
+		SequenceDiff: *diffSnapshots(fromRevision.Snapshot, toRevision.Snapshot),
+	}, nil
+}
+
+// RollbackRevision replaces the sequence and the variants of its steps with the
+// content of the given revision, which is recorded as a new revision instead of
+// discarding the ones after it.
+func (s *revisionService) RollbackRevision(ctx context.Context, sequenceID uuid.UUID, revision int) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
+	found, err := s.findRevision(ctx, sequenceID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	sequence := models.SequenceWithSteps{
+		ExternalID:           sequenceID,
+		Name:                 found.Snapshot.Name,
+		OpenTrackingEnabled:  found.Snapshot.OpenTrackingEnabled,
+		ClickTrackingEnabled: found.Snapshot.ClickTrackingEnabled,
+		Variables:            found.Snapshot.Variables,
+		Steps:                make([]*dao.Step, 0, len(found.Snapshot.Steps)),
+		Variants:             make(map[uuid.UUID][]*models.StepVariant),
+	}
+
+	for _, step := range found.Snapshot.Steps {
+		sequence.Steps = append(sequence.Steps, &dao.Step{
+			ExternalID:       step.ExternalID,
+			StepNumber:       step.StepNumber,
+			MailSubject:      step.MailSubject,
+			MailContent:      step.MailContent,
+			DelayDays:        step.DelayDays,
+			DelayHours:       step.DelayHours,
+			BusinessDaysOnly: step.BusinessDaysOnly,
+			SendWindowStart:  step.SendWindowStart,
+			SendWindowEnd:    step.SendWindowEnd,
+			MailText:         step.MailText,
+		})
+
+		for _, variant := range step.Variants {
+			sequence.Variants[step.ExternalID] = append(sequence.Variants[step.ExternalID], &models.StepVariant{
+				ExternalID:  variant.ExternalID,
+				MailSubject: variant.MailSubject,
+				MailContent: variant.MailContent,
+				Weight:      variant.Weight,
+			})
+		}
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sequenceRepository.Replace(ctx, &sequence); err != nil {
+			return err
+		}
+
+		return s.auditor.record(ctx, auditActionRollback, auditTargetSequence, sequenceID, toSequenceResponse(before), toSequenceResponse(&sequence))
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		slog.Error("failed to rollback sequence", err.Error(), err)
+		return nil, err
+	}
+
+	return toSequenceResponse(&sequence), nil
+}
+
+func (s *revisionService) findRevision(ctx context.Context, sequenceID uuid.UUID, revision int) (*models.SequenceRevision, error) {
+	found, err := s.revisionRepository.FindOne(ctx, sequenceID, int32(revision))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorRevisionNotFound
+		}
+		slog.Error("failed to get revision", err.Error(), err)
+		return nil, err
+	}
+
+	return found, nil
+}
+
+func diffSnapshots(from models.SequenceSnapshot, to models.SequenceSnapshot) *dto.SequenceDiff {
+	diff := &dto.SequenceDiff{
+		Changes:      make([]*dto.FieldChange, 0),
+		AddedSteps:   make([]*dto.StepResponse, 0),
+		RemovedSteps: make([]*dto.StepResponse, 0),
+		ChangedSteps: make([]*dto.StepDiff, 0),
+	}
+
+	diff.Changes = appendChange(diff.Changes, "name", from.Name, to.Name)
+	diff.Changes = appendChange(diff.Changes, "openTrackingEnabled", from.OpenTrackingEnabled, to.OpenTrackingEnabled)
+	diff.Changes = appendChange(diff.Changes, "clickTrackingEnabled", from.ClickTrackingEnabled, to.ClickTrackingEnabled)
+	diff.Changes = appendChange(diff.Changes, "deleted", from.Deleted, to.Deleted)
+
+	if !maps.Equal(from.Variables, to.Variables) {
+		diff.Changes = append(diff.Changes, &dto.FieldChange{Field: "variables", From: from.Variables, To: to.Variables})
+	}
+
+	previous := make(map[uuid.UUID]*models.StepSnapshot, len(from.Steps))
+	for _, step := range from.Steps {
+		previous[step.ExternalID] = step
+	}
+
+	for _, step := range to.Steps {
+		old, ok := previous[step.ExternalID]
+		if !ok {
+			diff.AddedSteps = append(diff.AddedSteps, toStepSnapshotResponse(step))
+			continue
+		}
+
+		delete(previous, step.ExternalID)
+
+		changes := make([]*dto.FieldChange, 0)
+		changes = appendChange(changes, "stepNumber", old.StepNumber, step.StepNumber)
+		changes = appendChange(changes, "mailSubject", old.MailSubject, step.MailSubject)
+		changes = appendChange(changes, "mailContent", old.MailContent, step.MailContent)
+		changes = appendChange(changes, "mailText", old.MailText, step.MailText)
+		changes = appendChange(changes, "delayDays", old.DelayDays, step.DelayDays)
+		changes = appendChange(changes, "delayHours", old.DelayHours, step.DelayHours)
+		changes = appendChange(changes, "businessDaysOnly", old.BusinessDaysOnly, step.BusinessDaysOnly)
+		changes = appendSendWindowChange(changes, old, step)
+
+		if !slices.EqualFunc(old.Variants, step.Variants, func(a, b *models.VariantSnapshot) bool { return *a == *b }) {
+			changes = append(changes, &dto.FieldChange{Field: "variants", From: old.Variants, To: step.Variants})
+		}
+
+		if len(changes) > 0 {
+			diff.ChangedSteps = append(diff.ChangedSteps, &dto.StepDiff{ExternalID: step.ExternalID.String(), Changes: changes})
+		}
+	}
+
+	// keeps the removed steps in the order they had in the older revision
+	for _, step := range from.Steps {
+		if _, ok := previous[step.ExternalID]; ok {
+			diff.RemovedSteps = append(diff.RemovedSteps, toStepSnapshotResponse(step))
+		}
+	}
+
+	return diff
+}
+
+func appendChange[T comparable](changes []*dto.FieldChange, field string, from T, to T) []*dto.FieldChange {
+	if from == to {
+		return changes
+	}
+
+	return append(changes, &dto.FieldChange{Field: field, From: from, To: to})
+}
+
+// appendSendWindowChange compares the send windows by value since both bounds
+// are optional.
+func appendSendWindowChange(changes []*dto.FieldChange, from, to *models.StepSnapshot) []*dto.FieldChange {
+	fromWindow := dto.NewSendWindow(from.SendWindowStart, from.SendWindowEnd)
+	toWindow := dto.NewSendWindow(to.SendWindowStart, to.SendWindowEnd)
+
+	if fromWindow == nil && toWindow == nil {
+		return changes
+	}
+
+	if fromWindow != nil && toWindow != nil && *fromWindow == *toWindow {
+		return changes
+	}
+
+	return append(changes, &dto.FieldChange{Field: "sendWindow", From: fromWindow, To: toWindow})
+}
+
+func toRevisionResponse(revision *models.SequenceRevision) *dto.RevisionResponse {
+	response := &dto.RevisionResponse{
+		Revision:             int(revision.Revision),
+		Name:                 revision.Snapshot.Name,
+		OpenTrackingEnabled:  revision.Snapshot.OpenTrackingEnabled,
+		ClickTrackingEnabled: revision.Snapshot.ClickTrackingEnabled,
+		Variables:            revision.Snapshot.Variables,
+		Deleted:              revision.Snapshot.Deleted,
+		Steps:                make([]*dto.StepResponse, 0, len(revision.Snapshot.Steps)),
+		CreatedAt:            revision.Created.Format(time.RFC3339),
+	}
+
+	if response.Variables == nil {
+		response.Variables = make(map[string]string)
+	}
+
+	for _, step := range revision.Snapshot.Steps {
+		response.Steps = append(response.Steps, toStepSnapshotResponse(step))
+	}
+
+	return response
+}
+
+func toStepSnapshotResponse(step *models.StepSnapshot) *dto.StepResponse {
+	return &dto.StepResponse{
+		ExternalID:       step.ExternalID.String(),
+		StepNumber:       int(step.StepNumber),
+		MailSubject:      step.MailSubject,
+		MailContent:      step.MailContent,
+		MailHTML:         step.MailContent,
+		MailText:         plainText(step.MailContent, step.MailText),
+		DelayDays:        int(step.DelayDays),
+		DelayHours:       int(step.DelayHours),
+		BusinessDaysOnly: step.BusinessDaysOnly,
+		SendWindow:       dto.NewSendWindow(step.SendWindowStart, step.SendWindowEnd),
+		Variables:        referencedVariables(step.MailSubject, step.MailContent, step.MailText),
+	}
+}
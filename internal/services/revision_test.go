@@ -0,0 +1,315 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevisionService_GetRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1}, nil)
+		revisionRepository.EXPECT().FindAll(gomock.Any(), sequenceID, 10, 10).Return([]*models.SequenceRevision{
+			{Revision: 2, Created: time.Now(), Snapshot: models.SequenceSnapshot{Name: "new name"}},
+		}, nil)
+
+		res, err := revisionService.GetRevisions(context.Background(), sequenceID, 10, 1)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, 2, res[0].Revision)
+		assert.Equal(t, "new name", res[0].Name)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+		revisionRepository.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := revisionService.GetRevisions(context.Background(), sequenceID, 10, 0)
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1}, nil)
+		revisionRepository.EXPECT().FindAll(gomock.Any(), sequenceID, 10, 0).Return(nil, sql.ErrConnDone)
+
+		_, err := revisionService.GetRevisions(context.Background(), sequenceID, 10, 0)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestRevisionService_GetRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{
+			Revision: 1,
+			Created:  time.Now(),
+			Snapshot: models.SequenceSnapshot{
+				Name:  "name",
+				Steps: []*models.StepSnapshot{{ExternalID: stepID, StepNumber: 1, MailSubject: "subject", MailContent: "content"}},
+			},
+		}, nil)
+
+		res, err := revisionService.GetRevision(context.Background(), sequenceID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Revision)
+		assert.Equal(t, "name", res.Name)
+		assert.Equal(t, stepID.String(), res.Steps[0].ExternalID)
+		assert.NotEmpty(t, res.CreatedAt)
+	})
+
+	t.Run("return services.ErrorRevisionNotFound when revision search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(3)).Return(nil, pgx.ErrNoRows)
+
+		_, err := revisionService.GetRevision(context.Background(), sequenceID, 3)
+
+		assert.EqualError(t, err, services.ErrorRevisionNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(nil, sql.ErrConnDone)
+
+		_, err := revisionService.GetRevision(context.Background(), sequenceID, 1)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestRevisionService_DiffRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		keptID, changedID, removedID, addedID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		variants := []*models.VariantSnapshot{{ExternalID: uuid.New(), MailSubject: "subject B", MailContent: "content", Weight: 1}}
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{
+			Revision: 1,
+			Snapshot: models.SequenceSnapshot{
+				Name:                "name",
+				OpenTrackingEnabled: true,
+				Steps: []*models.StepSnapshot{
+					{ExternalID: keptID, StepNumber: 1, MailSubject: "subject", MailContent: "content"},
+					{ExternalID: changedID, StepNumber: 2, MailSubject: "subject", MailContent: "content"},
+					{ExternalID: removedID, StepNumber: 3, MailSubject: "subject", MailContent: "content"},
+				},
+			},
+		}, nil)
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(2)).Return(&models.SequenceRevision{
+			Revision: 2,
+			Snapshot: models.SequenceSnapshot{
+				Name:                "new name",
+				OpenTrackingEnabled: true,
+				Variables:           map[string]string{"product": "Mailbox"},
+				Steps: []*models.StepSnapshot{
+					{ExternalID: keptID, StepNumber: 1, MailSubject: "subject", MailContent: "content"},
+					{ExternalID: changedID, StepNumber: 3, MailSubject: "new subject", MailContent: "content", Variants: variants},
+					{ExternalID: addedID, StepNumber: 2, MailSubject: "subject", MailContent: "content"},
+				},
+			},
+		}, nil)
+
+		res, err := revisionService.DiffRevisions(context.Background(), sequenceID, 1, 2)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, res.From)
+		assert.Equal(t, 2, res.To)
+		assert.Equal(t, []*dto.FieldChange{
+			{Field: "name", From: "name", To: "new name"},
+			{Field: "variables", From: map[string]string(nil), To: map[string]string{"product": "Mailbox"}},
+		}, res.Changes)
+
+		assert.Len(t, res.AddedSteps, 1)
+		assert.Equal(t, addedID.String(), res.AddedSteps[0].ExternalID)
+
+		assert.Len(t, res.RemovedSteps, 1)
+		assert.Equal(t, removedID.String(), res.RemovedSteps[0].ExternalID)
+
+		assert.Equal(t, []*dto.StepDiff{{
+			ExternalID: changedID.String(),
+			Changes: []*dto.FieldChange{
+				{Field: "stepNumber", From: int32(2), To: int32(3)},
+				{Field: "mailSubject", From: "subject", To: "new subject"},
+				{Field: "variants", From: []*models.VariantSnapshot(nil), To: variants},
+			},
+		}}, res.ChangedSteps)
+	})
+
+	t.Run("return services.ErrorRevisionNotFound when one of the revisions does not exist", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{Revision: 1}, nil)
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(5)).Return(nil, pgx.ErrNoRows)
+
+		_, err := revisionService.DiffRevisions(context.Background(), sequenceID, 1, 5)
+
+		assert.EqualError(t, err, services.ErrorRevisionNotFound.Error())
+	})
+}
+
+func TestRevisionService_RollbackRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{
+			Revision: 1,
+			Snapshot: models.SequenceSnapshot{
+				Name:                 "name",
+				ClickTrackingEnabled: true,
+				Steps:                []*models.StepSnapshot{{ExternalID: stepID, StepNumber: 1, MailSubject: "subject", MailContent: "content"}},
+			},
+		}, nil)
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ExternalID: sequenceID, Name: "renamed"}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), &models.SequenceWithSteps{
+			ExternalID:           sequenceID,
+			Name:                 "name",
+			ClickTrackingEnabled: true,
+			Steps:                []*dao.Step{{ExternalID: stepID, StepNumber: 1, MailSubject: "subject", MailContent: "content"}},
+			Variants:             map[uuid.UUID][]*models.StepVariant{},
+		}).Return(nil)
+
+		res, err := revisionService.RollbackRevision(context.Background(), sequenceID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "name", res.Name)
+		assert.Equal(t, stepID.String(), res.Steps[0].ExternalID)
+	})
+
+	t.Run("should restore the variants of the revision", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+		otherStepID := uuid.New()
+		variantID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{
+			Revision: 1,
+			Snapshot: models.SequenceSnapshot{
+				Name: "name",
+				Steps: []*models.StepSnapshot{
+					{
+						ExternalID:  stepID,
+						StepNumber:  1,
+						MailSubject: "subject",
+						MailContent: "content",
+						Variants:    []*models.VariantSnapshot{{ExternalID: variantID, MailSubject: "subject A", MailContent: "content A", Weight: 2}},
+					},
+					{ExternalID: otherStepID, StepNumber: 2, MailSubject: "subject", MailContent: "content"},
+				},
+			},
+		}, nil)
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.SequenceWithSteps) error {
+			assert.Equal(t, map[uuid.UUID][]*models.StepVariant{
+				stepID: {{ExternalID: variantID, MailSubject: "subject A", MailContent: "content A", Weight: 2}},
+			}, model.Variants)
+			return nil
+		})
+
+		_, err := revisionService.RollbackRevision(context.Background(), sequenceID, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return services.ErrorRevisionNotFound when revision search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(nil, pgx.ErrNoRows)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := revisionService.RollbackRevision(context.Background(), sequenceID, 1)
+
+		assert.EqualError(t, err, services.ErrorRevisionNotFound.Error())
+	})
+
+	t.Run("return general error in general cases when replace", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		revisionRepository := mocks.NewMockRevisionRepository(ctrl)
+		revisionService := services.NewRevisionService(sequenceRepository, revisionRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		revisionRepository.EXPECT().FindOne(gomock.Any(), sequenceID, int32(1)).Return(&models.SequenceRevision{Revision: 1}, nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := revisionService.RollbackRevision(context.Background(), sequenceID, 1)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+type RoleBindingService interface {
+	GetRoleBindings(ctx context.Context) ([]*dto.RoleBindingResponse, error)
+	CreateRoleBinding(ctx context.Context, req dto.CreateRoleBindingRequest) (*dto.RoleBindingResponse, error)
+	DeleteRoleBinding(ctx context.Context, id uuid.UUID) error
+}
+
+// roleBindingService manages the role bindings of the workspace, which only
+// its admins may do.
+type roleBindingService struct {
+	sequenceRepository    repository.SequenceRepository
+	roleBindingRepository repository.RoleBindingRepository
+	authorizer            authorizer
+}
+
+func NewRoleBindingService(sequenceRepository repository.SequenceRepository, roleBindingRepository repository.RoleBindingRepository) RoleBindingService {
+	return &roleBindingService{sequenceRepository: sequenceRepository, roleBindingRepository: roleBindingRepository, authorizer: authorizer{roleBindingRepository: roleBindingRepository}}
+}
+
+func (s *roleBindingService) GetRoleBindings(ctx context.Context) ([]*dto.RoleBindingResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	bindings, err := s.roleBindingRepository.FindAll(ctx)
+	if err != nil {
+		slog.Error("failed to get role bindings", err.Error(), err)
+		return nil, err
+	}
+
+	response := make([]*dto.RoleBindingResponse, 0, len(bindings))
+	for _, binding := range bindings {
+		response = append(response, toRoleBindingResponse(binding))
+	}
+
+	return response, nil
+}
+
+// CreateRoleBinding grants the role to the user in the sequence of the request,
+// or in the whole workspace without one. A user has a single role in each of
+// them, so the role replaces the one the user had there.
+func (s *roleBindingService) CreateRoleBinding(ctx context.Context, req dto.CreateRoleBindingRequest) (*dto.RoleBindingResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	binding := &models.RoleBinding{
+		UserID: req.UserID,
+		Role:   models.Role(req.Role),
+	}
+
+	if req.SequenceID != nil {
+		sequence, err := s.sequenceRepository.FindByExternalId(ctx, *req.SequenceID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, ErrorSequenceNotFound
+			}
+			slog.Error("failed to get sequence", err.Error(), err)
+			return nil, err
+		}
+
+		binding.SequenceID = &sequence.ID
+		binding.SequenceExternalID = &sequence.ExternalID
+	}
+
+	if err := s.roleBindingRepository.Save(ctx, binding); err != nil {
+		slog.Error("failed to save role binding", err.Error(), err)
+		return nil, err
+	}
+
+	return toRoleBindingResponse(binding), nil
+}
+
+func (s *roleBindingService) DeleteRoleBinding(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.roleBindingRepository.Delete(ctx, id); err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrorRoleBindingNotFound
+		}
+		slog.Error("failed to delete role binding", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+func toRoleBindingResponse(binding *models.RoleBinding) *dto.RoleBindingResponse {
+	response := &dto.RoleBindingResponse{
+		ExternalID: binding.ExternalID.String(),
+		UserID:     binding.UserID,
+		Role:       string(binding.Role),
+		CreatedAt:  binding.Created.Format(time.RFC3339),
+	}
+
+	if binding.SequenceExternalID != nil {
+		sequenceID := binding.SequenceExternalID.String()
+		response.SequenceID = &sequenceID
+	}
+
+	return response
+}
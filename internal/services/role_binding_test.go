@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoleBindingService_GetRoleBindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		sequenceID := uuid.New()
+
+		roleBindingRepository.EXPECT().FindAll(gomock.Any()).Return([]*models.RoleBinding{
+			{ExternalID: uuid.New(), UserID: "user-1", Role: models.RoleAdmin, Created: time.Now()},
+			{ExternalID: uuid.New(), UserID: "user-2", Role: models.RoleEditor, SequenceExternalID: &sequenceID, Created: time.Now()},
+		}, nil)
+
+		res, err := roleBindingService.GetRoleBindings(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "admin", res[0].Role)
+		assert.Nil(t, res[0].SequenceID)
+		assert.Equal(t, sequenceID.String(), *res[1].SequenceID)
+	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user is not an admin", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"editor"}})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", nil, nil).Return([]models.Role{models.RoleEditor}, nil)
+
+		res, err := roleBindingService.GetRoleBindings(ctx)
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+}
+
+func TestRoleBindingService_CreateRoleBinding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "owner", Roles: []string{"admin"}})
+
+		roleBindingRepository.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, binding *models.RoleBinding) error {
+			assert.Nil(t, binding.SequenceID)
+			binding.ExternalID = uuid.New()
+			binding.Created = time.Now()
+			return nil
+		})
+
+		res, err := roleBindingService.CreateRoleBinding(ctx, dto.CreateRoleBindingRequest{UserID: "user-1", Role: "editor"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ExternalID)
+		assert.Equal(t, "user-1", res.UserID)
+		assert.Equal(t, "editor", res.Role)
+		assert.Nil(t, res.SequenceID)
+	})
+
+	t.Run("should bind the role to the sequence of the request", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 7, ExternalID: sequenceID}, nil)
+		roleBindingRepository.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, binding *models.RoleBinding) error {
+			assert.Equal(t, int32(7), *binding.SequenceID)
+			binding.ExternalID = uuid.New()
+			return nil
+		})
+
+		res, err := roleBindingService.CreateRoleBinding(context.Background(), dto.CreateRoleBindingRequest{UserID: "user-1", Role: "viewer", SequenceID: &sequenceID})
+		assert.NoError(t, err)
+		assert.Equal(t, sequenceID.String(), *res.SequenceID)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		_, err := roleBindingService.CreateRoleBinding(context.Background(), dto.CreateRoleBindingRequest{UserID: "user-1", Role: "viewer", SequenceID: &sequenceID})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		roleBindingRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := roleBindingService.CreateRoleBinding(context.Background(), dto.CreateRoleBindingRequest{UserID: "user-1", Role: "viewer"})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestRoleBindingService_DeleteRoleBinding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		id := uuid.New()
+
+		roleBindingRepository.EXPECT().Delete(gomock.Any(), id).Return(nil)
+
+		err := roleBindingService.DeleteRoleBinding(context.Background(), id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return services.ErrorRoleBindingNotFound when delete fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		roleBindingService := services.NewRoleBindingService(sequenceRepository, roleBindingRepository)
+
+		id := uuid.New()
+
+		roleBindingRepository.EXPECT().Delete(gomock.Any(), id).Return(pgx.ErrNoRows)
+
+		err := roleBindingService.DeleteRoleBinding(context.Background(), id)
+
+		assert.EqualError(t, err, services.ErrorRoleBindingNotFound.Error())
+	})
+}
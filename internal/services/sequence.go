@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,24 +14,58 @@ import (
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/murilo-bracero/sequence-technical-test/internal/models"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
 )
 
 type SequenceService interface {
 	GetSequences(ctx context.Context, size int, page int) ([]*dto.SequenceResponse, error)
+	GetSequencesPage(ctx context.Context, req dto.SequencePageRequest) (*dto.SequencePageResponse, error)
 	GetSequence(ctx context.Context, id uuid.UUID) (*dto.SequenceResponse, error)
-	UpdateSequence(ctx context.Context, id uuid.UUID, req dto.UpdateSequenceRequest) (*dto.SequenceResponse, error)
+	UpdateSequence(ctx context.Context, id uuid.UUID, version int32, req dto.UpdateSequenceRequest) (*dto.SequenceResponse, error)
+	ReplaceSequence(ctx context.Context, id uuid.UUID, version int32, req dto.ReplaceSequenceRequest) (*dto.SequenceResponse, error)
 	CreateSequence(ctx context.Context, req dto.CreateSequenceRequest) (*dto.SequenceResponse, error)
+	DeleteSequence(ctx context.Context, id uuid.UUID, version int32) error
+	GetDeletedSequences(ctx context.Context, size int, page int) ([]*dto.SequenceResponse, error)
+	RestoreSequence(ctx context.Context, id uuid.UUID) (*dto.SequenceResponse, error)
+	PurgeDeletedSequences(ctx context.Context, retention time.Duration) (int64, error)
+	TransitionSequence(ctx context.Context, id uuid.UUID, action string) (*dto.SequenceResponse, error)
+	CloneSequence(ctx context.Context, id uuid.UUID, req dto.CloneSequenceRequest) (*dto.SequenceResponse, error)
+}
+
+type transition struct {
+	from []string
+	to   string
+}
+
+// sequenceTransitions is the lifecycle of the sequences by action. Sequences
+// start as draft and archived is final.
+var sequenceTransitions = map[string]transition{
+	"activate": {from: []string{models.StatusDraft}, to: models.StatusActive},
+	"pause":    {from: []string{models.StatusActive}, to: models.StatusPaused},
+	"resume":   {from: []string{models.StatusPaused}, to: models.StatusActive},
+	"archive":  {from: []string{models.StatusDraft, models.StatusActive, models.StatusPaused}, to: models.StatusArchived},
 }
 
 type sequenceService struct {
 	sequenceRepository repository.SequenceRepository
+	authorizer         authorizer
+	auditor            auditor
 }
 
-func NewSequenceService(sequenceRepository repository.SequenceRepository) SequenceService {
-	return &sequenceService{sequenceRepository: sequenceRepository}
+func NewSequenceService(sequenceRepository repository.SequenceRepository, roleBindingRepository repository.RoleBindingRepository, transactor repository.Transactor, auditRepository repository.AuditRepository) SequenceService {
+	return &sequenceService{
+		sequenceRepository: sequenceRepository,
+		authorizer:         authorizer{roleBindingRepository: roleBindingRepository},
+		auditor:            auditor{transactor: transactor, auditRepository: auditRepository},
+	}
 }
 
 func (s *sequenceService) GetSequences(ctx context.Context, size int, page int) ([]*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
 	sequences, err := s.sequenceRepository.FindAll(ctx, size, size*page)
 	if err != nil {
 		slog.Error("failed to get sequences", err.Error(), err)
@@ -37,40 +74,99 @@ func (s *sequenceService) GetSequences(ctx context.Context, size int, page int)
 
 	response := make([]*dto.SequenceResponse, 0, len(sequences))
 	for _, s := range sequences {
-		sr := &dto.SequenceResponse{
-			ExternalID:           s.ExternalID.String(),
-			Name:                 s.Name,
-			OpenTrackingEnabled:  s.OpenTrackingEnabled,
-			ClickTrackingEnabled: s.ClickTrackingEnabled,
-			CreatedAt:            s.Created.Format(time.RFC3339),
-			Steps:                make([]*dto.StepResponse, 0, len(s.Steps)),
+		response = append(response, toSequenceResponse(s))
+	}
+
+	return response, nil
+}
+
+func (s *sequenceService) GetSequencesPage(ctx context.Context, req dto.SequencePageRequest) (*dto.SequencePageResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	sortBy, descending := req.SortBy()
+
+	filter := models.SequenceFilter{
+		Name:                 req.Name,
+		OpenTrackingEnabled:  req.OpenTrackingEnabled,
+		ClickTrackingEnabled: req.ClickTrackingEnabled,
+		CreatedAfter:         req.CreatedAfter,
+		CreatedBefore:        req.CreatedBefore,
+		UpdatedAfter:         req.UpdatedAfter,
+		UpdatedBefore:        req.UpdatedBefore,
+		Search:               req.Search,
+		SortBy:               sortBy,
+		Descending:           descending,
+	}
+
+	var cursor *utils.Cursor
+
+	if req.Cursor != "" {
+		c, err := utils.DecodeCursor(req.Cursor)
+		if err != nil || c.Sort != sortBy || !validCursorValue(sortBy, c.Value) {
+			return nil, ErrorInvalidCursor
 		}
+		cursor = c
+	}
+
+	backward := cursor != nil && cursor.Backward
+
+	// fetches one extra sequence to know if there is another page after this one
+	sequences, err := s.sequenceRepository.FindPage(ctx, filter, cursor, req.Limit+1)
+	if err != nil {
+		slog.Error("failed to get sequences page", err.Error(), err)
+		return nil, err
+	}
 
-		if s.Updated != nil {
-			updated := s.Updated.Format(time.RFC3339)
-			sr.LastUpdatedAt = &updated
+	hasMore := len(sequences) > req.Limit
+	if hasMore {
+		if backward {
+			sequences = sequences[1:]
+		} else {
+			sequences = sequences[:req.Limit]
 		}
+	}
 
-		for _, step := range s.Steps {
-			if step == nil {
-				continue
-			}
+	response := &dto.SequencePageResponse{
+		Items: make([]*dto.SequenceResponse, 0, len(sequences)),
+	}
 
-			sr.Steps = append(sr.Steps, &dto.StepResponse{
-				ExternalID:  step.ExternalID.String(),
-				StepNumber:  int(step.StepNumber),
-				MailSubject: step.MailSubject,
-				MailContent: step.MailContent,
-			})
+	for _, s := range sequences {
+		response.Items = append(response.Items, toSequenceResponse(s))
+	}
+
+	if len(sequences) > 0 {
+		first, last := sequences[0], sequences[len(sequences)-1]
+
+		if backward || hasMore {
+			next := utils.EncodeCursor(utils.Cursor{Sort: sortBy, Value: last.SortValue(sortBy), ID: last.ID})
+			response.NextCursor = &next
 		}
 
-		response = append(response, sr)
+		if (backward && hasMore) || (!backward && cursor != nil) {
+			prev := utils.EncodeCursor(utils.Cursor{Sort: sortBy, Value: first.SortValue(sortBy), ID: first.ID, Backward: true})
+			response.PrevCursor = &prev
+		}
+	}
+
+	if req.IncludeTotalCount {
+		total, err := s.sequenceRepository.Count(ctx, filter)
+		if err != nil {
+			slog.Error("failed to count sequences", err.Error(), err)
+			return nil, err
+		}
+		response.TotalCount = &total
 	}
 
 	return response, nil
 }
 
 func (s *sequenceService) GetSequence(ctx context.Context, id uuid.UUID) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, id); err != nil {
+		return nil, err
+	}
+
 	sequence, err := s.sequenceRepository.FindByExternalId(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -81,36 +177,16 @@ func (s *sequenceService) GetSequence(ctx context.Context, id uuid.UUID) (*dto.S
 		return nil, err
 	}
 
-	response := &dto.SequenceResponse{
-		ExternalID:           sequence.ExternalID.String(),
-		Name:                 sequence.Name,
-		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
-		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
-		CreatedAt:            sequence.Created.Format(time.RFC3339),
-		Steps:                make([]*dto.StepResponse, 0, len(sequence.Steps)),
-	}
-
-	if sequence.Updated != nil {
-		updated := sequence.Updated.Format(time.RFC3339)
-		response.LastUpdatedAt = &updated
-	}
+	return toSequenceResponse(sequence), nil
+}
 
-	for _, step := range sequence.Steps {
-		if step == nil {
-			continue
-		}
-		response.Steps = append(response.Steps, &dto.StepResponse{
-			ExternalID:  step.ExternalID.String(),
-			StepNumber:  int(step.StepNumber),
-			MailSubject: step.MailSubject,
-			MailContent: step.MailContent,
-		})
+// UpdateSequence applies the fields present in the request, a version other
+// than 0 must match the current version of the sequence.
+func (s *sequenceService) UpdateSequence(ctx context.Context, id uuid.UUID, version int32, req dto.UpdateSequenceRequest) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, id); err != nil {
+		return nil, err
 	}
 
-	return response, nil
-}
-
-func (s *sequenceService) UpdateSequence(ctx context.Context, id uuid.UUID, req dto.UpdateSequenceRequest) (*dto.SequenceResponse, error) {
 	sequence, err := s.sequenceRepository.FindByExternalId(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -120,6 +196,16 @@ func (s *sequenceService) UpdateSequence(ctx context.Context, id uuid.UUID, req
 		return nil, err
 	}
 
+	if version != 0 && version != sequence.Version {
+		return nil, ErrorVersionMismatch
+	}
+
+	before := toSequenceResponse(sequence)
+
+	if req.Name != nil {
+		sequence.Name = *req.Name
+	}
+
 	if req.OpenTrackingEnabled != nil {
 		sequence.OpenTrackingEnabled = *req.OpenTrackingEnabled
 	}
@@ -128,77 +214,460 @@ func (s *sequenceService) UpdateSequence(ctx context.Context, id uuid.UUID, req
 		sequence.ClickTrackingEnabled = *req.ClickTrackingEnabled
 	}
 
-	if err := s.sequenceRepository.Update(ctx, sequence); err != nil {
+	if req.Variables != nil {
+		sequence.Variables = req.Variables
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.Update(ctx, sequence); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionUpdate, auditTargetSequence, id, before, toSequenceResponse(sequence))
+	})
+	if err != nil {
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		// the sequence changed after it was read above
+		if err == repository.ErrVersionConflict {
+			return nil, ErrorVersionMismatch
+		}
 		slog.Error("failed to update sequence", err.Error(), err)
 		return nil, err
 	}
 
-	response := &dto.SequenceResponse{
-		ExternalID:           sequence.ExternalID.String(),
-		Name:                 sequence.Name,
-		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
-		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
-		CreatedAt:            sequence.Created.Format(time.RFC3339),
+	return toSequenceResponse(sequence), nil
+}
+
+// ReplaceSequence overwrites the sequence and all of its steps with the
+// request, steps referencing an id must already belong to the sequence. A
+// version other than 0 must match the current version of the sequence.
+func (s *sequenceService) ReplaceSequence(ctx context.Context, id uuid.UUID, version int32, req dto.ReplaceSequenceRequest) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, id); err != nil {
+		return nil, err
 	}
 
-	if sequence.Updated != nil {
-		updated := sequence.Updated.Format(time.RFC3339)
-		response.LastUpdatedAt = &updated
+	current, err := s.sequenceRepository.FindByExternalId(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to get sequence during replaceSequence", err.Error(), err)
+		return nil, err
 	}
 
-	for _, step := range sequence.Steps {
-		if step == nil {
-			continue
+	if version != 0 && version != current.Version {
+		return nil, ErrorVersionMismatch
+	}
+
+	stepIDs := make(map[uuid.UUID]bool, len(current.Steps))
+	for _, step := range current.Steps {
+		stepIDs[step.ExternalID] = true
+	}
+
+	sequence := models.SequenceWithSteps{
+		ExternalID:           id,
+		Name:                 req.Name,
+		OpenTrackingEnabled:  req.OpenTrackingEnabled,
+		ClickTrackingEnabled: req.ClickTrackingEnabled,
+		Variables:            req.Variables,
+		Version:              version,
+		Steps:                make([]*dao.Step, 0, len(req.Steps)),
+	}
+
+	for _, step := range req.Steps {
+		model := toStep(&step.CreateStepRequest)
+
+		if step.ExternalID != nil {
+			if !stepIDs[*step.ExternalID] {
+				return nil, ErrorStepNotInSequence
+			}
+			model.ExternalID = *step.ExternalID
 		}
-		response.Steps = append(response.Steps, &dto.StepResponse{
-			ExternalID:  step.ExternalID.String(),
-			StepNumber:  int(step.StepNumber),
-			MailSubject: step.MailSubject,
-			MailContent: step.MailContent,
-		})
+
+		sequence.Steps = append(sequence.Steps, model)
 	}
 
-	return response, nil
+	var replaced *models.SequenceWithSteps
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.Replace(ctx, &sequence); err != nil {
+			return err
+		}
+
+		// reads the sequence back, the model lacks the versions of its steps
+		found, err := s.sequenceRepository.FindByExternalId(ctx, id)
+		if err != nil {
+			return err
+		}
+		replaced = found
+
+		return s.auditor.record(ctx, auditActionReplace, auditTargetSequence, id, toSequenceResponse(current), toSequenceResponse(replaced))
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		if err == repository.ErrVersionConflict {
+			return nil, ErrorVersionMismatch
+		}
+		slog.Error("failed to replace sequence", err.Error(), err)
+		return nil, err
+	}
+
+	return toSequenceResponse(replaced), nil
 }
 
 func (s *sequenceService) CreateSequence(ctx context.Context, req dto.CreateSequenceRequest) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
 	sequence := models.SequenceWithSteps{
 		Name:                 req.Name,
 		OpenTrackingEnabled:  req.OpenTrackingEnabled,
 		ClickTrackingEnabled: req.ClickTrackingEnabled,
+		Variables:            req.Variables,
 		Steps:                make([]*dao.Step, 0, len(req.Steps)),
 	}
 
 	for _, step := range req.Steps {
-		sequence.Steps = append(sequence.Steps, &dao.Step{
-			StepNumber:  int32(step.StepNumber),
-			MailSubject: step.MailSubject,
-			MailContent: step.MailContent,
-		})
+		sequence.Steps = append(sequence.Steps, toStep(step))
 	}
 
-	if err := s.sequenceRepository.Create(ctx, &sequence); err != nil {
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.Create(ctx, &sequence); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionCreate, auditTargetSequence, sequence.ExternalID, nil, toSequenceResponse(&sequence))
+	})
+	if err != nil {
 		slog.Error("failed to create sequence", err.Error(), err)
 		return nil, err
 	}
 
+	return toSequenceResponse(&sequence), nil
+}
+
+// DeleteSequence moves the sequence to the trash, a version other than 0 must
+// match the current version of the sequence.
+func (s *sequenceService) DeleteSequence(ctx context.Context, id uuid.UUID, version int32) error {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, id); err != nil {
+		return err
+	}
+
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.sequenceRepository.FindByExternalId(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sequenceRepository.Delete(ctx, id, version); err != nil {
+			return err
+		}
+
+		return s.auditor.record(ctx, auditActionDelete, auditTargetSequence, id, toSequenceResponse(before), nil)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrorSequenceNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return ErrorSequenceNotEditable
+		}
+		if err == repository.ErrVersionConflict {
+			return ErrorVersionMismatch
+		}
+		slog.Error("failed to delete sequence", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *sequenceService) GetDeletedSequences(ctx context.Context, size int, page int) ([]*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	sequences, err := s.sequenceRepository.FindAllDeleted(ctx, size, size*page)
+	if err != nil {
+		slog.Error("failed to get deleted sequences", err.Error(), err)
+		return nil, err
+	}
+
+	response := make([]*dto.SequenceResponse, 0, len(sequences))
+	for _, s := range sequences {
+		response = append(response, toSequenceResponse(s))
+	}
+
+	return response, nil
+}
+
+func (s *sequenceService) RestoreSequence(ctx context.Context, id uuid.UUID) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, id); err != nil {
+		return nil, err
+	}
+
+	var sequence *models.SequenceWithSteps
+
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.sequenceRepository.FindDeletedByExternalId(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sequenceRepository.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		restored, err := s.sequenceRepository.FindByExternalId(ctx, id)
+		if err != nil {
+			return err
+		}
+		sequence = restored
+
+		return s.auditor.record(ctx, auditActionRestore, auditTargetSequence, id, toSequenceResponse(before), toSequenceResponse(sequence))
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to restore sequence", err.Error(), err)
+		return nil, err
+	}
+
+	return toSequenceResponse(sequence), nil
+}
+
+// PurgeDeletedSequences permanently removes every sequence that has been in the
+// trash for longer than the given retention, returning how many were removed.
+// The removals are recorded as made by the system.
+func (s *sequenceService) PurgeDeletedSequences(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged []*models.SequenceWithSteps
+
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		sequences, err := s.sequenceRepository.Purge(ctx, retention)
+		if err != nil {
+			return err
+		}
+		purged = sequences
+
+		for _, sequence := range purged {
+			if err := s.auditor.recordSystem(ctx, auditActionPurge, auditTargetSequence, sequence.ExternalID, toSequenceResponse(sequence), nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		slog.Error("failed to purge deleted sequences", err.Error(), err)
+		return 0, err
+	}
+
+	return int64(len(purged)), nil
+}
+
+// TransitionSequence applies the lifecycle action to the sequence, failing with
+// ErrorInvalidTransition when the current status does not allow it. Sequences
+// only become active with at least one step and templates that parse.
+func (s *sequenceService) TransitionSequence(ctx context.Context, id uuid.UUID, action string) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, id); err != nil {
+		return nil, err
+	}
+
+	t, ok := sequenceTransitions[action]
+	if !ok {
+		return nil, ErrorUnknownTransition
+	}
+
+	sequence, err := s.sequenceRepository.FindByExternalId(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to get sequence during transitionSequence", err.Error(), err)
+		return nil, err
+	}
+
+	if !slices.Contains(t.from, sequence.Status) {
+		return nil, fmt.Errorf("%w: cannot %s a sequence that is %s", ErrorInvalidTransition, action, sequence.Status)
+	}
+
+	if t.to == models.StatusActive {
+		if err := validateActivation(sequence); err != nil {
+			return nil, err
+		}
+	}
+
+	before := toSequenceResponse(sequence)
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.UpdateStatus(ctx, sequence, t.to); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, action, auditTargetSequence, id, before, toSequenceResponse(sequence))
+	})
+	if err != nil {
+		// the sequence was deleted or changed status since it was read
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%w: sequence changed while applying %s", ErrorInvalidTransition, action)
+		}
+		slog.Error("failed to update sequence status", err.Error(), err)
+		return nil, err
+	}
+
+	return toSequenceResponse(sequence), nil
+}
+
+// CloneSequence copies the sequence with its steps and their variants into a
+// new draft, named after the cloned sequence with a " (copy)" suffix unless
+// the request names it.
+func (s *sequenceService) CloneSequence(ctx context.Context, id uuid.UUID, req dto.CloneSequenceRequest) (*dto.SequenceResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, id); err != nil {
+		return nil, err
+	}
+
+	// the copy is a new sequence of the workspace
+	if err := s.authorizer.authorizeWorkspace(ctx, models.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	source, err := s.sequenceRepository.FindByExternalId(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to get sequence during cloneSequence", err.Error(), err)
+		return nil, err
+	}
+
+	sequence := models.SequenceWithSteps{
+		Name:                 source.Name + " (copy)",
+		OpenTrackingEnabled:  source.OpenTrackingEnabled,
+		ClickTrackingEnabled: source.ClickTrackingEnabled,
+		SourceID:             &source.ExternalID,
+	}
+
+	if req.Name != nil {
+		sequence.Name = *req.Name
+	}
+
+	if req.OpenTrackingEnabled != nil {
+		sequence.OpenTrackingEnabled = *req.OpenTrackingEnabled
+	}
+
+	if req.ClickTrackingEnabled != nil {
+		sequence.ClickTrackingEnabled = *req.ClickTrackingEnabled
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.sequenceRepository.Clone(ctx, &sequence); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionClone, auditTargetSequence, sequence.ExternalID, nil, toSequenceResponse(&sequence))
+	})
+	if err != nil {
+		// the sequence was deleted since it was read above
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		slog.Error("failed to clone sequence", err.Error(), err)
+		return nil, err
+	}
+
+	return toSequenceResponse(&sequence), nil
+}
+
+// validateActivation checks the rules a sequence must follow before it starts
+// sending, steps stored before templates were validated may not parse.
+func validateActivation(sequence *models.SequenceWithSteps) error {
+	steps := 0
+
+	for _, step := range sequence.Steps {
+		if step == nil {
+			continue
+		}
+		steps++
+
+		fields := []struct{ name, src string }{
+			{"mail subject", step.MailSubject},
+			{"mail content", step.MailContent},
+			{"mail text", step.MailText},
+		}
+
+		for _, field := range fields {
+			if _, err := templating.Parse(field.src); err != nil {
+				return fmt.Errorf("%w: step %d %s: %s", ErrorInvalidTemplate, step.StepNumber, field.name, err)
+			}
+		}
+	}
+
+	if steps == 0 {
+		return ErrorSequenceHasNoSteps
+	}
+
+	return nil
+}
+
+// validCursorValue reports whether a cursor value has the format of the sort
+// key it claims to point at, so a tampered cursor never reaches the queries.
+func validCursorValue(sortBy, value string) bool {
+	switch sortBy {
+	case models.SortByName:
+		return true
+	case models.SortByStepCount:
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	default:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	}
+}
+
+func toSequenceResponse(sequence *models.SequenceWithSteps) *dto.SequenceResponse {
 	response := &dto.SequenceResponse{
 		ExternalID:           sequence.ExternalID.String(),
 		Name:                 sequence.Name,
 		OpenTrackingEnabled:  sequence.OpenTrackingEnabled,
 		ClickTrackingEnabled: sequence.ClickTrackingEnabled,
+		Variables:            sequence.Variables,
+		Status:               sequence.Status,
+		Version:              sequence.Version,
 		CreatedAt:            sequence.Created.Format(time.RFC3339),
-		Steps:                make([]*dto.StepResponse, 0, len(req.Steps)),
+		Steps:                make([]*dto.StepResponse, 0, len(sequence.Steps)),
+	}
+
+	if response.Variables == nil {
+		response.Variables = make(map[string]string)
+	}
+
+	if sequence.Updated != nil {
+		updated := sequence.Updated.Format(time.RFC3339)
+		response.LastUpdatedAt = &updated
+	}
+
+	if sequence.Deleted != nil {
+		deleted := sequence.Deleted.Format(time.RFC3339)
+		response.DeletedAt = &deleted
+	}
+
+	if sequence.SourceID != nil {
+		source := sequence.SourceID.String()
+		response.SourceSequenceID = &source
 	}
 
 	for _, step := range sequence.Steps {
-		response.Steps = append(response.Steps, &dto.StepResponse{
-			ExternalID:  step.ExternalID.String(),
-			StepNumber:  int(step.StepNumber),
-			MailSubject: step.MailSubject,
-			MailContent: step.MailContent,
-		})
+		if step == nil {
+			continue
+		}
+
+		response.Steps = append(response.Steps, toStepResponse(step))
 	}
 
-	return response, nil
+	return response
 }
@@ -8,11 +8,14 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
 	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -22,7 +25,7 @@ func TestSequeceService_GetSequences(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceRepository.EXPECT().FindAll(gomock.Any(), 10, 10).Return([]*models.SequenceWithSteps{
 			{
@@ -59,7 +62,7 @@ func TestSequeceService_GetSequences(t *testing.T) {
 
 	t.Run("return general error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceRepository.EXPECT().FindAll(gomock.Any(), 10, 10).Return(nil, sql.ErrConnDone)
 
@@ -69,12 +72,178 @@ func TestSequeceService_GetSequences(t *testing.T) {
 	})
 }
 
+func TestSequeceService_GetSequencesPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newSequences := func(n int) []*models.SequenceWithSteps {
+		sequences := make([]*models.SequenceWithSteps, 0, n)
+		created := time.Date(2025, 8, 31, 16, 0, 0, 0, time.UTC)
+		for i := range n {
+			sequences = append(sequences, &models.SequenceWithSteps{
+				ID:         int32(i + 1),
+				ExternalID: uuid.New(),
+				Name:       "name",
+				Created:    created.Add(time.Duration(i) * time.Minute),
+			})
+		}
+		return sequences
+	}
+
+	t.Run("first page with more sequences after it", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequences := newSequences(3)
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), nil, 3).Return(sequences, nil)
+		sequenceRepository.EXPECT().Count(gomock.Any(), gomock.Any()).Times(0)
+
+		res, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 2)
+		assert.Nil(t, res.PrevCursor)
+		assert.Nil(t, res.TotalCount)
+
+		next, err := utils.DecodeCursor(*res.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), next.ID)
+		assert.False(t, next.Backward)
+	})
+
+	t.Run("last page reached with a cursor", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequences := newSequences(3)[2:]
+		cursor := utils.EncodeCursor(utils.Cursor{Sort: models.SortByCreated, Value: sequences[0].Created.Add(-time.Minute).Format(time.RFC3339Nano), ID: 2})
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), 3).Return(sequences, nil)
+
+		res, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Cursor: cursor, Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 1)
+		assert.Nil(t, res.NextCursor)
+
+		prev, err := utils.DecodeCursor(*res.PrevCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), prev.ID)
+		assert.True(t, prev.Backward)
+	})
+
+	t.Run("backward page drops the extra sequence at the start", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequences := newSequences(3)
+		cursor := utils.EncodeCursor(utils.Cursor{Sort: models.SortByCreated, Value: time.Now().Format(time.RFC3339Nano), ID: 4, Backward: true})
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), 3).Return(sequences, nil)
+
+		res, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Cursor: cursor, Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 2)
+		assert.Equal(t, sequences[1].ExternalID.String(), res.Items[0].ExternalID)
+		assert.NotNil(t, res.PrevCursor)
+		assert.NotNil(t, res.NextCursor)
+	})
+
+	t.Run("includes total count when requested", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), nil, 51).Return(newSequences(1), nil)
+		sequenceRepository.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		res, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Limit: 50, IncludeTotalCount: true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), *res.TotalCount)
+	})
+
+	t.Run("return services.ErrorInvalidCursor when cursor cannot be decoded", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Cursor: "invalid!", Limit: 2})
+
+		assert.EqualError(t, err, services.ErrorInvalidCursor.Error())
+	})
+
+	t.Run("sorts and filters with the requested parameters", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequences := newSequences(3)
+		name := "name"
+		search := "welcome"
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), models.SequenceFilter{
+			Name:       &name,
+			Search:     &search,
+			SortBy:     models.SortByName,
+			Descending: true,
+		}, nil, 3).Return(sequences, nil)
+
+		res, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{
+			Limit:  2,
+			Name:   &name,
+			Search: &search,
+			Sort:   "name:desc",
+		})
+		assert.NoError(t, err)
+
+		next, err := utils.DecodeCursor(*res.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, models.SortByName, next.Sort)
+		assert.Equal(t, "name", next.Value)
+		assert.Equal(t, int32(2), next.ID)
+	})
+
+	t.Run("return services.ErrorInvalidCursor when cursor was issued for another sort", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		cursor := utils.EncodeCursor(utils.Cursor{Sort: models.SortByName, Value: "name", ID: 2})
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Cursor: cursor, Limit: 2, Sort: "created"})
+
+		assert.EqualError(t, err, services.ErrorInvalidCursor.Error())
+	})
+
+	t.Run("return services.ErrorInvalidCursor when cursor value does not match the sort", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		cursor := utils.EncodeCursor(utils.Cursor{Sort: models.SortByCreated, Value: "yesterday", ID: 2})
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Cursor: cursor, Limit: 2})
+
+		assert.EqualError(t, err, services.ErrorInvalidCursor.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), nil, 3).Return(nil, sql.ErrConnDone)
+
+		_, err := sequenceService.GetSequencesPage(context.Background(), dto.SequencePageRequest{Limit: 2})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
 func TestSequeceService_GetSequence(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
@@ -110,7 +279,7 @@ func TestSequeceService_GetSequence(t *testing.T) {
 
 	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
@@ -123,7 +292,7 @@ func TestSequeceService_GetSequence(t *testing.T) {
 
 	t.Run("return general error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
@@ -133,6 +302,50 @@ func TestSequeceService_GetSequence(t *testing.T) {
 
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
+
+	t.Run("success when the user is bound to the sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1"})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", &sequenceID, nil).Return([]models.Role{models.RoleViewer}, nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name"}, nil)
+
+		res, err := sequenceService.GetSequence(ctx, sequenceID)
+		assert.NoError(t, err)
+		assert.Equal(t, "name", res.Name)
+	})
+
+	t.Run("should skip the role bindings when the token grants the role", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"admin"}})
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name"}, nil)
+
+		_, err := sequenceService.GetSequence(ctx, sequenceID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user has no role", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"unknown"}})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", &sequenceID, nil).Return([]models.Role{}, nil)
+
+		_, err := sequenceService.GetSequence(ctx, sequenceID)
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
 }
 
 func TestSequeceService_UpdateSequence(t *testing.T) {
@@ -140,7 +353,7 @@ func TestSequeceService_UpdateSequence(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
@@ -174,7 +387,7 @@ func TestSequeceService_UpdateSequence(t *testing.T) {
 			Updated:              nil,
 		}).Return(nil)
 
-		res, err := sequenceService.UpdateSequence(context.Background(), sequenceID, req)
+		res, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 0, req)
 		assert.NoError(t, err)
 
 		assert.Equal(t, "name", res.Name)
@@ -183,44 +396,237 @@ func TestSequeceService_UpdateSequence(t *testing.T) {
 		assert.Nil(t, res.LastUpdatedAt)
 	})
 
+	t.Run("success when changing the name", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		name := "new name"
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: sequenceID,
+			Name:       "name",
+		}, nil)
+
+		sequenceRepository.EXPECT().Update(gomock.Any(), &models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: sequenceID,
+			Name:       "new name",
+		}).Return(nil)
+
+		res, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 0, dto.UpdateSequenceRequest{Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, "new name", res.Name)
+	})
+
 	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
 		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
 		sequenceRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, dto.UpdateSequenceRequest{})
+		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 0, dto.UpdateSequenceRequest{})
 
 		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
 	})
 
 	t.Run("return general error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
 		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, sql.ErrConnDone)
 		sequenceRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, dto.UpdateSequenceRequest{})
+		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 0, dto.UpdateSequenceRequest{})
 
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
 
+	t.Run("return services.ErrorVersionMismatch when version is not the current one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Version: 3}, nil)
+		sequenceRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 2, dto.UpdateSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
+	t.Run("return services.ErrorVersionMismatch when sequence changes during the update", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Version: 3}, nil)
+		sequenceRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+
+		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 3, dto.UpdateSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
 	t.Run("return general error in general cases when update", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 
 		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{}, nil)
 		sequenceRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
 
-		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, dto.UpdateSequenceRequest{})
+		_, err := sequenceService.UpdateSequence(context.Background(), sequenceID, 0, dto.UpdateSequenceRequest{})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestSequeceService_ReplaceSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: sequenceID,
+			Name:       "name",
+			Steps: []*dao.Step{
+				{ID: 1, ExternalID: stepID, StepNumber: 1, MailSubject: "subject", MailContent: "content"},
+				{ID: 2, ExternalID: uuid.New(), StepNumber: 2, MailSubject: "subject", MailContent: "content"},
+			},
+			Created: time.Now(),
+		}, nil)
+
+		sequenceRepository.EXPECT().Replace(gomock.Any(), &models.SequenceWithSteps{
+			ExternalID:          sequenceID,
+			Name:                "new name",
+			OpenTrackingEnabled: true,
+			Steps: []*dao.Step{
+				{ExternalID: stepID, StepNumber: 2, MailSubject: "new subject", MailContent: "content"},
+				{StepNumber: 1, MailSubject: "subject", MailContent: "content"},
+			},
+		}).DoAndReturn(func(_ context.Context, model *models.SequenceWithSteps) error {
+			model.ID = 1
+			model.Steps[1].ExternalID = uuid.New()
+			return nil
+		})
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{
+			ID:                  1,
+			ExternalID:          sequenceID,
+			Name:                "new name",
+			OpenTrackingEnabled: true,
+			Version:             2,
+			Steps: []*dao.Step{
+				{ID: 1, ExternalID: stepID, StepNumber: 2, MailSubject: "new subject", MailContent: "content", Version: 2},
+				{ID: 3, ExternalID: uuid.New(), StepNumber: 1, MailSubject: "subject", MailContent: "content", Version: 1},
+			},
+			Created: time.Now(),
+		}, nil)
+
+		res, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 0, dto.ReplaceSequenceRequest{
+			Name:                "new name",
+			OpenTrackingEnabled: true,
+			Steps: []*dto.ReplaceStepRequest{
+				{ExternalID: &stepID, CreateStepRequest: dto.CreateStepRequest{StepNumber: 2, MailSubject: "new subject", MailContent: "content"}},
+				{CreateStepRequest: dto.CreateStepRequest{StepNumber: 1, MailSubject: "subject", MailContent: "content"}},
+			},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "new name", res.Name)
+		assert.Len(t, res.Steps, 2)
+		assert.Equal(t, stepID.String(), res.Steps[0].ExternalID)
+		assert.Equal(t, 2, res.Steps[0].StepNumber)
+		assert.Equal(t, int32(2), res.Steps[0].Version)
+		assert.NotEmpty(t, res.Steps[1].ExternalID)
+		assert.Equal(t, int32(1), res.Steps[1].Version)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 0, dto.ReplaceSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return services.ErrorStepNotInSequence when a step id is not part of the sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 0, dto.ReplaceSequenceRequest{
+			Steps: []*dto.ReplaceStepRequest{{ExternalID: &stepID}},
+		})
+
+		assert.EqualError(t, err, services.ErrorStepNotInSequence.Error())
+	})
+
+	t.Run("return services.ErrorVersionMismatch when version is not the current one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Version: 3}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 2, dto.ReplaceSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence is deleted during the replacement", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+		_, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 0, dto.ReplaceSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases when replace", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Replace(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := sequenceService.ReplaceSequence(context.Background(), sequenceID, 0, dto.ReplaceSequenceRequest{})
 
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
@@ -231,7 +637,7 @@ func TestSequeceService_CreateSequence(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		step := &dto.CreateStepRequest{
 			MailSubject: "subject",
@@ -261,7 +667,7 @@ func TestSequeceService_CreateSequence(t *testing.T) {
 
 	t.Run("return general error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
-		sequenceService := services.NewSequenceService(sequenceRepository)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
 
@@ -269,4 +675,544 @@ func TestSequeceService_CreateSequence(t *testing.T) {
 
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user is only a viewer", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1"})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", nil, nil).Return([]models.Role{models.RoleViewer}, nil)
+
+		_, err := sequenceService.CreateSequence(ctx, dto.CreateSequenceRequest{Name: "name"})
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+}
+
+func TestSequeceService_DeleteSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(nil)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence does not exist", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when delete fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(pgx.ErrNoRows)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when sequence is active", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(repository.ErrSequenceReadOnly)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+
+		assert.ErrorIs(t, err, services.ErrorSequenceNotEditable)
+	})
+
+	t.Run("return services.ErrorVersionMismatch when version is not the current one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(2)).Return(repository.ErrVersionConflict)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 2)
+
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(sql.ErrConnDone)
+
+		err := sequenceService.DeleteSequence(context.Background(), sequenceID, 0)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user is only a viewer of the sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"viewer"}})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", &sequenceID, nil).Return([]models.Role{models.RoleViewer}, nil)
+
+		err := sequenceService.DeleteSequence(ctx, sequenceID, 0)
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+
+	t.Run("should not check roles of api keys", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "api-key:" + uuid.NewString()})
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Delete(gomock.Any(), sequenceID, int32(0)).Return(nil)
+
+		err := sequenceService.DeleteSequence(ctx, sequenceID, 0)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSequeceService_GetDeletedSequences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		deleted := time.Now()
+
+		sequenceRepository.EXPECT().FindAllDeleted(gomock.Any(), 10, 0).Return([]*models.SequenceWithSteps{
+			{
+				ID:         1,
+				ExternalID: uuid.New(),
+				Name:       "name",
+				Steps: []*dao.Step{
+					{
+						ID:          1,
+						ExternalID:  uuid.New(),
+						MailSubject: "subject",
+						MailContent: "content",
+					},
+				},
+				Created: time.Now(),
+				Deleted: &deleted,
+			},
+		}, nil)
+
+		res, err := sequenceService.GetDeletedSequences(context.Background(), 10, 0)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+
+		assert.Equal(t, "name", res[0].Name)
+		assert.NotNil(t, res[0].DeletedAt)
+		assert.Len(t, res[0].Steps, 1)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindAllDeleted(gomock.Any(), 10, 10).Return(nil, sql.ErrConnDone)
+
+		_, err := sequenceService.GetDeletedSequences(context.Background(), 10, 1)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestSequeceService_RestoreSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindDeletedByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID, Name: "name"}, nil)
+		sequenceRepository.EXPECT().Restore(gomock.Any(), sequenceID).Return(nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: sequenceID,
+			Name:       "name",
+			Created:    time.Now(),
+		}, nil)
+
+		res, err := sequenceService.RestoreSequence(context.Background(), sequenceID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, sequenceID.String(), res.ExternalID)
+		assert.Nil(t, res.DeletedAt)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence is not in the trash", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindDeletedByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+		sequenceRepository.EXPECT().Restore(gomock.Any(), gomock.Any()).Times(0)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.RestoreSequence(context.Background(), sequenceID)
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindDeletedByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Restore(gomock.Any(), sequenceID).Return(sql.ErrConnDone)
+
+		_, err := sequenceService.RestoreSequence(context.Background(), sequenceID)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestSequeceService_PurgeDeletedSequences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		retention := 24 * time.Hour
+
+		sequenceRepository.EXPECT().Purge(gomock.Any(), retention).Return([]*models.SequenceWithSteps{
+			{ExternalID: uuid.New(), Name: "first"},
+			{ExternalID: uuid.New(), Name: "second"},
+		}, nil)
+
+		purged, err := sequenceService.PurgeDeletedSequences(context.Background(), retention)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		_, err := sequenceService.PurgeDeletedSequences(context.Background(), time.Hour)
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestSequeceService_TransitionSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sequenceWithStatus := func(id uuid.UUID, status string) *models.SequenceWithSteps {
+		return &models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: id,
+			Name:       "name",
+			Status:     status,
+			Version:    1,
+			Created:    time.Now(),
+			Steps: []*dao.Step{
+				{ID: 1, ExternalID: uuid.New(), StepNumber: 1, MailSubject: "Hi {{contact.firstName}}", MailContent: "content"},
+			},
+		}
+	}
+
+	table := []struct {
+		action string
+		from   string
+		to     string
+	}{
+		{action: "activate", from: models.StatusDraft, to: models.StatusActive},
+		{action: "pause", from: models.StatusActive, to: models.StatusPaused},
+		{action: "resume", from: models.StatusPaused, to: models.StatusActive},
+		{action: "archive", from: models.StatusDraft, to: models.StatusArchived},
+		{action: "archive", from: models.StatusActive, to: models.StatusArchived},
+		{action: "archive", from: models.StatusPaused, to: models.StatusArchived},
+	}
+
+	for _, tc := range table {
+		t.Run("success to "+tc.action+" a "+tc.from+" sequence", func(t *testing.T) {
+			sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+			sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+			sequenceID := uuid.New()
+
+			sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequenceWithStatus(sequenceID, tc.from), nil)
+			sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), tc.to).DoAndReturn(func(_ context.Context, model *models.SequenceWithSteps, to string) error {
+				model.Status = to
+				model.Version++
+				return nil
+			})
+
+			res, err := sequenceService.TransitionSequence(context.Background(), sequenceID, tc.action)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.to, res.Status)
+			assert.Equal(t, int32(2), res.Version)
+		})
+	}
+
+	t.Run("return services.ErrorInvalidTransition when status does not allow the action", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequenceWithStatus(sequenceID, models.StatusArchived), nil)
+		sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "activate")
+
+		assert.ErrorIs(t, err, services.ErrorInvalidTransition)
+		assert.EqualError(t, err, "sequence status does not allow the transition: cannot activate a sequence that is archived")
+	})
+
+	t.Run("return services.ErrorInvalidTransition when status changes concurrently", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequenceWithStatus(sequenceID, models.StatusActive), nil)
+		sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), models.StatusPaused).Return(pgx.ErrNoRows)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "pause")
+
+		assert.ErrorIs(t, err, services.ErrorInvalidTransition)
+	})
+
+	t.Run("return services.ErrorSequenceHasNoSteps when activating a sequence without steps", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequence := sequenceWithStatus(sequenceID, models.StatusDraft)
+		// sequences without steps are read with a single null step
+		sequence.Steps = []*dao.Step{nil}
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequence, nil)
+		sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "activate")
+
+		assert.ErrorIs(t, err, services.ErrorSequenceHasNoSteps)
+	})
+
+	t.Run("return services.ErrorInvalidTemplate when resuming a sequence with a template that does not parse", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequence := sequenceWithStatus(sequenceID, models.StatusPaused)
+		sequence.Steps[0].MailContent = "Hi {{contact.firstName"
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequence, nil)
+		sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "resume")
+
+		assert.ErrorIs(t, err, services.ErrorInvalidTemplate)
+		assert.EqualError(t, err, "step template is invalid: step 1 mail content: tag at position 3 is not closed")
+	})
+
+	t.Run("return services.ErrorUnknownTransition when action does not exist", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), uuid.New(), "publish")
+
+		assert.ErrorIs(t, err, services.ErrorUnknownTransition)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "archive")
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(sequenceWithStatus(sequenceID, models.StatusDraft), nil)
+		sequenceRepository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), models.StatusArchived).Return(sql.ErrConnDone)
+
+		_, err := sequenceService.TransitionSequence(context.Background(), sequenceID, "archive")
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestSequeceService_CloneSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	source := func(id uuid.UUID) *models.SequenceWithSteps {
+		return &models.SequenceWithSteps{
+			ID:                  1,
+			ExternalID:          id,
+			Name:                "name",
+			OpenTrackingEnabled: true,
+			Status:              models.StatusActive,
+			Version:             3,
+			Created:             time.Now(),
+		}
+	}
+
+	// clone stands for the repository, which stores the copy as a new draft
+	clone := func(_ context.Context, model *models.SequenceWithSteps) error {
+		model.ID = 2
+		model.ExternalID = uuid.New()
+		model.Status = models.StatusDraft
+		model.Version = 1
+		model.Created = time.Now()
+		model.Steps = []*dao.Step{{ID: 2, ExternalID: uuid.New(), StepNumber: 1, MailSubject: "subject", MailContent: "content"}}
+		return nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(source(sequenceID), nil)
+		sequenceRepository.EXPECT().Clone(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.SequenceWithSteps) error {
+			assert.Equal(t, "name (copy)", model.Name)
+			assert.True(t, model.OpenTrackingEnabled)
+			assert.False(t, model.ClickTrackingEnabled)
+			assert.Equal(t, sequenceID, *model.SourceID)
+			return clone(ctx, model)
+		})
+
+		res, err := sequenceService.CloneSequence(context.Background(), sequenceID, dto.CloneSequenceRequest{})
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, sequenceID.String(), res.ExternalID)
+		assert.Equal(t, "name (copy)", res.Name)
+		assert.Equal(t, models.StatusDraft, res.Status)
+		assert.Equal(t, sequenceID.String(), *res.SourceSequenceID)
+		assert.Len(t, res.Steps, 1)
+	})
+
+	t.Run("should apply the overrides of the request", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		name := "new name"
+		disabled := false
+		enabled := true
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(source(sequenceID), nil)
+		sequenceRepository.EXPECT().Clone(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model *models.SequenceWithSteps) error {
+			assert.False(t, model.OpenTrackingEnabled)
+			assert.True(t, model.ClickTrackingEnabled)
+			return clone(ctx, model)
+		})
+
+		res, err := sequenceService.CloneSequence(context.Background(), sequenceID, dto.CloneSequenceRequest{
+			Name:                 &name,
+			OpenTrackingEnabled:  &disabled,
+			ClickTrackingEnabled: &enabled,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "new name", res.Name)
+		assert.False(t, res.OpenTrackingEnabled)
+		assert.True(t, res.ClickTrackingEnabled)
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+		sequenceRepository.EXPECT().Clone(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := sequenceService.CloneSequence(context.Background(), sequenceID, dto.CloneSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotFound when sequence is deleted while cloning", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(source(sequenceID), nil)
+		sequenceRepository.EXPECT().Clone(gomock.Any(), gomock.Any()).Return(pgx.ErrNoRows)
+
+		_, err := sequenceService.CloneSequence(context.Background(), sequenceID, dto.CloneSequenceRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		sequenceService := services.NewSequenceService(sequenceRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(source(sequenceID), nil)
+		sequenceRepository.EXPECT().Clone(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := sequenceService.CloneSequence(context.Background(), sequenceID, dto.CloneSequenceRequest{})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
 }
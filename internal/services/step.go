@@ -1,32 +1,134 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
+	"slices"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
 )
 
 type StepService interface {
+	GetSteps(ctx context.Context, sequenceID uuid.UUID, req dto.StepPageRequest) (*dto.StepPageResponse, error)
+	GetStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) (*dto.StepResponse, error)
 	CreateStep(ctx context.Context, sequenceID uuid.UUID, req dto.CreateStepRequest) (*dto.StepResponse, error)
-	UpdateStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.UpdateStepRequest) (*dto.StepResponse, error)
-	DeleteStep(ctx context.Context, stepID uuid.UUID) error
+	UpdateStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, version int32, req dto.UpdateStepRequest) (*dto.StepResponse, error)
+	DeleteStep(ctx context.Context, stepID uuid.UUID, version int32) error
+	ReorderSteps(ctx context.Context, sequenceID uuid.UUID, version int32, req dto.ReorderStepsRequest) ([]*dto.StepResponse, error)
 }
 
+const stepCursorSort = "stepNumber"
+
 type stepService struct {
 	sequenceRepository repository.SequenceRepository
 	stepRepository     repository.StepRepository
+	authorizer         authorizer
+	auditor            auditor
+}
+
+func NewStepService(sequenceRepository repository.SequenceRepository, stepRepository repository.StepRepository, roleBindingRepository repository.RoleBindingRepository, transactor repository.Transactor, auditRepository repository.AuditRepository) StepService {
+	return &stepService{
+		sequenceRepository: sequenceRepository,
+		stepRepository:     stepRepository,
+		authorizer:         authorizer{roleBindingRepository: roleBindingRepository},
+		auditor:            auditor{transactor: transactor, auditRepository: auditRepository},
+	}
 }
 
-func NewStepService(sequenceRepository repository.SequenceRepository, stepRepository repository.StepRepository) StepService {
-	return &stepService{sequenceRepository: sequenceRepository, stepRepository: stepRepository}
+// GetSteps returns a page of the steps of the sequence ordered by step number.
+func (s *stepService) GetSteps(ctx context.Context, sequenceID uuid.UUID, req dto.StepPageRequest) (*dto.StepPageResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	var after int32
+
+	if req.Cursor != "" {
+		cursor, err := utils.DecodeCursor(req.Cursor)
+		if err != nil || cursor.Sort != stepCursorSort {
+			return nil, ErrorInvalidCursor
+		}
+
+		stepNumber, err := strconv.ParseInt(cursor.Value, 10, 32)
+		if err != nil {
+			return nil, ErrorInvalidCursor
+		}
+		after = int32(stepNumber)
+	}
+
+	// fetches one extra step to know if there is another page after this one
+	steps, err := s.stepRepository.FindPage(ctx, sequenceID, after, req.Limit+1)
+	if err != nil {
+		slog.Error("failed to get steps page", err.Error(), err)
+		return nil, err
+	}
+
+	// an empty page is also what a missing sequence looks like
+	if len(steps) == 0 {
+		if _, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, ErrorSequenceNotFound
+			}
+			slog.Error("failed to get sequence", err.Error(), err)
+			return nil, err
+		}
+	}
+
+	hasMore := len(steps) > req.Limit
+	if hasMore {
+		steps = steps[:req.Limit]
+	}
+
+	response := &dto.StepPageResponse{
+		Items: make([]*dto.StepResponse, 0, len(steps)),
+	}
+
+	for _, step := range steps {
+		response.Items = append(response.Items, toStepResponse(step))
+	}
+
+	if hasMore {
+		last := steps[len(steps)-1]
+		next := utils.EncodeCursor(utils.Cursor{Sort: stepCursorSort, Value: strconv.Itoa(int(last.StepNumber)), ID: last.ID})
+		response.NextCursor = &next
+	}
+
+	return response, nil
+}
+
+// GetStep returns the step only when it belongs to the sequence.
+func (s *stepService) GetStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) (*dto.StepResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.stepRepository.FindOne(ctx, sequenceID, stepID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorStepNotFound
+		}
+		slog.Error("failed to get step", err.Error(), err)
+		return nil, err
+	}
+
+	return toStepResponse(step), nil
 }
 
 func (s *stepService) CreateStep(ctx context.Context, sequenceID uuid.UUID, req dto.CreateStepRequest) (*dto.StepResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
 	sequence, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -36,26 +138,33 @@ func (s *stepService) CreateStep(ctx context.Context, sequenceID uuid.UUID, req
 		return nil, err
 	}
 
-	step := &dao.Step{
-		MailSubject: req.MailSubject,
-		MailContent: req.MailContent,
-		StepNumber:  int32(req.StepNumber),
-		SequenceID:  sequence.ID,
-	}
+	step := toStep(&req)
+	step.SequenceID = sequence.ID
 
-	if err := s.stepRepository.Create(ctx, step); err != nil {
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.stepRepository.Create(ctx, step); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionCreate, auditTargetStep, step.ExternalID, nil, toStepResponse(step))
+	})
+	if err != nil {
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
 		slog.Error("failed to create step", err.Error(), err)
 		return nil, err
 	}
 
-	return &dto.StepResponse{
-		ExternalID:  step.ExternalID.String(),
-		MailSubject: step.MailSubject,
-		MailContent: step.MailContent,
-	}, nil
+	return toStepResponse(step), nil
 }
 
-func (s *stepService) UpdateStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.UpdateStepRequest) (*dto.StepResponse, error) {
+// UpdateStep applies the fields present in the request, a version other than
+// 0 must match the current version of the step.
+func (s *stepService) UpdateStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, version int32, req dto.UpdateStepRequest) (*dto.StepResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
 	step, err := s.stepRepository.FindOne(ctx, sequenceID, stepID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -66,31 +175,216 @@ func (s *stepService) UpdateStep(ctx context.Context, sequenceID uuid.UUID, step
 		return nil, err
 	}
 
+	if version != 0 && version != step.Version {
+		return nil, ErrorVersionMismatch
+	}
+
+	before := toStepResponse(step)
+
 	if req.MailSubject != nil {
 		step.MailSubject = *req.MailSubject
 	}
 
-	if req.MailContent != nil {
-		step.MailContent = *req.MailContent
+	if html := req.HTML(); html != nil {
+		step.MailContent = mail.SanitizeHTML(*html)
+	}
+
+	if req.MailText != nil {
+		step.MailText = *req.MailText
 	}
 
 	if req.StepNumber != nil {
 		step.StepNumber = int32(*req.StepNumber)
 	}
 
-	if err := s.stepRepository.Update(context.Background(), step); err != nil {
+	if req.DelayDays != nil {
+		step.DelayDays = int32(*req.DelayDays)
+	}
+
+	if req.DelayHours != nil {
+		step.DelayHours = int32(*req.DelayHours)
+	}
+
+	if req.BusinessDaysOnly != nil {
+		step.BusinessDaysOnly = *req.BusinessDaysOnly
+	}
+
+	if req.SendWindow != nil {
+		step.SendWindowStart, step.SendWindowEnd = nil, nil
+		if !req.SendWindow.IsZero() {
+			start, end, _ := req.SendWindow.Minutes()
+			step.SendWindowStart, step.SendWindowEnd = &start, &end
+		}
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.stepRepository.Update(ctx, step); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionUpdate, auditTargetStep, stepID, before, toStepResponse(step))
+	})
+	if err != nil {
+		if err == repository.ErrStepNumberTaken {
+			return nil, ErrorStepNumberTaken
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		// the step changed after it was read above
+		if err == repository.ErrVersionConflict {
+			return nil, ErrorVersionMismatch
+		}
 		slog.Error("failed to update step", err.Error(), err)
 		return nil, err
 	}
 
+	return toStepResponse(step), nil
+}
+
+// DeleteStep removes the step, a version other than 0 must match the current
+// version of the step. Deleting a step that does not exist changes nothing and
+// is not audited.
+func (s *stepService) DeleteStep(ctx context.Context, stepID uuid.UUID, version int32) error {
+	if err := s.authorizer.authorizeStep(ctx, models.RoleEditor, stepID); err != nil {
+		return err
+	}
+
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.stepRepository.Delete(ctx, stepID, version)
+		if err != nil || deleted == nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionDelete, auditTargetStep, stepID, toStepResponse(deleted), nil)
+	})
+	if err != nil {
+		if err == repository.ErrSequenceReadOnly {
+			return ErrorSequenceNotEditable
+		}
+		if err == repository.ErrVersionConflict {
+			return ErrorVersionMismatch
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReorderSteps renumbers the steps of the sequence following the order of the
+// request and returns them in their new order. A version other than 0 must
+// match the current version of the sequence.
+func (s *stepService) ReorderSteps(ctx context.Context, sequenceID uuid.UUID, version int32, req dto.ReorderStepsRequest) ([]*dto.StepResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
+	var sequence *models.SequenceWithSteps
+
+	err := s.auditor.inTx(ctx, func(ctx context.Context) error {
+		before, err := s.sequenceRepository.FindByExternalId(ctx, sequenceID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sequenceRepository.Reorder(ctx, sequenceID, version, req.StepIDs); err != nil {
+			return err
+		}
+
+		if sequence, err = s.sequenceRepository.FindByExternalId(ctx, sequenceID); err != nil {
+			return err
+		}
+
+		return s.auditor.record(ctx, auditActionReorder, auditTargetSequence, sequenceID, toSequenceResponse(before), toSequenceResponse(sequence))
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorSequenceNotFound
+		}
+		if err == repository.ErrStepOrderMismatch {
+			return nil, ErrorInvalidStepOrder
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		if err == repository.ErrVersionConflict {
+			return nil, ErrorVersionMismatch
+		}
+		slog.Error("failed to reorder steps", err.Error(), err)
+		return nil, err
+	}
+
+	slices.SortFunc(sequence.Steps, func(a, b *dao.Step) int {
+		return cmp.Compare(a.StepNumber, b.StepNumber)
+	})
+
+	response := make([]*dto.StepResponse, 0, len(sequence.Steps))
+	for _, step := range sequence.Steps {
+		response = append(response, toStepResponse(step))
+	}
+
+	return response, nil
+}
+
+// toStep maps a validated step request to its model.
+func toStep(req *dto.CreateStepRequest) *dao.Step {
+	step := &dao.Step{
+		StepNumber:       int32(req.StepNumber),
+		MailSubject:      req.MailSubject,
+		MailContent:      mail.SanitizeHTML(req.HTML()),
+		MailText:         req.MailText,
+		DelayDays:        int32(req.DelayDays),
+		DelayHours:       int32(req.DelayHours),
+		BusinessDaysOnly: req.BusinessDaysOnly,
+	}
+
+	if req.SendWindow != nil {
+		start, end, _ := req.SendWindow.Minutes()
+		step.SendWindowStart, step.SendWindowEnd = &start, &end
+	}
+
+	return step
+}
+
+func toStepResponse(step *dao.Step) *dto.StepResponse {
 	return &dto.StepResponse{
-		ExternalID:  step.ExternalID.String(),
-		StepNumber:  int(step.StepNumber),
-		MailSubject: step.MailSubject,
-		MailContent: step.MailContent,
-	}, nil
+		ExternalID:       step.ExternalID.String(),
+		StepNumber:       int(step.StepNumber),
+		MailSubject:      step.MailSubject,
+		MailContent:      step.MailContent,
+		MailHTML:         step.MailContent,
+		MailText:         plainText(step.MailContent, step.MailText),
+		DelayDays:        int(step.DelayDays),
+		DelayHours:       int(step.DelayHours),
+		BusinessDaysOnly: step.BusinessDaysOnly,
+		SendWindow:       dto.NewSendWindow(step.SendWindowStart, step.SendWindowEnd),
+		Variables:        referencedVariables(step.MailSubject, step.MailContent, step.MailText),
+		Version:          step.Version,
+	}
 }
 
-func (s *stepService) DeleteStep(ctx context.Context, stepID uuid.UUID) error {
-	return s.stepRepository.Delete(context.Background(), stepID)
+// plainText returns the plain text body of a step, derived from the HTML body
+// when the step has none of its own.
+func plainText(html string, text string) string {
+	if text != "" {
+		return text
+	}
+	return mail.PlainText(html)
+}
+
+// referencedVariables lists the template variables used by the subject and the
+// bodies of a step, sorted and without duplicates.
+func referencedVariables(sources ...string) []string {
+	variables := make([]string, 0)
+
+	for _, src := range sources {
+		tmpl, err := templating.Parse(src)
+		if err != nil {
+			// steps stored before templates were validated may not parse
+			continue
+		}
+		variables = append(variables, tmpl.Variables()...)
+	}
+
+	slices.Sort(variables)
+
+	return slices.Compact(variables)
 }
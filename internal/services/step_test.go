@@ -7,22 +7,145 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
 	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
 	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
 	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
 	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
 	"github.com/murilo-bracero/sequence-technical-test/internal/services"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+func TestStepService_GetSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		stepRepository.EXPECT().FindPage(gomock.Any(), sequenceID, int32(0), 3).Return([]*dao.Step{
+			{ID: 1, StepNumber: 1},
+			{ID: 2, StepNumber: 2},
+			{ID: 3, StepNumber: 3},
+		}, nil)
+
+		res, err := stepService.GetSteps(context.Background(), sequenceID, dto.StepPageRequest{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 2)
+		assert.Equal(t, 2, res.Items[1].StepNumber)
+		assert.NotNil(t, res.NextCursor)
+
+		// the next page starts after the last step returned
+		stepRepository.EXPECT().FindPage(gomock.Any(), sequenceID, int32(2), 3).Return([]*dao.Step{{ID: 3, StepNumber: 3}}, nil)
+
+		res, err = stepService.GetSteps(context.Background(), sequenceID, dto.StepPageRequest{Limit: 2, Cursor: *res.NextCursor})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 1)
+		assert.Nil(t, res.NextCursor)
+	})
+
+	t.Run("return ErrorSequenceNotFound when sequence does not exist", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		stepRepository.EXPECT().FindPage(gomock.Any(), sequenceID, int32(0), 51).Return([]*dao.Step{}, nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		res, err := stepService.GetSteps(context.Background(), sequenceID, dto.StepPageRequest{Limit: 50})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return ErrorInvalidCursor when cursor is malformed", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		res, err := stepService.GetSteps(context.Background(), uuid.New(), dto.StepPageRequest{Limit: 50, Cursor: "invalid"})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorInvalidCursor.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindPage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		res, err := stepService.GetSteps(context.Background(), uuid.New(), dto.StepPageRequest{Limit: 50})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestStepService_GetStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{
+			ID:          1,
+			ExternalID:  stepID,
+			MailSubject: "Hi {{contact.firstName}}",
+			MailContent: "{{contact.firstName | default \"there\"}}, meet {{sequence.product}}",
+		}, nil)
+
+		res, err := stepService.GetStep(context.Background(), sequenceID, stepID)
+		assert.NoError(t, err)
+		assert.Equal(t, stepID.String(), res.ExternalID)
+		assert.Equal(t, "Hi {{contact.firstName}}", res.MailSubject)
+		assert.Equal(t, []string{"contact.firstName", "sequence.product"}, res.Variables)
+	})
+
+	t.Run("return ErrorStepNotFound when step is not in the sequence", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+		res, err := stepService.GetStep(context.Background(), uuid.New(), uuid.New())
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorStepNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		res, err := stepService.GetStep(context.Background(), uuid.New(), uuid.New())
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
 func TestStepService_CreateStep(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		req := dto.CreateStepRequest{
@@ -46,7 +169,7 @@ func TestStepService_CreateStep(t *testing.T) {
 	t.Run("return services.ErrorSequenceNotFound when sequence search fails with pgx.ErrNoRows", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		req := dto.CreateStepRequest{
@@ -65,7 +188,7 @@ func TestStepService_CreateStep(t *testing.T) {
 	t.Run("return driver error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		req := dto.CreateStepRequest{
@@ -88,7 +211,7 @@ func TestStepService_UpdateStep(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		stepID := uuid.New()
@@ -108,16 +231,33 @@ func TestStepService_UpdateStep(t *testing.T) {
 			MailContent: *req.MailContent,
 		}).Return(nil)
 
-		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, req)
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, req)
 		assert.NoError(t, err)
 		assert.Equal(t, "subject", res.MailSubject)
 		assert.Equal(t, "content", res.MailContent)
 	})
 
+	t.Run("return services.ErrorVersionMismatch when version is not the current one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, Version: 3}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 2, dto.UpdateStepRequest{})
+
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
 	t.Run("return ErrorStepNotFound when step search fails with pgx.ErrNoRows", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		stepID := uuid.New()
@@ -133,7 +273,7 @@ func TestStepService_UpdateStep(t *testing.T) {
 		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(nil, pgx.ErrNoRows)
 		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, req)
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, req)
 		assert.Nil(t, res)
 		assert.EqualError(t, err, services.ErrorStepNotFound.Error())
 	})
@@ -141,7 +281,7 @@ func TestStepService_UpdateStep(t *testing.T) {
 	t.Run("return driver error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		stepID := uuid.New()
@@ -157,7 +297,7 @@ func TestStepService_UpdateStep(t *testing.T) {
 		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(nil, sql.ErrConnDone)
 		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, req)
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, req)
 		assert.Nil(t, res)
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
@@ -165,7 +305,7 @@ func TestStepService_UpdateStep(t *testing.T) {
 	t.Run("return driver error in general cases with second call", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		sequenceID := uuid.New()
 		stepID := uuid.New()
@@ -181,7 +321,250 @@ func TestStepService_UpdateStep(t *testing.T) {
 		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1}, nil)
 		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
 
-		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, req)
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, req)
+		assert.Nil(t, res)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when sequence is active", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		mailSubject := "subject"
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), gomock.Any()).Return(repository.ErrSequenceReadOnly)
+
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, dto.UpdateStepRequest{MailSubject: &mailSubject})
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, services.ErrorSequenceNotEditable)
+	})
+}
+
+func TestStepService_UpdateStep_StepNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("return ErrorStepNumberTaken when another step has the step number", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		stepNumber := 2
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, StepNumber: 1}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), &dao.Step{ID: 1, StepNumber: 2}).Return(repository.ErrStepNumberTaken)
+
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, dto.UpdateStepRequest{StepNumber: &stepNumber})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorStepNumberTaken.Error())
+	})
+}
+
+func TestStepService_Timing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success creating a step with delays and send window", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		req := dto.CreateStepRequest{
+			MailSubject:      "subject",
+			MailContent:      "content",
+			DelayDays:        2,
+			DelayHours:       6,
+			BusinessDaysOnly: true,
+			SendWindow:       &dto.SendWindow{Start: "09:00", End: "17:30"},
+		}
+
+		start, end := int32(540), int32(1050)
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1}, nil)
+		stepRepository.EXPECT().Create(gomock.Any(), &dao.Step{
+			MailSubject:      req.MailSubject,
+			MailContent:      req.MailContent,
+			SequenceID:       1,
+			DelayDays:        2,
+			DelayHours:       6,
+			BusinessDaysOnly: true,
+			SendWindowStart:  &start,
+			SendWindowEnd:    &end,
+		}).Return(nil)
+
+		res, err := stepService.CreateStep(context.Background(), sequenceID, req)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, res.DelayDays)
+		assert.Equal(t, 6, res.DelayHours)
+		assert.True(t, res.BusinessDaysOnly)
+		assert.Equal(t, &dto.SendWindow{Start: "09:00", End: "17:30"}, res.SendWindow)
+	})
+
+	t.Run("success removing the send window with an empty window", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		start, end := int32(540), int32(1050)
+		delayDays := 3
+
+		req := dto.UpdateStepRequest{DelayDays: &delayDays, SendWindow: &dto.SendWindow{}}
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, SendWindowStart: &start, SendWindowEnd: &end}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), &dao.Step{ID: 1, DelayDays: 3}).Return(nil)
+
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, req)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, res.DelayDays)
+		assert.Nil(t, res.SendWindow)
+	})
+}
+
+func TestStepService_Bodies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success creating a step with sanitised html and derived text", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		req := dto.CreateStepRequest{
+			MailSubject: "subject",
+			MailHTML:    `<p onclick="track()">Hi {{contact.firstName | default "there"}}</p><script>alert(1)</script>`,
+		}
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1}, nil)
+		stepRepository.EXPECT().Create(gomock.Any(), &dao.Step{
+			MailSubject: req.MailSubject,
+			MailContent: `<p>Hi {{contact.firstName | default "there"}}</p>`,
+			SequenceID:  1,
+		}).Return(nil)
+
+		res, err := stepService.CreateStep(context.Background(), sequenceID, req)
+		assert.NoError(t, err)
+		assert.Equal(t, `<p>Hi {{contact.firstName | default "there"}}</p>`, res.MailHTML)
+		assert.Equal(t, res.MailHTML, res.MailContent)
+		assert.Equal(t, `Hi {{contact.firstName | default "there"}}`, res.MailText)
+	})
+
+	t.Run("success updating the text and keeping the html", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		stepID := uuid.New()
+
+		text := "Hi {{contact.firstName}}"
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, MailContent: "<p>Hi</p>"}, nil)
+		stepRepository.EXPECT().Update(gomock.Any(), &dao.Step{ID: 1, MailContent: "<p>Hi</p>", MailText: text}).Return(nil)
+
+		res, err := stepService.UpdateStep(context.Background(), sequenceID, stepID, 0, dto.UpdateStepRequest{MailText: &text})
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>Hi</p>", res.MailHTML)
+		assert.Equal(t, text, res.MailText)
+		assert.Equal(t, []string{"contact.firstName"}, res.Variables)
+	})
+}
+
+func TestStepService_ReorderSteps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+		first, second := uuid.New(), uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Reorder(gomock.Any(), sequenceID, int32(0), []uuid.UUID{second, first}).Return(nil)
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{
+			ID:         1,
+			ExternalID: sequenceID,
+			Steps: []*dao.Step{
+				{ExternalID: first, StepNumber: 2},
+				{ExternalID: second, StepNumber: 1},
+			},
+		}, nil)
+
+		res, err := stepService.ReorderSteps(context.Background(), sequenceID, 0, dto.ReorderStepsRequest{StepIDs: []uuid.UUID{second, first}})
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, second.String(), res[0].ExternalID)
+		assert.Equal(t, 1, res[0].StepNumber)
+		assert.Equal(t, first.String(), res[1].ExternalID)
+		assert.Equal(t, 2, res[1].StepNumber)
+	})
+
+	t.Run("return ErrorSequenceNotFound when sequence does not exist", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Reorder(gomock.Any(), sequenceID, int32(0), gomock.Any()).Return(pgx.ErrNoRows)
+
+		res, err := stepService.ReorderSteps(context.Background(), sequenceID, 0, dto.ReorderStepsRequest{StepIDs: []uuid.UUID{uuid.New()}})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return ErrorSequenceNotFound when sequence is not found before the reorder", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(nil, pgx.ErrNoRows)
+
+		res, err := stepService.ReorderSteps(context.Background(), sequenceID, 0, dto.ReorderStepsRequest{StepIDs: []uuid.UUID{uuid.New()}})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorSequenceNotFound.Error())
+	})
+
+	t.Run("return ErrorInvalidStepOrder when ids do not match the sequence steps", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Reorder(gomock.Any(), sequenceID, int32(0), gomock.Any()).Return(repository.ErrStepOrderMismatch)
+
+		res, err := stepService.ReorderSteps(context.Background(), sequenceID, 0, dto.ReorderStepsRequest{StepIDs: []uuid.UUID{uuid.New()}})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, services.ErrorInvalidStepOrder.Error())
+	})
+
+	t.Run("return driver error in general cases", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID := uuid.New()
+
+		sequenceRepository.EXPECT().FindByExternalId(gomock.Any(), sequenceID).Return(&models.SequenceWithSteps{ID: 1, ExternalID: sequenceID}, nil)
+		sequenceRepository.EXPECT().Reorder(gomock.Any(), sequenceID, int32(0), gomock.Any()).Return(sql.ErrConnDone)
+
+		res, err := stepService.ReorderSteps(context.Background(), sequenceID, 0, dto.ReorderStepsRequest{StepIDs: []uuid.UUID{uuid.New()}})
 		assert.Nil(t, res)
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
@@ -193,27 +576,57 @@ func TestStepService_Delete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		stepID := uuid.New()
 
-		stepRepository.EXPECT().Delete(gomock.Any(), stepID).Return(nil)
+		stepRepository.EXPECT().Delete(gomock.Any(), stepID, int32(0)).Return(&dao.Step{ID: 1, ExternalID: stepID}, nil)
 
-		err := stepService.DeleteStep(context.Background(), stepID)
+		err := stepService.DeleteStep(context.Background(), stepID, 0)
 		assert.NoError(t, err)
 	})
 
+	t.Run("return services.ErrorVersionMismatch when version is not the current one", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepID := uuid.New()
+
+		stepRepository.EXPECT().Delete(gomock.Any(), stepID, int32(2)).Return(nil, repository.ErrVersionConflict)
+
+		err := stepService.DeleteStep(context.Background(), stepID, 2)
+
+		assert.EqualError(t, err, services.ErrorVersionMismatch.Error())
+	})
+
 	t.Run("return driver error in general cases", func(t *testing.T) {
 		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
 		stepRepository := mocks.NewMockStepRepository(ctrl)
-		stepService := services.NewStepService(sequenceRepository, stepRepository)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
 
 		stepID := uuid.New()
 
-		stepRepository.EXPECT().Delete(gomock.Any(), stepID).Return(sql.ErrConnDone)
+		stepRepository.EXPECT().Delete(gomock.Any(), stepID, int32(0)).Return(nil, sql.ErrConnDone)
 
-		err := stepService.DeleteStep(context.Background(), stepID)
+		err := stepService.DeleteStep(context.Background(), stepID, 0)
 
 		assert.EqualError(t, err, sql.ErrConnDone.Error())
 	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user cannot edit the sequence of the step", func(t *testing.T) {
+		sequenceRepository := mocks.NewMockSequenceRepository(ctrl)
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		stepService := services.NewStepService(sequenceRepository, stepRepository, roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepID := uuid.New()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1"})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", nil, &stepID).Return([]models.Role{models.RoleViewer}, nil)
+
+		err := stepService.DeleteStep(ctx, stepID, 0)
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
 }
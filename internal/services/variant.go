@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/mail"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+type VariantService interface {
+	GetVariants(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) ([]*dto.VariantResponse, error)
+	GetVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID) (*dto.VariantResponse, error)
+	CreateVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.CreateVariantRequest) (*dto.VariantResponse, error)
+	UpdateVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID, req dto.UpdateVariantRequest) (*dto.VariantResponse, error)
+	DeleteVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID) error
+	AssignVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.AssignVariantRequest) (*dto.VariantAssignmentResponse, error)
+}
+
+type variantService struct {
+	stepRepository    repository.StepRepository
+	variantRepository repository.VariantRepository
+	authorizer        authorizer
+	auditor           auditor
+}
+
+func NewVariantService(stepRepository repository.StepRepository, variantRepository repository.VariantRepository, roleBindingRepository repository.RoleBindingRepository, transactor repository.Transactor, auditRepository repository.AuditRepository) VariantService {
+	return &variantService{
+		stepRepository:    stepRepository,
+		variantRepository: variantRepository,
+		authorizer:        authorizer{roleBindingRepository: roleBindingRepository},
+		auditor:           auditor{transactor: transactor, auditRepository: auditRepository},
+	}
+}
+
+func (s *variantService) GetVariants(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) ([]*dto.VariantResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.variantRepository.FindAll(ctx, step.ID)
+	if err != nil {
+		slog.Error("failed to get variants", err.Error(), err)
+		return nil, err
+	}
+
+	response := make([]*dto.VariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		response = append(response, toVariantResponse(variant))
+	}
+
+	return response, nil
+}
+
+func (s *variantService) GetVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID) (*dto.VariantResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleViewer, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := s.findVariant(ctx, step.ID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toVariantResponse(variant), nil
+}
+
+func (s *variantService) CreateVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.CreateVariantRequest) (*dto.VariantResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := &models.StepVariant{
+		StepID:      step.ID,
+		MailSubject: req.MailSubject,
+		MailContent: mail.SanitizeHTML(req.MailContent),
+		Weight:      int32(req.Weight),
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.variantRepository.Create(ctx, step.SequenceID, variant); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionCreate, auditTargetVariant, variant.ExternalID, nil, toVariantResponse(variant))
+	})
+	if err != nil {
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		slog.Error("failed to create variant", err.Error(), err)
+		return nil, err
+	}
+
+	return toVariantResponse(variant), nil
+}
+
+func (s *variantService) UpdateVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID, req dto.UpdateVariantRequest) (*dto.VariantResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := s.findVariant(ctx, step.ID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := toVariantResponse(variant)
+
+	if req.MailSubject != nil {
+		variant.MailSubject = *req.MailSubject
+	}
+
+	if req.MailContent != nil {
+		variant.MailContent = mail.SanitizeHTML(*req.MailContent)
+	}
+
+	if req.Weight != nil {
+		variant.Weight = int32(*req.Weight)
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		if err := s.variantRepository.Update(ctx, step.SequenceID, variant); err != nil {
+			return err
+		}
+		return s.auditor.record(ctx, auditActionUpdate, auditTargetVariant, variantID, before, toVariantResponse(variant))
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorVariantNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return nil, ErrorSequenceNotEditable
+		}
+		slog.Error("failed to update variant", err.Error(), err)
+		return nil, err
+	}
+
+	return toVariantResponse(variant), nil
+}
+
+func (s *variantService) DeleteVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, variantID uuid.UUID) error {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return err
+	}
+
+	err = s.auditor.inTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.variantRepository.FindOne(ctx, step.ID, variantID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.variantRepository.Delete(ctx, step.SequenceID, step.ID, variantID); err != nil {
+			return err
+		}
+
+		return s.auditor.record(ctx, auditActionDelete, auditTargetVariant, variantID, toVariantResponse(deleted), nil)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrorVariantNotFound
+		}
+		if err == repository.ErrSequenceReadOnly {
+			return ErrorSequenceNotEditable
+		}
+		slog.Error("failed to delete variant", err.Error(), err)
+		return err
+	}
+
+	return nil
+}
+
+// AssignVariant returns the variant of the step the enrollment gets. The first
+// assignment is recorded and kept, so changing the variants or their weights
+// only affects the enrollments that were not assigned yet.
+func (s *variantService) AssignVariant(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID, req dto.AssignVariantRequest) (*dto.VariantAssignmentResponse, error) {
+	if err := s.authorizer.authorizeSequence(ctx, models.RoleEditor, sequenceID); err != nil {
+		return nil, err
+	}
+
+	step, err := s.findStep(ctx, sequenceID, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := s.variantRepository.FindAssignment(ctx, step.ID, req.EnrollmentID)
+	if err == nil {
+		return toVariantAssignmentResponse(req.EnrollmentID, variant), nil
+	}
+
+	if err != pgx.ErrNoRows {
+		slog.Error("failed to get variant assignment", err.Error(), err)
+		return nil, err
+	}
+
+	variants, err := s.variantRepository.FindAll(ctx, step.ID)
+	if err != nil {
+		slog.Error("failed to get variants", err.Error(), err)
+		return nil, err
+	}
+
+	if len(variants) == 0 {
+		return nil, ErrorStepHasNoVariants
+	}
+
+	picked := pickVariant(req.EnrollmentID, step.ExternalID, variants)
+
+	if err := s.variantRepository.Assign(ctx, step.ID, req.EnrollmentID, picked.ID); err != nil {
+		slog.Error("failed to assign variant", err.Error(), err)
+		return nil, err
+	}
+
+	// a concurrent request may have recorded another variant first
+	variant, err = s.variantRepository.FindAssignment(ctx, step.ID, req.EnrollmentID)
+	if err != nil {
+		slog.Error("failed to get variant assignment", err.Error(), err)
+		return nil, err
+	}
+
+	return toVariantAssignmentResponse(req.EnrollmentID, variant), nil
+}
+
+func (s *variantService) findStep(ctx context.Context, sequenceID uuid.UUID, stepID uuid.UUID) (*dao.Step, error) {
+	step, err := s.stepRepository.FindOne(ctx, sequenceID, stepID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorStepNotFound
+		}
+		slog.Error("failed to get step", err.Error(), err)
+		return nil, err
+	}
+
+	return step, nil
+}
+
+func (s *variantService) findVariant(ctx context.Context, stepID int32, variantID uuid.UUID) (*models.StepVariant, error) {
+	variant, err := s.variantRepository.FindOne(ctx, stepID, variantID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorVariantNotFound
+		}
+		slog.Error("failed to get variant", err.Error(), err)
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// pickVariant hashes the enrollment and the step to a point of the summed
+// weights of the variants, in creation order. The same enrollment always gets
+// the same variant while the variants stay the same, and the step is part of
+// the hash so an enrollment is not bound to the first variant of every step.
+func pickVariant(enrollmentID uuid.UUID, stepID uuid.UUID, variants []*models.StepVariant) *models.StepVariant {
+	var total uint64
+	for _, variant := range variants {
+		total += uint64(variant.Weight)
+	}
+
+	h := fnv.New64a()
+	h.Write(enrollmentID[:])
+	h.Write(stepID[:])
+
+	point := h.Sum64() % total
+	for _, variant := range variants {
+		if point < uint64(variant.Weight) {
+			return variant
+		}
+		point -= uint64(variant.Weight)
+	}
+
+	return variants[len(variants)-1]
+}
+
+func toVariantResponse(variant *models.StepVariant) *dto.VariantResponse {
+	return &dto.VariantResponse{
+		ExternalID:  variant.ExternalID.String(),
+		MailSubject: variant.MailSubject,
+		MailContent: variant.MailContent,
+		Weight:      int(variant.Weight),
+		Assignments: variant.Assignments,
+		Variables:   referencedVariables(variant.MailSubject, variant.MailContent),
+		CreatedAt:   variant.Created.Format(time.RFC3339),
+	}
+}
+
+func toVariantAssignmentResponse(enrollmentID uuid.UUID, variant *models.StepVariant) *dto.VariantAssignmentResponse {
+	return &dto.VariantAssignmentResponse{
+		EnrollmentID: enrollmentID.String(),
+		VariantID:    variant.ExternalID.String(),
+		MailSubject:  variant.MailSubject,
+		MailContent:  variant.MailContent,
+	}
+}
@@ -0,0 +1,357 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/auth"
+	dao "github.com/murilo-bracero/sequence-technical-test/internal/db/gen"
+	"github.com/murilo-bracero/sequence-technical-test/internal/dto"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVariantService_GetVariants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID, stepID := uuid.New(), uuid.New()
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID}, nil)
+		variantRepository.EXPECT().FindAll(gomock.Any(), int32(1)).Return([]*models.StepVariant{
+			{ID: 1, ExternalID: uuid.New(), MailSubject: "Hi {{contact.firstName}}", MailContent: "content", Weight: 1, Assignments: 4},
+			{ID: 2, ExternalID: uuid.New(), MailSubject: "Hello", MailContent: "content", Weight: 3, Assignments: 12},
+		}, nil)
+
+		res, err := variantService.GetVariants(context.Background(), sequenceID, stepID)
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, int64(4), res[0].Assignments)
+		assert.Equal(t, []string{"contact.firstName"}, res[0].Variables)
+		assert.Equal(t, 3, res[1].Weight)
+	})
+
+	t.Run("return services.ErrorStepNotFound when step search fails with pgx.ErrNoRows", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+		variantRepository.EXPECT().FindAll(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := variantService.GetVariants(context.Background(), uuid.New(), uuid.New())
+
+		assert.EqualError(t, err, services.ErrorStepNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindAll(gomock.Any(), int32(1)).Return(nil, sql.ErrConnDone)
+
+		_, err := variantService.GetVariants(context.Background(), uuid.New(), uuid.New())
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestVariantService_CreateVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID, stepID, variantID := uuid.New(), uuid.New(), uuid.New()
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID, SequenceID: 7}, nil)
+		variantRepository.EXPECT().Create(gomock.Any(), int32(7), &models.StepVariant{
+			StepID:      1,
+			MailSubject: "subject B",
+			MailContent: "<p>content</p>",
+			Weight:      2,
+		}).DoAndReturn(func(_ context.Context, _ int32, model *models.StepVariant) error {
+			model.ExternalID = variantID
+			return nil
+		})
+
+		res, err := variantService.CreateVariant(context.Background(), sequenceID, stepID, dto.CreateVariantRequest{
+			MailSubject: "subject B",
+			MailContent: "<p>content</p><script>alert(1)</script>",
+			Weight:      2,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, variantID.String(), res.ExternalID)
+		assert.Equal(t, "<p>content</p>", res.MailContent)
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when sequence is read-only", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrSequenceReadOnly)
+
+		_, err := variantService.CreateVariant(context.Background(), uuid.New(), uuid.New(), dto.CreateVariantRequest{MailSubject: "subject", MailContent: "content", Weight: 1})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotEditable.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+		_, err := variantService.CreateVariant(context.Background(), uuid.New(), uuid.New(), dto.CreateVariantRequest{MailSubject: "subject", MailContent: "content", Weight: 1})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
+
+func TestVariantService_UpdateVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		sequenceID, stepID, variantID := uuid.New(), uuid.New(), uuid.New()
+		weight := 5
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, SequenceID: 7}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), variantID).Return(&models.StepVariant{ID: 3, ExternalID: variantID, StepID: 1, MailSubject: "subject", MailContent: "content", Weight: 1}, nil)
+		variantRepository.EXPECT().Update(gomock.Any(), int32(7), &models.StepVariant{ID: 3, ExternalID: variantID, StepID: 1, MailSubject: "subject", MailContent: "content", Weight: 5}).Return(nil)
+
+		res, err := variantService.UpdateVariant(context.Background(), sequenceID, stepID, variantID, dto.UpdateVariantRequest{Weight: &weight})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, res.Weight)
+	})
+
+	t.Run("return services.ErrorVariantNotFound when variant search fails with pgx.ErrNoRows", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), gomock.Any()).Return(nil, pgx.ErrNoRows)
+		variantRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := variantService.UpdateVariant(context.Background(), uuid.New(), uuid.New(), uuid.New(), dto.UpdateVariantRequest{})
+
+		assert.EqualError(t, err, services.ErrorVariantNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when sequence is read-only", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), gomock.Any()).Return(&models.StepVariant{ID: 3, StepID: 1, Weight: 1}, nil)
+		variantRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrSequenceReadOnly)
+
+		_, err := variantService.UpdateVariant(context.Background(), uuid.New(), uuid.New(), uuid.New(), dto.UpdateVariantRequest{})
+
+		assert.EqualError(t, err, services.ErrorSequenceNotEditable.Error())
+	})
+}
+
+func TestVariantService_DeleteVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		variantID := uuid.New()
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1, SequenceID: 7}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), variantID).Return(&models.StepVariant{ID: 1, ExternalID: variantID, StepID: 1}, nil)
+		variantRepository.EXPECT().Delete(gomock.Any(), int32(7), int32(1), variantID).Return(nil)
+
+		assert.NoError(t, variantService.DeleteVariant(context.Background(), uuid.New(), uuid.New(), variantID))
+	})
+
+	t.Run("return services.ErrorVariantNotFound when the variant is not found", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), gomock.Any()).Return(nil, pgx.ErrNoRows)
+		variantRepository.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := variantService.DeleteVariant(context.Background(), uuid.New(), uuid.New(), uuid.New())
+
+		assert.EqualError(t, err, services.ErrorVariantNotFound.Error())
+	})
+
+	t.Run("return services.ErrorVariantNotFound when delete fails with pgx.ErrNoRows", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), gomock.Any()).Return(&models.StepVariant{ID: 1, StepID: 1}, nil)
+		variantRepository.EXPECT().Delete(gomock.Any(), gomock.Any(), int32(1), gomock.Any()).Return(pgx.ErrNoRows)
+
+		err := variantService.DeleteVariant(context.Background(), uuid.New(), uuid.New(), uuid.New())
+
+		assert.EqualError(t, err, services.ErrorVariantNotFound.Error())
+	})
+
+	t.Run("return services.ErrorSequenceNotEditable when sequence is read-only", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Return(&dao.Step{ID: 1}, nil)
+		variantRepository.EXPECT().FindOne(gomock.Any(), int32(1), gomock.Any()).Return(&models.StepVariant{ID: 1, StepID: 1}, nil)
+		variantRepository.EXPECT().Delete(gomock.Any(), gomock.Any(), int32(1), gomock.Any()).Return(repository.ErrSequenceReadOnly)
+
+		err := variantService.DeleteVariant(context.Background(), uuid.New(), uuid.New(), uuid.New())
+
+		assert.EqualError(t, err, services.ErrorSequenceNotEditable.Error())
+	})
+}
+
+func TestVariantService_AssignVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	sequenceID, stepID := uuid.New(), uuid.New()
+
+	variants := []*models.StepVariant{
+		{ID: 1, ExternalID: uuid.New(), StepID: 1, MailSubject: "subject A", Weight: 1},
+		{ID: 2, ExternalID: uuid.New(), StepID: 1, MailSubject: "subject B", Weight: 3},
+	}
+
+	// newVariantService records the assignments in memory, like the table does
+	newVariantService := func() services.VariantService {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+
+		assignments := make(map[uuid.UUID]int32)
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID}, nil).AnyTimes()
+		variantRepository.EXPECT().FindAll(gomock.Any(), int32(1)).Return(variants, nil).AnyTimes()
+		variantRepository.EXPECT().FindAssignment(gomock.Any(), int32(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int32, enrollmentID uuid.UUID) (*models.StepVariant, error) {
+			variantID, ok := assignments[enrollmentID]
+			if !ok {
+				return nil, pgx.ErrNoRows
+			}
+			return variants[variantID-1], nil
+		}).AnyTimes()
+		variantRepository.EXPECT().Assign(gomock.Any(), int32(1), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ int32, enrollmentID uuid.UUID, variantID int32) error {
+			if _, ok := assignments[enrollmentID]; !ok {
+				assignments[enrollmentID] = variantID
+			}
+			return nil
+		}).AnyTimes()
+
+		return services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		variantService := newVariantService()
+
+		enrollmentID := uuid.New()
+
+		first, err := variantService.AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: enrollmentID})
+		assert.NoError(t, err)
+		assert.Equal(t, enrollmentID.String(), first.EnrollmentID)
+
+		second, err := variantService.AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: enrollmentID})
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("success assigning the same variant without the recorded assignment", func(t *testing.T) {
+		enrollmentID := uuid.New()
+
+		first, err := newVariantService().AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: enrollmentID})
+		assert.NoError(t, err)
+
+		second, err := newVariantService().AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: enrollmentID})
+		assert.NoError(t, err)
+
+		assert.Equal(t, first.VariantID, second.VariantID)
+	})
+
+	t.Run("success splitting the enrollments by weight", func(t *testing.T) {
+		variantService := newVariantService()
+
+		counts := make(map[string]int)
+		for range 4000 {
+			res, err := variantService.AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: uuid.New()})
+			assert.NoError(t, err)
+			counts[res.VariantID]++
+		}
+
+		assert.InDelta(t, 1000, counts[variants[0].ExternalID.String()], 150)
+		assert.InDelta(t, 3000, counts[variants[1].ExternalID.String()], 150)
+	})
+
+	t.Run("return services.ErrorStepHasNoVariants when step has no variants", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID}, nil)
+		variantRepository.EXPECT().FindAssignment(gomock.Any(), int32(1), gomock.Any()).Return(nil, pgx.ErrNoRows)
+		variantRepository.EXPECT().FindAll(gomock.Any(), int32(1)).Return([]*models.StepVariant{}, nil)
+		variantRepository.EXPECT().Assign(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := variantService.AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: uuid.New()})
+
+		assert.EqualError(t, err, services.ErrorStepHasNoVariants.Error())
+	})
+
+	t.Run("return services.ErrorRoleNotGranted when the user is a viewer", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		roleBindingRepository := mocks.NewMockRoleBindingRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, mocks.NewMockVariantRepository(ctrl), roleBindingRepository, newTransactor(ctrl), newAuditRepository(ctrl))
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: "user-1", Roles: []string{"viewer"}})
+
+		roleBindingRepository.EXPECT().FindRoles(gomock.Any(), "user-1", &sequenceID, nil).Return([]models.Role{models.RoleViewer}, nil)
+		stepRepository.EXPECT().FindOne(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := variantService.AssignVariant(ctx, sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: uuid.New()})
+
+		assert.EqualError(t, err, services.ErrorRoleNotGranted.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		stepRepository := mocks.NewMockStepRepository(ctrl)
+		variantRepository := mocks.NewMockVariantRepository(ctrl)
+		variantService := services.NewVariantService(stepRepository, variantRepository, mocks.NewMockRoleBindingRepository(ctrl), newTransactor(ctrl), newAuditRepository(ctrl))
+
+		stepRepository.EXPECT().FindOne(gomock.Any(), sequenceID, stepID).Return(&dao.Step{ID: 1, ExternalID: stepID}, nil)
+		variantRepository.EXPECT().FindAssignment(gomock.Any(), int32(1), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		_, err := variantService.AssignVariant(context.Background(), sequenceID, stepID, dto.AssignVariantRequest{EnrollmentID: uuid.New()})
+
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
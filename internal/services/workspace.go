@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository"
+)
+
+type WorkspaceService interface {
+	GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	GetWorkspaces(ctx context.Context) ([]*models.Workspace, error)
+}
+
+type workspaceService struct {
+	workspaceRepository repository.WorkspaceRepository
+}
+
+func NewWorkspaceService(workspaceRepository repository.WorkspaceRepository) WorkspaceService {
+	return &workspaceService{workspaceRepository: workspaceRepository}
+}
+
+func (s *workspaceService) GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	workspace, err := s.workspaceRepository.FindByExternalId(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrorWorkspaceNotFound
+		}
+		slog.Error("failed to get workspace", err.Error(), err)
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// GetWorkspaces returns every workspace, for the jobs that run across all of
+// them.
+func (s *workspaceService) GetWorkspaces(ctx context.Context) ([]*models.Workspace, error) {
+	workspaces, err := s.workspaceRepository.FindAll(ctx)
+	if err != nil {
+		slog.Error("failed to get workspaces", err.Error(), err)
+		return nil, err
+	}
+
+	return workspaces, nil
+}
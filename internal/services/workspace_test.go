@@ -0,0 +1,57 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/murilo-bracero/sequence-technical-test/internal/models"
+	"github.com/murilo-bracero/sequence-technical-test/internal/repository/mocks"
+	"github.com/murilo-bracero/sequence-technical-test/internal/services"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWorkspaceService_GetWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("success", func(t *testing.T) {
+		workspaceRepository := mocks.NewMockWorkspaceRepository(ctrl)
+		workspaceService := services.NewWorkspaceService(workspaceRepository)
+
+		id := uuid.New()
+
+		workspaceRepository.EXPECT().FindByExternalId(gomock.Any(), id).Return(&models.Workspace{ID: 1, ExternalID: id, Name: "workspace"}, nil)
+
+		workspace, err := workspaceService.GetWorkspace(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), workspace.ID)
+		assert.Equal(t, "workspace", workspace.Name)
+	})
+
+	t.Run("return services.ErrorWorkspaceNotFound when workspace does not exist", func(t *testing.T) {
+		workspaceRepository := mocks.NewMockWorkspaceRepository(ctrl)
+		workspaceService := services.NewWorkspaceService(workspaceRepository)
+
+		workspaceRepository.EXPECT().FindByExternalId(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+		workspace, err := workspaceService.GetWorkspace(context.Background(), uuid.New())
+
+		assert.Nil(t, workspace)
+		assert.EqualError(t, err, services.ErrorWorkspaceNotFound.Error())
+	})
+
+	t.Run("return general error in general cases", func(t *testing.T) {
+		workspaceRepository := mocks.NewMockWorkspaceRepository(ctrl)
+		workspaceService := services.NewWorkspaceService(workspaceRepository)
+
+		workspaceRepository.EXPECT().FindByExternalId(gomock.Any(), gomock.Any()).Return(nil, sql.ErrConnDone)
+
+		workspace, err := workspaceService.GetWorkspace(context.Background(), uuid.New())
+
+		assert.Nil(t, workspace)
+		assert.EqualError(t, err, sql.ErrConnDone.Error())
+	})
+}
@@ -0,0 +1,166 @@
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrMissingVariable = errors.New("missing template variable")
+
+// Data holds the values of the variables by namespace and name, e.g.
+// data["contact"]["firstName"].
+type Data map[string]map[string]string
+
+func (d Data) lookup(variable string) (string, bool) {
+	namespace, name, _ := strings.Cut(variable, ".")
+
+	value, ok := d[namespace][name]
+	return value, ok
+}
+
+// Render writes the template with the given data. Missing variables render as
+// empty strings, unless strict is set, in which case rendering fails with
+// ErrMissingVariable for any missing variable that has no default filter.
+func (t *Template) Render(data Data, strict bool) (string, error) {
+	return t.render(data, strict, nil)
+}
+
+// RenderHTML is Render for HTML bodies, the values of the variables are
+// escaped so they are written as text and cannot add markup nor leave the
+// attribute they are in. Unsafe urls still have to be removed by sanitising
+// the result.
+func (t *Template) RenderHTML(data Data, strict bool) (string, error) {
+	return t.render(data, strict, html.EscapeString)
+}
+
+func (t *Template) render(data Data, strict bool, escape func(string) string) (string, error) {
+	var sb strings.Builder
+
+	err := render(&sb, t.nodes, data, escape, func(name string) error {
+		if strict {
+			return fmt.Errorf("%w: %s", ErrMissingVariable, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// Unresolved returns the variables that strict rendering would fail on, in
+// the order they appear in the output and without duplicates.
+func (t *Template) Unresolved(data Data) []string {
+	var sb strings.Builder
+
+	unresolved := make([]string, 0)
+
+	render(&sb, t.nodes, data, nil, func(name string) error {
+		if !slices.Contains(unresolved, name) {
+			unresolved = append(unresolved, name)
+		}
+		return nil
+	})
+
+	return unresolved
+}
+
+// render writes the nodes, calling missing for every rendered variable that
+// has no value nor default filter, rendering stops when missing fails. The
+// values of the variables go through escape, when set, after the filters.
+func render(sb *strings.Builder, nodes []node, data Data, escape func(string) string, missing func(name string) error) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *textNode:
+			sb.WriteString(n.text)
+		case *variableNode:
+			value, ok := data.lookup(n.name)
+			if !ok && !n.hasDefault() {
+				if err := missing(n.name); err != nil {
+					return err
+				}
+			}
+
+			for _, call := range n.filters {
+				value = filters[call.name].apply(value, call.args)
+			}
+
+			if escape != nil {
+				value = escape(value)
+			}
+
+			sb.WriteString(value)
+		case *ifNode:
+			// conditionals are how templates deal with missing values, so
+			// they never fail in strict mode
+			branch := n.otherwise
+			if value, _ := data.lookup(n.condition); value != "" {
+				branch = n.then
+			}
+
+			if err := render(sb, branch, data, escape, missing); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *variableNode) hasDefault() bool {
+	for _, call := range n.filters {
+		if call.name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+type filter struct {
+	args  int
+	apply func(value string, args []string) string
+}
+
+var filters = map[string]filter{
+	"default": {args: 1, apply: func(value string, args []string) string {
+		if value == "" {
+			return args[0]
+		}
+		return value
+	}},
+	"upper": {apply: func(value string, _ []string) string {
+		return strings.ToUpper(value)
+	}},
+	"lower": {apply: func(value string, _ []string) string {
+		return strings.ToLower(value)
+	}},
+	"capitalize": {apply: func(value string, _ []string) string {
+		r, size := utf8.DecodeRuneInString(value)
+		if size == 0 {
+			return value
+		}
+		return string(unicode.ToUpper(r)) + value[size:]
+	}},
+	"trim": {apply: func(value string, _ []string) string {
+		return strings.TrimSpace(value)
+	}},
+}
+
+func validateFilter(call filterCall) error {
+	f, ok := filters[call.name]
+	if !ok {
+		return fmt.Errorf("unknown filter %q", call.name)
+	}
+
+	if len(call.args) != f.args {
+		return fmt.Errorf("filter %q takes %d argument(s)", call.name, f.args)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package templating_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Render(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := templating.Parse(`Hi {{contact.firstName | default "there" | capitalize}}, {{#if contact.company}}welcome {{contact.company | upper}}{{else}}welcome{{/if}} to {{sequence.product}}!`)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		res, err := tmpl.Render(templating.Data{
+			"contact":  {"firstName": "ana", "company": "Acme"},
+			"sequence": {"product": "Mailbox"},
+		}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi Ana, welcome ACME to Mailbox!", res)
+	})
+
+	t.Run("success using defaults and else branches", func(t *testing.T) {
+		res, err := tmpl.Render(templating.Data{
+			"contact":  {"firstName": ""},
+			"sequence": {"product": "Mailbox"},
+		}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi There, welcome to Mailbox!", res)
+	})
+
+	t.Run("success rendering missing variables as empty when not strict", func(t *testing.T) {
+		res, err := tmpl.Render(templating.Data{}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi There, welcome to !", res)
+	})
+
+	t.Run("should return error when variable is missing in strict mode", func(t *testing.T) {
+		res, err := tmpl.Render(templating.Data{"contact": {"firstName": "Ana"}}, true)
+		assert.ErrorIs(t, err, templating.ErrMissingVariable)
+		assert.EqualError(t, err, "missing template variable: sequence.product")
+		assert.Empty(t, res)
+	})
+}
+
+func TestTemplate_RenderHTML(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := templating.Parse(`<p>Hi {{contact.firstName | default "there"}}</p><a href="{{contact.url}}">{{sequence.product}}</a>`)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		res, err := tmpl.RenderHTML(templating.Data{
+			"contact":  {"firstName": "Ana", "url": "https://example.com?a=1&b=2"},
+			"sequence": {"product": "Mailbox"},
+		}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, `<p>Hi Ana</p><a href="https://example.com?a=1&amp;b=2">Mailbox</a>`, res)
+	})
+
+	t.Run("success escaping markup in the values", func(t *testing.T) {
+		res, err := tmpl.RenderHTML(templating.Data{
+			"contact":  {"firstName": "<script>alert(1)</script>", "url": `" onclick="alert(1)`},
+			"sequence": {"product": "<img src=x onerror=alert(1)>"},
+		}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, `<p>Hi &lt;script&gt;alert(1)&lt;/script&gt;</p><a href="&#34; onclick=&#34;alert(1)">&lt;img src=x onerror=alert(1)&gt;</a>`, res)
+	})
+}
+
+func TestTemplate_Unresolved(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := templating.Parse(`{{contact.lastName}} {{contact.firstName | default "there"}} {{#if contact.company}}{{contact.role}}{{else}}{{sequence.product}}{{/if}} {{contact.lastName}}`)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		assert.Equal(t, []string{"contact.lastName", "sequence.product"}, tmpl.Unresolved(templating.Data{}))
+	})
+
+	t.Run("success following the rendered branch", func(t *testing.T) {
+		assert.Equal(t, []string{"contact.role"}, tmpl.Unresolved(templating.Data{"contact": {"lastName": "Silva", "company": "Acme"}}))
+	})
+}
@@ -0,0 +1,296 @@
+// Package templating parses and renders the personalization tags of the step
+// subjects and contents, e.g.
+//
+//	Hi {{contact.firstName | default "there"}},
+//	{{#if contact.company}}how are things at {{contact.company}}?{{else}}how are you?{{/if}}
+//
+// Variables are always namespace.name, where the namespace is contact or
+// sequence. Conditionals check whether the variable has a non-empty value.
+// Filter arguments are plain text, they cannot contain markup.
+package templating
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	NamespaceContact  = "contact"
+	NamespaceSequence = "sequence"
+)
+
+var namespaces = []string{NamespaceContact, NamespaceSequence}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Template is a parsed step subject or content, safe for concurrent use.
+type Template struct {
+	nodes []node
+}
+
+type node interface{}
+
+type textNode struct {
+	text string
+}
+
+type variableNode struct {
+	name    string
+	filters []filterCall
+}
+
+type ifNode struct {
+	condition string
+	then      []node
+	otherwise []node
+}
+
+type filterCall struct {
+	name string
+	args []string
+}
+
+// Parse validates the syntax of src, including the namespaces of the variables
+// and the filters, and returns the template ready to be rendered.
+func Parse(src string) (*Template, error) {
+	p := &parser{src: src}
+
+	nodes, end, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if end != "" {
+		return nil, fmt.Errorf("unexpected {{%s}} at position %d", end, p.tagStart)
+	}
+
+	return &Template{nodes: nodes}, nil
+}
+
+// IsValidName reports whether name can be used as a variable name.
+func IsValidName(name string) bool {
+	return identifierRegex.MatchString(name)
+}
+
+// Variables returns the variables referenced by the template, sorted and
+// without duplicates.
+func (t *Template) Variables() []string {
+	variables := collectVariables(t.nodes, nil)
+
+	slices.Sort(variables)
+
+	return slices.Compact(variables)
+}
+
+func collectVariables(nodes []node, variables []string) []string {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *variableNode:
+			variables = append(variables, n.name)
+		case *ifNode:
+			variables = append(variables, n.condition)
+			variables = collectVariables(n.then, variables)
+			variables = collectVariables(n.otherwise, variables)
+		}
+	}
+	return variables
+}
+
+type parser struct {
+	src      string
+	pos      int
+	tagStart int
+}
+
+// parseNodes reads nodes until the end of the source or a tag closing the
+// current block, which is returned as end ("else" or "/if").
+func (p *parser) parseNodes() ([]node, string, error) {
+	nodes := make([]node, 0)
+
+	for p.pos < len(p.src) {
+		open := strings.Index(p.src[p.pos:], "{{")
+		if open < 0 {
+			nodes = append(nodes, &textNode{text: p.src[p.pos:]})
+			p.pos = len(p.src)
+			break
+		}
+
+		if open > 0 {
+			nodes = append(nodes, &textNode{text: p.src[p.pos : p.pos+open]})
+		}
+
+		p.tagStart = p.pos + open
+		p.pos = p.tagStart + 2
+
+		tokens, err := p.lexTag()
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(tokens) == 0 {
+			return nil, "", fmt.Errorf("empty tag at position %d", p.tagStart)
+		}
+
+		switch tokens[0] {
+		case "else", "/if":
+			if len(tokens) > 1 {
+				return nil, "", fmt.Errorf("unexpected %q after {{%s}} at position %d", tokens[1], tokens[0], p.tagStart)
+			}
+			return nodes, tokens[0], nil
+		case "#if":
+			n, err := p.parseIf(tokens)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, n)
+		default:
+			n, err := p.parseVariable(tokens)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes, "", nil
+}
+
+func (p *parser) parseIf(tokens []string) (*ifNode, error) {
+	start := p.tagStart
+
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("{{#if}} takes exactly one variable at position %d", start)
+	}
+
+	if err := validateVariable(tokens[1]); err != nil {
+		return nil, fmt.Errorf("%w at position %d", err, start)
+	}
+
+	n := &ifNode{condition: tokens[1]}
+
+	then, end, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	n.then = then
+
+	if end == "else" {
+		otherwise, elseEnd, err := p.parseNodes()
+		if err != nil {
+			return nil, err
+		}
+		if elseEnd == "else" {
+			return nil, fmt.Errorf("unexpected {{else}} at position %d", p.tagStart)
+		}
+		n.otherwise = otherwise
+		end = elseEnd
+	}
+
+	if end != "/if" {
+		return nil, fmt.Errorf("{{#if}} at position %d is not closed with {{/if}}", start)
+	}
+
+	return n, nil
+}
+
+func (p *parser) parseVariable(tokens []string) (*variableNode, error) {
+	if err := validateVariable(tokens[0]); err != nil {
+		return nil, fmt.Errorf("%w at position %d", err, p.tagStart)
+	}
+
+	n := &variableNode{name: tokens[0]}
+
+	rest := tokens[1:]
+	for len(rest) > 0 {
+		if rest[0] != "|" || len(rest) < 2 {
+			return nil, fmt.Errorf("expected a filter after %q at position %d", tokens[0], p.tagStart)
+		}
+
+		call := filterCall{name: rest[1]}
+		rest = rest[2:]
+
+		for len(rest) > 0 && rest[0] != "|" {
+			arg, ok := unquote(rest[0])
+			if !ok {
+				return nil, fmt.Errorf("filter arguments must be quoted strings at position %d", p.tagStart)
+			}
+			if strings.ContainsAny(arg, "<>") {
+				return nil, fmt.Errorf("filter arguments cannot contain markup at position %d", p.tagStart)
+			}
+			call.args = append(call.args, arg)
+			rest = rest[1:]
+		}
+
+		if err := validateFilter(call); err != nil {
+			return nil, fmt.Errorf("%w at position %d", err, p.tagStart)
+		}
+
+		n.filters = append(n.filters, call)
+	}
+
+	return n, nil
+}
+
+// lexTag splits the content of the tag starting at the current position into
+// words, quoted strings and pipes, leaving the position after the closing braces.
+func (p *parser) lexTag() ([]string, error) {
+	tokens := make([]string, 0)
+
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "}}"):
+			p.pos += 2
+			return tokens, nil
+		case c == '|':
+			tokens = append(tokens, "|")
+			p.pos++
+		case c == '"':
+			end := p.pos + 1
+			for end < len(p.src) && p.src[end] != '"' {
+				if p.src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(p.src) {
+				return nil, fmt.Errorf("unterminated string at position %d", p.pos)
+			}
+			tokens = append(tokens, p.src[p.pos:end+1])
+			p.pos = end + 1
+		default:
+			end := p.pos
+			for end < len(p.src) && !strings.ContainsRune(" \t\r\n|\"", rune(p.src[end])) && !strings.HasPrefix(p.src[end:], "}}") {
+				end++
+			}
+			tokens = append(tokens, p.src[p.pos:end])
+			p.pos = end
+		}
+	}
+
+	return nil, fmt.Errorf("tag at position %d is not closed", p.tagStart)
+}
+
+func validateVariable(name string) error {
+	namespace, field, ok := strings.Cut(name, ".")
+	if !ok || !IsValidName(namespace) || !IsValidName(field) {
+		return fmt.Errorf("invalid variable %q, variables must be namespace.name", name)
+	}
+
+	if !slices.Contains(namespaces, namespace) {
+		return fmt.Errorf("unknown variable namespace %q", namespace)
+	}
+
+	return nil
+}
+
+func unquote(token string) (string, bool) {
+	if len(token) < 2 || token[0] != '"' || token[len(token)-1] != '"' {
+		return "", false
+	}
+
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(token[1 : len(token)-1]), true
+}
@@ -0,0 +1,99 @@
+package templating_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/templating"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		tmpl, err := templating.Parse(`Hi {{ contact.firstName | default "there" | capitalize }}, {{#if contact.company}}welcome {{contact.company}}{{else}}welcome{{/if}} to {{sequence.product}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"contact.company", "contact.firstName", "sequence.product"}, tmpl.Variables())
+	})
+
+	t.Run("success without tags", func(t *testing.T) {
+		tmpl, err := templating.Parse("Plain text { with } braces")
+		assert.NoError(t, err)
+		assert.Empty(t, tmpl.Variables())
+	})
+
+	table := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "should return error when tag is not closed",
+			src:  "Hi {{contact.firstName",
+			err:  "tag at position 3 is not closed",
+		},
+		{
+			name: "should return error when tag is empty",
+			src:  "Hi {{ }}",
+			err:  "empty tag at position 3",
+		},
+		{
+			name: "should return error when variable has no namespace",
+			src:  "Hi {{firstName}}",
+			err:  `invalid variable "firstName", variables must be namespace.name at position 3`,
+		},
+		{
+			name: "should return error when namespace is unknown",
+			src:  "Hi {{user.firstName}}",
+			err:  `unknown variable namespace "user" at position 3`,
+		},
+		{
+			name: "should return error when filter is unknown",
+			src:  "Hi {{contact.firstName | reverse}}",
+			err:  `unknown filter "reverse" at position 3`,
+		},
+		{
+			name: "should return error when default has no argument",
+			src:  "Hi {{contact.firstName | default}}",
+			err:  `filter "default" takes 1 argument(s) at position 3`,
+		},
+		{
+			name: "should return error when filter argument is not quoted",
+			src:  "Hi {{contact.firstName | default there}}",
+			err:  "filter arguments must be quoted strings at position 3",
+		},
+		{
+			name: "should return error when filter argument has markup",
+			src:  `Hi {{contact.firstName | default "<img src=x onerror=alert(1)>"}}`,
+			err:  "filter arguments cannot contain markup at position 3",
+		},
+		{
+			name: "should return error when string is not terminated",
+			src:  `Hi {{contact.firstName | default "there}}`,
+			err:  "unterminated string at position 33",
+		},
+		{
+			name: "should return error when if is not closed",
+			src:  "{{#if contact.company}}Hi",
+			err:  "{{#if}} at position 0 is not closed with {{/if}}",
+		},
+		{
+			name: "should return error when if closes without opening",
+			src:  "Hi{{/if}}",
+			err:  "unexpected {{/if}} at position 2",
+		},
+		{
+			name: "should return error when if has two else",
+			src:  "{{#if contact.company}}a{{else}}b{{else}}c{{/if}}",
+			err:  "unexpected {{else}} at position 33",
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := templating.Parse(tc.src)
+			assert.Nil(t, tmpl)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
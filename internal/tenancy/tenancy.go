@@ -0,0 +1,18 @@
+package tenancy
+
+import "context"
+
+type workspaceKey struct{}
+
+// WithWorkspace returns a copy of ctx scoped to the workspace with the given
+// internal id, every query issued with it only sees the rows of that workspace.
+func WithWorkspace(ctx context.Context, workspaceID int32) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspaceID)
+}
+
+// WorkspaceID returns the internal id of the workspace ctx is scoped to, or 0
+// when it is not scoped, which matches no rows.
+func WorkspaceID(ctx context.Context) int32 {
+	id, _ := ctx.Value(workspaceKey{}).(int32)
+	return id
+}
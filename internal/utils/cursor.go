@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Cursor points to the row a keyset page starts after, or before when Backward
+// is set. Value holds the sort key of that row, formatted as text, and ID breaks
+// the ties between rows with the same key. Clients only see it as an opaque token.
+type Cursor struct {
+	Sort     string `json:"s"`
+	Value    string `json:"v"`
+	ID       int32  `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("cursor is not base64: %w", err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("cursor is not json: %w", err)
+	}
+
+	if c.ID <= 0 || c.Sort == "" {
+		return nil, errors.New("cursor is missing its id or sort")
+	}
+
+	return &c, nil
+}
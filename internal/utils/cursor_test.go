@@ -0,0 +1,46 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/murilo-bracero/sequence-technical-test/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		token := utils.EncodeCursor(utils.Cursor{Sort: "name", Value: "My Sequence", ID: 42, Backward: true})
+
+		cursor, err := utils.DecodeCursor(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "name", cursor.Sort)
+		assert.Equal(t, "My Sequence", cursor.Value)
+		assert.Equal(t, int32(42), cursor.ID)
+		assert.True(t, cursor.Backward)
+	})
+
+	table := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "not base64",
+			token: "not a cursor!",
+		},
+		{
+			name:  "not json",
+			token: "bm90IGpzb24",
+		},
+		{
+			name:  "missing fields",
+			token: "e30",
+		},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := utils.DecodeCursor(tc.token)
+			assert.Error(t, err)
+		})
+	}
+}